@@ -23,8 +23,10 @@ func LoadFlagsFromAltSources(cmd *cobra.Command, args []string) {
 	if hasURLInEnvButNotFlag(cmd) {
 		_ = flags.Set("url", viper.GetViper().GetString("SHOUTRRR_URL"))
 
-		// If the URL has been set in ENV, default the message to read from stdin.
-		if msg, _ := flags.GetString("message"); msg == "" {
+		// If the URL has been set in ENV, default the message to read from stdin,
+		// unless the message is read as part of the JSON input.
+		jsonInput, _ := flags.GetBool("json")
+		if msg, _ := flags.GetString("message"); msg == "" && !jsonInput {
 			_ = flags.Set("message", "-")
 		}
 	}
@@ -0,0 +1,89 @@
+package format
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// ConfigPropNames returns the names of the config props, in the order of the config fields.
+// Props are named by their primary query key, or the lowercase field name for URL only fields,
+// matching the property names of the JSON Schema renderer.
+func ConfigPropNames(config types.ServiceConfig) []string {
+	nodes := GetConfigFormat(config).Items
+	names := make([]string, 0, len(nodes))
+
+	for _, node := range nodes {
+		names = append(names, propName(node.Field()))
+	}
+
+	return names
+}
+
+// GetConfigProp returns the value of the named config prop, formatted as it is in the service URL.
+// Any of the query keys of a field can be used, as well as its field name.
+func GetConfigProp(config types.ServiceConfig, name string) (string, error) {
+	field, err := findConfigProp(config, name)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := GetConfigFieldString(reflect.Indirect(reflect.ValueOf(config)), *field)
+	if err != nil {
+		return "", &PropError{Key: strings.ToLower(name), Err: err}
+	}
+
+	return value, nil
+}
+
+// SetConfigProp sets the value of the named config prop, parsing it as it would be from the service URL.
+// Any of the query keys of a field can be used, as well as its field name.
+func SetConfigProp(config types.ServiceConfig, name string, value string) error {
+	field, err := findConfigProp(config, name)
+	if err != nil {
+		return err
+	}
+
+	valid, err := SetConfigField(reflect.Indirect(reflect.ValueOf(config)), *field, value)
+	if !valid && err == nil {
+		err = ErrInvalidValueForType
+	}
+
+	if err != nil {
+		return &PropError{Key: strings.ToLower(name), Err: err}
+	}
+
+	return nil
+}
+
+// findConfigProp returns the info of the config field with a query key or field name matching the prop name.
+// Query keys take precedence over field names.
+func findConfigProp(config types.ServiceConfig, name string) (*FieldInfo, error) {
+	nodes := GetConfigFormat(config).Items
+
+	for _, node := range nodes {
+		for _, key := range node.Field().Keys {
+			if strings.EqualFold(key, name) {
+				return node.Field(), nil
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		if strings.EqualFold(node.Field().Name, name) {
+			return node.Field(), nil
+		}
+	}
+
+	return nil, unknownKeyError(name, ConfigPropNames(config), "props")
+}
+
+// propName returns the name of the config prop for the field.
+func propName(field *FieldInfo) string {
+	if len(field.Keys) > 0 {
+		return field.Keys[0]
+	}
+
+	return strings.ToLower(field.Name)
+}
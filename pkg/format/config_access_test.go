@@ -0,0 +1,63 @@
+package format
+
+import (
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("Config prop access", func() {
+	var config *testJSONStruct
+
+	ginkgo.BeforeEach(func() {
+		config = &testJSONStruct{}
+		pkr := NewPropKeyResolver(config)
+		gomega.Expect(pkr.SetDefaultProps(config)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("should name the props by their primary key or lowercase field name", func() {
+		gomega.Expect(ConfigPropNames(config)).To(gomega.Equal([]string{
+			"color", "count", "enabled", "host", "tags", "timeout", "token",
+		}))
+	})
+
+	ginkgo.It("should get the prop values as they are formatted in the URL", func() {
+		gomega.Expect(GetConfigProp(config, "color")).To(gomega.Equal("0xff00ff"))
+		gomega.Expect(GetConfigProp(config, "c")).To(gomega.Equal("3"))
+		gomega.Expect(GetConfigProp(config, "Enabled")).To(gomega.Equal("Yes"))
+		gomega.Expect(GetConfigProp(config, "timeout")).To(gomega.Equal("10s"))
+	})
+
+	ginkgo.It("should set the props by key, alias or field name", func() {
+		gomega.Expect(SetConfigProp(config, "c", "5")).To(gomega.Succeed())
+		gomega.Expect(SetConfigProp(config, "host", "example.com")).To(gomega.Succeed())
+		gomega.Expect(SetConfigProp(config, "TIMEOUT", "1m")).To(gomega.Succeed())
+		gomega.Expect(SetConfigProp(config, "tags", "x;y;z")).To(gomega.Succeed())
+
+		gomega.Expect(config.Count).To(gomega.Equal(5))
+		gomega.Expect(config.Host).To(gomega.Equal("example.com"))
+		gomega.Expect(config.Timeout).To(gomega.Equal(time.Minute))
+		gomega.Expect(config.Tags).To(gomega.Equal([]string{"x", "y", "z"}))
+	})
+
+	ginkgo.It("should return a prop error for invalid values", func() {
+		err := SetConfigProp(config, "count", "many")
+
+		var propErr *PropError
+
+		gomega.Expect(errors.As(err, &propErr)).To(gomega.BeTrue())
+		gomega.Expect(propErr.Key).To(gomega.Equal("count"))
+		gomega.Expect(config.Count).To(gomega.Equal(3))
+	})
+
+	ginkgo.It("should suggest the closest prop name for unknown props", func() {
+		err := SetConfigProp(config, "tokn", "secret")
+		gomega.Expect(err).To(gomega.MatchError(ErrInvalidConfigKey))
+		gomega.Expect(err.Error()).To(gomega.ContainSubstring(`did you mean "token"?`))
+
+		_, err = GetConfigProp(config, "unknown")
+		gomega.Expect(err).To(gomega.MatchError(ErrInvalidConfigKey))
+	})
+})
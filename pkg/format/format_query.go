@@ -33,8 +33,9 @@ func BuildQueryWithCustomFields(cqr types.ConfigQueryResolver, query url.Values)
 			continue
 		}
 
+		// Only values that are written exactly as their default are omitted, keeping the built URLs stable
 		value, err := cqr.Get(key)
-		if err != nil || isPkr && pkr.IsDefault(key, value) {
+		if err != nil || isPkr && pkr.keyFields[key].DefaultValue == value {
 			continue
 		}
 
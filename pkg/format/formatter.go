@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util"
@@ -35,6 +36,7 @@ var (
 	ErrUnexpectedIntKind     = errors.New("unexpected int kind")
 	ErrParseIntFailed        = errors.New("failed to parse integer")
 	ErrParseUintFailed       = errors.New("failed to parse unsigned integer")
+	ErrParseDurationFailed   = errors.New("failed to parse duration")
 )
 
 // GetServiceConfig extracts the inner config from a service.
@@ -126,11 +128,22 @@ func SetConfigField(config reflect.Value, field FieldInfo, inputValue string) (b
 
 // setIntField handles integer field setting.
 func setIntField(configField reflect.Value, field FieldInfo, inputValue string) (bool, error) {
+	if field.Type == durationType {
+		duration, err := time.ParseDuration(inputValue)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrParseDurationFailed, err)
+		}
+
+		configField.SetInt(int64(duration))
+
+		return true, nil
+	}
+
 	number, base := util.StripNumberPrefix(inputValue)
 
 	value, err := strconv.ParseInt(number, base, field.Type.Bits())
 	if err != nil {
-		return false, fmt.Errorf("%w: %w", ErrParseIntFailed, err)
+		return false, fmt.Errorf("%w: %w%s", ErrParseIntFailed, err, baseHint(field, inputValue))
 	}
 
 	configField.SetInt(value)
@@ -144,7 +157,7 @@ func setUintField(configField reflect.Value, field FieldInfo, inputValue string)
 
 	value, err := strconv.ParseUint(number, base, field.Type.Bits())
 	if err != nil {
-		return false, fmt.Errorf("%w: %w", ErrParseUintFailed, err)
+		return false, fmt.Errorf("%w: %w%s", ErrParseUintFailed, err, baseHint(field, inputValue))
 	}
 
 	configField.SetUint(value)
@@ -152,6 +165,16 @@ func setUintField(configField reflect.Value, field FieldInfo, inputValue string)
 	return true, nil
 }
 
+// baseHint returns a hint on how to write numbers for fields that are not in decimal, when the value is missing a
+// base prefix. Without a prefix, the value is parsed as a decimal number.
+func baseHint(field FieldInfo, inputValue string) string {
+	if field.Base != BaseHexLen || strings.HasPrefix(inputValue, "#") || strings.HasPrefix(inputValue, "0x") {
+		return ""
+	}
+
+	return fmt.Sprintf(" (the value is a base %d number, prefix it with 0x or #)", field.Base)
+}
+
 // setBoolField handles boolean field setting.
 func setBoolField(configField reflect.Value, inputValue string) (bool, error) {
 	value, ok := ParseBool(inputValue, false)
@@ -170,6 +193,12 @@ func setMapField(configField reflect.Value, field FieldInfo, inputValue string)
 		return false, ErrUnsupportedFieldKey
 	}
 
+	if inputValue == "" {
+		configField.Set(reflect.Zero(field.Type))
+
+		return true, nil
+	}
+
 	mapValue := reflect.MakeMap(field.Type)
 
 	pairs := strings.Split(inputValue, ",")
@@ -246,6 +275,12 @@ func setSliceOrArrayValues(
 	}
 
 	if baseType.Kind() == reflect.Struct {
+		if len(values) == 1 && values[0] == "" {
+			configField.Set(reflect.Zero(field.Type))
+
+			return true, nil
+		}
+
 		slice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(values))
 
 		for _, v := range values {
@@ -3,6 +3,7 @@ package format
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
@@ -54,6 +55,23 @@ var _ = ginkgo.Describe("SetConfigField", func() {
 				})
 			})
 		})
+		ginkgo.When("setting a duration value", func() {
+			durationField := FieldInfo{Name: "Timeout", Type: reflect.TypeFor[time.Duration]()}
+
+			ginkgo.It("should parse it as a duration", func() {
+				config := &struct{ Timeout time.Duration }{}
+				valid, err := SetConfigField(reflect.ValueOf(config).Elem(), durationField, "1m30s")
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(valid).To(gomega.BeTrue())
+				gomega.Expect(config.Timeout).To(gomega.Equal(90 * time.Second))
+			})
+			ginkgo.It("should return an error for plain numbers", func() {
+				config := &struct{ Timeout time.Duration }{}
+				valid, err := SetConfigField(reflect.ValueOf(config).Elem(), durationField, "90")
+				gomega.Expect(err).To(gomega.MatchError(ErrParseDurationFailed))
+				gomega.Expect(valid).To(gomega.BeFalse())
+			})
+		})
 		ginkgo.When("setting an unsigned integer value", func() {
 			ginkgo.When("the value is valid", func() {
 				ginkgo.It("should set it", func() {
@@ -161,6 +179,15 @@ var _ = ginkgo.Describe("SetConfigField", func() {
 					gomega.Expect(ts.SubPropSlice).To(gomega.HaveLen(2))
 				})
 			})
+			ginkgo.When("the value is empty", func() {
+				ginkgo.It("should clear it", func() {
+					ts.SubPropSlice = []subPropStruct{{}}
+					valid, err := SetConfigField(tv, *nodeMap["SubPropSlice"].Field(), "")
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+					gomega.Expect(valid).To(gomega.BeTrue())
+					gomega.Expect(ts.SubPropSlice).To(gomega.BeEmpty())
+				})
+			})
 		})
 		ginkgo.When("setting a struct pointer slice value", func() {
 			ginkgo.When("the value is valid", func() {
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util"
@@ -303,6 +304,10 @@ func getValueNodeValue(fieldValue reflect.Value, fieldInfo *FieldInfo) (string,
 
 		return val, NumberToken
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fieldValue.Type() == durationType {
+			return time.Duration(fieldValue.Int()).String(), NumberToken
+		}
+
 		return strconv.FormatInt(fieldValue.Int(), base), NumberToken
 	case reflect.String:
 		return fieldValue.String(), StringToken
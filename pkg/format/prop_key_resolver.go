@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 var (
@@ -15,6 +16,52 @@ var (
 	ErrInvalidValueForType = errors.New("invalid value for type")
 )
 
+// PropError is returned when a config prop could not be set from its value, or when its key is not valid,
+// identifying the key of the prop.
+type PropError struct {
+	Key        string
+	Suggestion string // Suggestion is the closest valid key, when the key is not valid.
+	Err        error
+}
+
+func (e *PropError) Error() string {
+	if errors.Is(e.Err, ErrInvalidConfigKey) {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("invalid value for %v: %v", e.Key, e.Err)
+}
+
+func (e *PropError) Unwrap() error {
+	return e.Err
+}
+
+// Summary describes the error using the key and the suggestion alone, leaving out the value and the
+// error it caused, as these may contain secrets.
+func (e *PropError) Summary() string {
+	switch {
+	case !errors.Is(e.Err, ErrInvalidConfigKey):
+		return "invalid value for " + e.Key
+	case e.Suggestion != "":
+		return fmt.Sprintf("%v: %v, did you mean %q?", ErrInvalidConfigKey, e.Key, e.Suggestion)
+	default:
+		return fmt.Sprintf("%v: %v", ErrInvalidConfigKey, e.Key)
+	}
+}
+
+// unknownKeyError creates a PropError for a key that is not one of the valid keys, suggesting the closest one.
+// The kind names what the keys are in the error message.
+func unknownKeyError(key string, validKeys []string, kind string) *PropError {
+	suggestion, found := util.ClosestMatch(strings.ToLower(key), validKeys)
+	if !found {
+		return &PropError{Key: key, Err: fmt.Errorf("%w: %v (valid %s: %v)", ErrInvalidConfigKey, key, kind, validKeys)}
+	}
+
+	err := fmt.Errorf("%w: %v, did you mean %q? (valid %s: %v)", ErrInvalidConfigKey, key, suggestion, kind, validKeys)
+
+	return &PropError{Key: key, Suggestion: suggestion, Err: err}
+}
+
 // PropKeyResolver implements the ConfigQueryResolver interface for services that uses key tags for query props.
 type PropKeyResolver struct {
 	confValue reflect.Value
@@ -83,13 +130,17 @@ func (pkr *PropKeyResolver) set(target reflect.Value, key string, value string)
 	if field, found := pkr.keyFields[strings.ToLower(key)]; found {
 		valid, err := SetConfigField(target, field, value)
 		if !valid && err == nil {
-			return ErrInvalidValueForType
+			err = ErrInvalidValueForType
 		}
 
-		return err
+		if err != nil {
+			return &PropError{Key: strings.ToLower(key), Err: err}
+		}
+
+		return nil
 	}
 
-	return fmt.Errorf("%w: %v (valid keys: %v)", ErrInvalidConfigKey, key, pkr.keys)
+	return unknownKeyError(key, pkr.keys, "keys")
 }
 
 // UpdateConfigFromParams mutates the provided config, updating the values from its corresponding params.
@@ -171,6 +222,39 @@ func configValue(config types.ServiceConfig) reflect.Value {
 }
 
 // IsDefault returns whether the specified key value is the default value.
+// Values are compared as they are formatted in the URL, making "yes" the same as a default of "Yes".
 func (pkr *PropKeyResolver) IsDefault(key string, value string) bool {
-	return pkr.keyFields[key].DefaultValue == value
+	field, found := pkr.keyFields[strings.ToLower(key)]
+	if !found {
+		return false
+	}
+
+	if field.DefaultValue == value {
+		return true
+	}
+
+	defaultValue, err := pkr.formatValue(field, field.DefaultValue)
+	if err != nil {
+		return false
+	}
+
+	formattedValue, err := pkr.formatValue(field, value)
+
+	return err == nil && formattedValue == defaultValue
+}
+
+// formatValue returns the value as it is formatted in the URL, after setting it on a new config.
+func (pkr *PropKeyResolver) formatValue(field FieldInfo, value string) (string, error) {
+	target := reflect.New(pkr.confValue.Type()).Elem()
+
+	valid, err := SetConfigField(target, field, value)
+	if !valid && err == nil {
+		err = ErrInvalidValueForType
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return GetConfigFieldString(target, field)
 }
@@ -1,6 +1,8 @@
 package format
 
 import (
+	"errors"
+
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 
@@ -38,6 +40,27 @@ var _ = ginkgo.Describe("Prop Key Resolver", func() {
 				gomega.Expect(ts.Signed).To(gomega.Equal(1))
 				gomega.Expect(ts.Str).To(gomega.Equal("val"))
 			})
+			ginkgo.It("should suggest the closest key", func() {
+				err := pkr.UpdateConfigFromParams(nil, &types.Params{"sgned": "1"})
+				gomega.Expect(err).To(gomega.MatchError(ErrInvalidConfigKey))
+				gomega.Expect(err.Error()).To(gomega.ContainSubstring(`did you mean "signed"?`))
+
+				var propErr *PropError
+				gomega.Expect(errors.As(err, &propErr)).To(gomega.BeTrue())
+				gomega.Expect(propErr.Suggestion).To(gomega.Equal("signed"))
+				gomega.Expect(propErr.Summary()).To(gomega.Equal(`not a valid config key: sgned, did you mean "signed"?`))
+			})
+		})
+		ginkgo.When("a param has an invalid value", func() {
+			ginkgo.It("should report the key of the prop", func() {
+				err := pkr.UpdateConfigFromParams(nil, &types.Params{"signed": "NaN"})
+
+				var propErr *PropError
+				gomega.Expect(errors.As(err, &propErr)).To(gomega.BeTrue())
+				gomega.Expect(propErr.Key).To(gomega.Equal("signed"))
+				gomega.Expect(err).To(gomega.MatchError(ErrParseIntFailed))
+				gomega.Expect(propErr.Summary()).To(gomega.Equal("invalid value for signed"))
+			})
 		})
 	})
 	ginkgo.Describe("Setting default props", func() {
@@ -55,4 +78,15 @@ var _ = ginkgo.Describe("Prop Key Resolver", func() {
 			})
 		})
 	})
+	ginkgo.Describe("Checking for default values", func() {
+		ginkgo.It("should compare the values as they are formatted", func() {
+			gomega.Expect(pkr.IsDefault("str", "notempty")).To(gomega.BeTrue())
+			gomega.Expect(pkr.IsDefault("signed", "00")).To(gomega.BeTrue())
+			gomega.Expect(pkr.IsDefault("signed", "1")).To(gomega.BeFalse())
+		})
+		ginkgo.It("should not treat invalid values or unknown keys as default", func() {
+			gomega.Expect(pkr.IsDefault("signed", "NaN")).To(gomega.BeFalse())
+			gomega.Expect(pkr.IsDefault("unknown", "")).To(gomega.BeFalse())
+		})
+	})
 })
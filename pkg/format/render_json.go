@@ -0,0 +1,257 @@
+package format
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
+)
+
+// JSONSchemaDialect is the JSON Schema version used by the JSONSchemaTreeRenderer.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns for values that are strings in the schema, but have a specific format in the service URLs.
+const (
+	hexNumberPattern = `^(0x|#)?[0-9a-fA-F]+$`
+	durationPattern  = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+)
+
+// durationType is the type of duration fields, which are integers that are written as duration strings.
+var durationType = reflect.TypeFor[time.Duration]()
+
+// JSONTreeRenderer renders a ContainerNode tree into a JSON description of the service config fields.
+type JSONTreeRenderer struct {
+	Indent string
+}
+
+// JSONSchemaTreeRenderer renders a ContainerNode tree into a JSON Schema of the service config props.
+// The props are named by their primary query key, or the lowercase field name for URL only fields.
+type JSONSchemaTreeRenderer struct {
+	Indent string
+}
+
+// jsonServiceInfo is the service description written by the JSONTreeRenderer.
+type jsonServiceInfo struct {
+	Scheme string          `json:"scheme"`
+	Fields []jsonFieldInfo `json:"fields"`
+}
+
+// jsonFieldInfo is the field description written by the JSONTreeRenderer.
+type jsonFieldInfo struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	GoType        string   `json:"goType"`
+	Description   string   `json:"description,omitempty"`
+	Default       string   `json:"default,omitempty"`
+	Required      bool     `json:"required"`
+	URLParts      []string `json:"urlParts,omitempty"`
+	Key           string   `json:"key,omitempty"`
+	Aliases       []string `json:"aliases,omitempty"`
+	Enum          []string `json:"enum,omitempty"`
+	Base          int      `json:"base,omitempty"`
+	ItemSeparator string   `json:"itemSeparator,omitempty"`
+}
+
+// jsonSchema is the subset of JSON Schema keywords used by the JSONSchemaTreeRenderer.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Field                string                 `json:"x-shoutrrr-field,omitempty"`
+	URLParts             []string               `json:"x-shoutrrr-url-parts,omitempty"`
+	Aliases              []string               `json:"x-shoutrrr-aliases,omitempty"`
+}
+
+// RenderTree renders a ContainerNode tree into a JSON description of the service config fields.
+func (r JSONTreeRenderer) RenderTree(root *ContainerNode, scheme string) string {
+	service := jsonServiceInfo{
+		Scheme: scheme,
+		Fields: make([]jsonFieldInfo, 0, len(root.Items)),
+	}
+
+	for _, node := range root.Items {
+		field := node.Field()
+		info := jsonFieldInfo{
+			Name:        field.Name,
+			Type:        schemaType(field.Type, field),
+			GoType:      field.Type.String(),
+			Description: field.Description,
+			Default:     field.DefaultValue,
+			Required:    field.Required,
+			URLParts:    urlPartNames(field),
+		}
+
+		if len(field.Keys) > 0 {
+			info.Key = field.Keys[0]
+			info.Aliases = field.Keys[1:]
+		}
+
+		if field.EnumFormatter != nil {
+			info.Enum = field.EnumFormatter.Names()
+		}
+
+		if isIntKind(field.Type.Kind()) && field.Type != durationType {
+			info.Base = field.Base
+		}
+
+		if kind := field.Type.Kind(); util.IsCollection(kind) || kind == reflect.Map {
+			info.ItemSeparator = string(field.ItemSeparator)
+		}
+
+		service.Fields = append(service.Fields, info)
+	}
+
+	return marshalIndent(service, r.Indent)
+}
+
+// RenderTree renders a ContainerNode tree into a JSON Schema of the service config props.
+func (r JSONSchemaTreeRenderer) RenderTree(root *ContainerNode, scheme string) string {
+	schema := jsonSchema{
+		Schema:               JSONSchemaDialect,
+		Title:                scheme,
+		Description:          "Config props of " + scheme + " service URLs",
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema, len(root.Items)),
+		AdditionalProperties: false,
+	}
+
+	for _, node := range root.Items {
+		field := node.Field()
+		name := propName(field)
+
+		property := fieldSchema(field.Type, field)
+		property.Description = field.Description
+		property.Default = schemaDefault(field)
+		property.Field = field.Name
+		property.URLParts = urlPartNames(field)
+
+		if len(field.Keys) > 0 {
+			property.Aliases = field.Keys[1:]
+		}
+
+		schema.Properties[name] = property
+
+		if field.Required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return marshalIndent(schema, r.Indent)
+}
+
+// fieldSchema returns the schema of a value of the given type, using the field for its format.
+func fieldSchema(fieldType reflect.Type, field *FieldInfo) *jsonSchema {
+	schema := &jsonSchema{Type: schemaType(fieldType, field)}
+
+	switch {
+	case fieldType == field.Type && field.EnumFormatter != nil:
+		schema.Enum = field.EnumFormatter.Names()
+	case fieldType == durationType:
+		schema.Pattern = durationPattern
+	case isIntKind(fieldType.Kind()) && field.Base == BaseHexLen:
+		schema.Pattern = hexNumberPattern
+	case util.IsCollection(fieldType.Kind()):
+		schema.Items = fieldSchema(fieldType.Elem(), field)
+	case fieldType.Kind() == reflect.Map:
+		schema.AdditionalProperties = fieldSchema(fieldType.Elem(), field)
+	}
+
+	return schema
+}
+
+// schemaType returns the JSON Schema type name for a value of the given type, using the field for its format.
+func schemaType(fieldType reflect.Type, field *FieldInfo) string {
+	if fieldType == field.Type && field.EnumFormatter != nil {
+		return "string"
+	}
+
+	switch kind := fieldType.Kind(); {
+	case fieldType == durationType:
+		return "string"
+	case isIntKind(kind):
+		// Numbers that are not decimal are prefixed in the URLs, and therefore not valid JSON numbers
+		if field.Base == BaseHexLen {
+			return "string"
+		}
+
+		return "integer"
+	case kind == reflect.Bool:
+		return "boolean"
+	case util.IsCollection(kind):
+		return "array"
+	case kind == reflect.Map:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// schemaDefault returns the default value of the field, converted to the type used in the schema.
+// Default values that cannot be converted are returned as strings.
+func schemaDefault(field *FieldInfo) any {
+	if len(field.DefaultValue) == 0 {
+		return nil
+	}
+
+	switch schemaType(field.Type, field) {
+	case "integer":
+		if value, err := strconv.ParseInt(field.DefaultValue, BaseDecimalLen, Int64BitSize); err == nil {
+			return value
+		}
+	case "boolean":
+		if value, ok := ParseBool(field.DefaultValue, false); ok {
+			return value
+		}
+	case "array":
+		return strings.Split(field.DefaultValue, string(field.ItemSeparator))
+	}
+
+	return field.DefaultValue
+}
+
+// urlPartNames returns the names of the URL parts that the field is serialized to, as used in the url tags.
+// Query props are not included, since they are identified by their keys.
+func urlPartNames(field *FieldInfo) []string {
+	names := make([]string, 0, len(field.URLParts))
+
+	for _, part := range field.URLParts {
+		switch {
+		case part == URLQuery:
+			continue
+		case part >= URLPath:
+			names = append(names, "path"+strconv.Itoa(int(part-URLPath)+1))
+		default:
+			names = append(names, strings.ToLower(part.String()))
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return names
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return util.IsSignedInt(kind) || util.IsUnsignedInt(kind)
+}
+
+func marshalIndent(value any, indent string) string {
+	bytes, err := json.MarshalIndent(value, "", indent)
+	if err != nil {
+		return `{"error":` + strconv.Quote(err.Error()) + `}`
+	}
+
+	return string(bytes)
+}
@@ -0,0 +1,111 @@
+package format
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// testJSONStruct has fields of the types that are formatted differently in the JSON renderers.
+type testJSONStruct struct {
+	testEnummer
+
+	Host    string        `desc:"Server host"  url:"host,port"`
+	Token   string        `                    url:"path2"`
+	Color   uint          `default:"0xff00ff"                    key:"color"   base:"16"`
+	Count   int           `default:"3"                           key:"count,c"`
+	Enabled bool          `default:"yes"                         key:"enabled"`
+	Tags    []string      `default:"a;b"                         key:"tags"    sep:";"`
+	Timeout time.Duration `default:"10s"                         key:"timeout"`
+}
+
+func (t *testJSONStruct) GetURL() *url.URL {
+	panic("not implemented")
+}
+
+func (t *testJSONStruct) SetURL(_ *url.URL) error {
+	panic("not implemented")
+}
+
+var _ = ginkgo.Describe("RenderJSON", func() {
+	ginkgo.It("should render the fields with their URL parts, keys and formats", func() {
+		actual := testRenderTree(JSONTreeRenderer{}, &testJSONStruct{})
+
+		gomega.Expect(actual).To(gomega.MatchJSON(`{
+			"scheme": "mock",
+			"fields": [
+				{"name": "Color", "type": "string", "goType": "uint", "default": "0xff00ff", "required": false,
+				 "key": "color", "base": 16},
+				{"name": "Count", "type": "integer", "goType": "int", "default": "3", "required": false,
+				 "key": "count", "aliases": ["c"], "base": 10},
+				{"name": "Enabled", "type": "boolean", "goType": "bool", "default": "yes", "required": false,
+				 "key": "enabled"},
+				{"name": "Host", "type": "string", "goType": "string", "description": "Server host", "required": true,
+				 "urlParts": ["host", "port"]},
+				{"name": "Tags", "type": "array", "goType": "[]string", "default": "a;b", "required": false,
+				 "key": "tags", "itemSeparator": ";"},
+				{"name": "Timeout", "type": "string", "goType": "time.Duration", "default": "10s", "required": false,
+				 "key": "timeout"},
+				{"name": "Token", "type": "string", "goType": "string", "required": true, "urlParts": ["path2"]}
+			]
+		}`))
+	})
+
+	ginkgo.It("should render the enum choices", func() {
+		actual := testRenderTree(JSONTreeRenderer{}, &testEnummer{})
+
+		gomega.Expect(actual).To(gomega.MatchJSON(`{
+			"scheme": "mock",
+			"fields": [
+				{"name": "Choice", "type": "string", "goType": "int", "default": "Maybe", "required": false,
+				 "key": "choice", "enum": ["Yes", "No", "Maybe"], "base": 10}
+			]
+		}`))
+	})
+})
+
+var _ = ginkgo.Describe("RenderJSONSchema", func() {
+	ginkgo.It("should render the props with their types, defaults and required props", func() {
+		actual := testRenderTree(JSONSchemaTreeRenderer{}, &testJSONStruct{})
+
+		gomega.Expect(actual).To(gomega.MatchJSON(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "mock",
+			"description": "Config props of mock service URLs",
+			"type": "object",
+			"properties": {
+				"color": {"type": "string", "pattern": "^(0x|#)?[0-9a-fA-F]+$", "default": "0xff00ff",
+				 "x-shoutrrr-field": "Color"},
+				"count": {"type": "integer", "default": 3, "x-shoutrrr-field": "Count", "x-shoutrrr-aliases": ["c"]},
+				"enabled": {"type": "boolean", "default": true, "x-shoutrrr-field": "Enabled"},
+				"host": {"description": "Server host", "type": "string", "x-shoutrrr-field": "Host",
+				 "x-shoutrrr-url-parts": ["host", "port"]},
+				"tags": {"type": "array", "items": {"type": "string"}, "default": ["a", "b"],
+				 "x-shoutrrr-field": "Tags"},
+				"timeout": {"type": "string", "pattern": "^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$",
+				 "default": "10s", "x-shoutrrr-field": "Timeout"},
+				"token": {"type": "string", "x-shoutrrr-field": "Token", "x-shoutrrr-url-parts": ["path2"]}
+			},
+			"additionalProperties": false,
+			"required": ["host", "token"]
+		}`))
+	})
+
+	ginkgo.It("should render the enum choices", func() {
+		actual := testRenderTree(JSONSchemaTreeRenderer{}, &testEnummer{})
+
+		gomega.Expect(actual).To(gomega.MatchJSON(`{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"title": "mock",
+			"description": "Config props of mock service URLs",
+			"type": "object",
+			"properties": {
+				"choice": {"type": "string", "enum": ["Yes", "No", "Maybe"], "default": "Maybe",
+				 "x-shoutrrr-field": "Choice"}
+			},
+			"additionalProperties": false
+		}`))
+	})
+})
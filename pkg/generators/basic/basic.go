@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -13,6 +15,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // Errors defined as static variables for better error handling.
@@ -20,10 +23,17 @@ var (
 	ErrInvalidConfigType    = errors.New("config does not implement types.ServiceConfig")
 	ErrInvalidConfigField   = errors.New("config field is invalid or nil")
 	ErrRequiredFieldMissing = errors.New("field is required and has no default value")
+	ErrMissingProperties    = errors.New("missing required properties")
+	ErrUnknownProperty      = errors.New("unknown property")
+	ErrInvalidProperty      = errors.New("invalid property value")
 )
 
 // Generator is the Basic Generator implementation for creating service configurations.
-type Generator struct{}
+type Generator struct {
+	// NonInteractive disables the prompts, setting the fields from the provided properties and
+	// their default values only. All missing required fields are reported in a single error.
+	NonInteractive bool
+}
 
 // Generate creates a service configuration by prompting the user for field values or using provided properties.
 func (g *Generator) Generate(
@@ -32,13 +42,28 @@ func (g *Generator) Generate(
 	_ []string,
 ) (types.ServiceConfig, error) {
 	configPtr := reflect.ValueOf(service).Elem().FieldByName("Config")
-	if !configPtr.IsValid() || configPtr.IsNil() {
+	if !configPtr.IsValid() || configPtr.Kind() != reflect.Ptr {
 		return nil, ErrInvalidConfigField
 	}
 
-	scanner := bufio.NewScanner(os.Stdin)
-	if err := g.promptUserForFields(configPtr, props, scanner); err != nil {
-		return nil, err
+	// Services are created without a config until they are initialized, so start from an empty one
+	if configPtr.IsNil() {
+		if !configPtr.CanSet() {
+			return nil, ErrInvalidConfigField
+		}
+
+		configPtr.Set(reflect.New(configPtr.Type().Elem()))
+	}
+
+	if g.NonInteractive {
+		if err := g.setFieldsFromProps(configPtr, props); err != nil {
+			return nil, err
+		}
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		if err := g.promptUserForFields(configPtr, props, scanner); err != nil {
+			return nil, err
+		}
 	}
 
 	if config, ok := configPtr.Interface().(types.ServiceConfig); ok {
@@ -85,6 +110,91 @@ func (g *Generator) promptUserForFields(
 	return nil
 }
 
+// setFieldsFromProps sets the config fields from the props, falling back to the default values, without prompting.
+// Props are matched by the field name or any of its query keys, ignoring case.
+func (g *Generator) setFieldsFromProps(configPtr reflect.Value, props map[string]string) error {
+	serviceConfig, ok := configPtr.Interface().(types.ServiceConfig)
+	if !ok {
+		return ErrInvalidConfigType
+	}
+
+	configNode := format.GetConfigFormat(serviceConfig)
+	config := configPtr.Elem() // Dereference for setting fields
+
+	remaining := make(map[string]string, len(props))
+	for key, value := range props {
+		remaining[strings.ToLower(key)] = value
+	}
+
+	var (
+		errs      []error
+		missing   []string
+		propNames []string
+	)
+
+	for _, item := range configNode.Items {
+		field := item.Field()
+		names := fieldPropNames(field)
+		propNames = append(propNames, names...)
+
+		inputValue := ""
+
+		for _, name := range names {
+			if value, found := remaining[name]; found {
+				inputValue = value
+
+				delete(remaining, name)
+			}
+		}
+
+		if len(inputValue) == 0 {
+			inputValue = field.DefaultValue
+		}
+
+		if len(inputValue) == 0 {
+			if field.Required {
+				missing = append(missing, names[0])
+			}
+
+			continue
+		}
+
+		if valid, err := format.SetConfigField(config, *field, inputValue); err != nil {
+			errs = append(errs, fmt.Errorf("%w for %s: %w", ErrInvalidProperty, names[0], err))
+		} else if !valid {
+			errs = append(errs, fmt.Errorf("%w for %s: %q is not a valid %s",
+				ErrInvalidProperty, names[0], inputValue, field.Type.Kind()))
+		}
+	}
+
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrMissingProperties, strings.Join(missing, ", ")))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(remaining)) {
+		if suggestion, found := util.ClosestMatch(key, propNames); found {
+			errs = append(errs, fmt.Errorf("%w %q, did you mean %q?", ErrUnknownProperty, key, suggestion))
+		} else {
+			errs = append(errs, fmt.Errorf("%w %q", ErrUnknownProperty, key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fieldPropNames returns the names that can be used as props for the field, starting with the lowercase field name.
+func fieldPropNames(field *format.FieldInfo) []string {
+	names := []string{strings.ToLower(field.Name)}
+
+	for _, key := range field.Keys {
+		if key = strings.ToLower(key); !slices.Contains(names, key) {
+			names = append(names, key)
+		}
+	}
+
+	return names
+}
+
 // getInputValue retrieves the value for a field from props or user input.
 func (g *Generator) getInputValue(
 	field *format.FieldInfo,
@@ -139,6 +249,11 @@ func (g *Generator) getInputValue(
 		return "", fmt.Errorf("scanner error: %w", scanErr)
 	}
 
+	// The input has ended, so prompting again would never get a value
+	if len(field.DefaultValue) == 0 && field.Required {
+		return "", fmt.Errorf("%s: %w", field.Name, ErrRequiredFieldMissing)
+	}
+
 	return field.DefaultValue, nil
 }
 
@@ -2,6 +2,7 @@ package basic
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -233,6 +234,80 @@ func TestGenerator_Generate(t *testing.T) {
 	}
 }
 
+func TestGenerator_Generate_NonInteractive(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   map[string]string
+		want    types.ServiceConfig
+		wantErr error
+	}{
+		{
+			name:  "props with defaults",
+			props: map[string]string{"Port": "9090"},
+			want: &mockConfig{
+				Host: "localhost",
+				Port: 9090,
+			},
+		},
+		{
+			name:  "values containing equals signs",
+			props: map[string]string{"host": "a=b=c"},
+			want: &mockConfig{
+				Host: "a=b=c",
+				Port: 8080,
+			},
+		},
+		{
+			name:    "invalid value",
+			props:   map[string]string{"port": "invalid"},
+			wantErr: ErrInvalidProperty,
+		},
+		{
+			name:    "unknown property",
+			props:   map[string]string{"hots": "example.com"},
+			wantErr: ErrUnknownProperty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{NonInteractive: true}
+
+			got, err := g.Generate(newMockServiceConfig(), tt.props, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+
+				return
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_setFieldsFromProps_MissingRequired(t *testing.T) {
+	type requiredConfig struct {
+		mockConfig
+
+		Token  string `key:"token"  required:"true"`
+		Secret string `key:"secret" required:"true"`
+	}
+
+	g := &Generator{NonInteractive: true}
+	config := &requiredConfig{}
+
+	err := g.setFieldsFromProps(reflect.ValueOf(config), map[string]string{})
+	if !errors.Is(err, ErrMissingProperties) {
+		t.Fatalf("setFieldsFromProps() error = %v, want %v", err, ErrMissingProperties)
+	}
+
+	if !strings.Contains(err.Error(), "secret, token") {
+		t.Errorf("setFieldsFromProps() error = %q, want all missing fields listed", err)
+	}
+}
+
 func TestGenerator_promptUserForFields(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,483 @@
+package guided
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
+)
+
+// Errors defined as static variables for better error handling.
+var (
+	ErrInvalidConfigType  = errors.New("config does not implement types.ServiceConfig")
+	ErrInvalidConfigField = errors.New("config field is invalid or nil")
+	ErrInvalidValue       = errors.New("value is not valid for the field type")
+	ErrMissingFields      = errors.New("input ended with required fields missing")
+	ErrUnknownField       = errors.New("unknown field")
+)
+
+// commandPrefix marks the input as a dialog command instead of a field value.
+const commandPrefix = ":"
+
+// reviewStep is the dialog step that shows all values before finishing.
+const reviewStep = -1
+
+const helpText = `Enter a value for the field, or leave it empty to keep the current value.
+Commands:
+  :back          go back to the previous field
+  :edit <field>  edit a field, by number or name
+  :clear         clear the value of the current field
+  :list          list all fields and their values
+  :done          review the values and finish
+  :help          show this help
+`
+
+// Generator is a guided dialog for creating service configurations in the terminal.
+// It shows the description, default value and choices of every field, validates the values as they are
+// entered, and allows going back to edit any field before finishing with an optional live check.
+type Generator struct {
+	// Input is where the answers are read from, defaulting to stdin.
+	Input io.Reader
+	// Output is where the dialog is written to, defaulting to the colored stdout.
+	Output io.Writer
+	// CheckRequested is set when the user asked for a live check of the generated URL.
+	CheckRequested bool
+}
+
+// dialog is the state of a guided session for a single service config.
+type dialog struct {
+	output  io.Writer
+	scanner *bufio.Scanner
+	config  reflect.Value
+	fields  []*format.FieldInfo
+	values  []string
+}
+
+// Generate creates a service configuration by guiding the user through its fields, prefilled by the props.
+func (g *Generator) Generate(
+	service types.Service,
+	props map[string]string,
+	_ []string,
+) (types.ServiceConfig, error) {
+	configPtr := reflect.ValueOf(service).Elem().FieldByName("Config")
+	if !configPtr.IsValid() || configPtr.Kind() != reflect.Ptr {
+		return nil, ErrInvalidConfigField
+	}
+
+	// Services are created without a config until they are initialized, so start from an empty one
+	if configPtr.IsNil() {
+		if !configPtr.CanSet() {
+			return nil, ErrInvalidConfigField
+		}
+
+		configPtr.Set(reflect.New(configPtr.Type().Elem()))
+	}
+
+	serviceConfig, ok := configPtr.Interface().(types.ServiceConfig)
+	if !ok {
+		return nil, ErrInvalidConfigType
+	}
+
+	input, output := g.Input, g.Output
+	if input == nil {
+		input = os.Stdin
+	}
+
+	if output == nil {
+		output = color.Output
+	}
+
+	d := newDialog(input, output, configPtr.Elem(), format.GetConfigFormat(serviceConfig))
+
+	d.printf("Guided setup for %s, with %d fields. Enter %s for help.\n",
+		color.HiCyanString(service.GetID()), len(d.fields), color.HiYellowString(":help"))
+	d.applyProps(props)
+
+	if err := d.run(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := service.(types.Checker); ok {
+		g.CheckRequested = d.confirm("Run a live check of the service?")
+	}
+
+	return serviceConfig, nil
+}
+
+// newDialog creates a dialog for the config fields, listing the required fields first, and applies the defaults.
+func newDialog(input io.Reader, output io.Writer, config reflect.Value, configNode *format.ContainerNode) *dialog {
+	d := &dialog{
+		output:  output,
+		scanner: bufio.NewScanner(input),
+		config:  config,
+	}
+
+	var optional []*format.FieldInfo
+
+	for _, item := range configNode.Items {
+		if field := item.Field(); field.Required {
+			d.fields = append(d.fields, field)
+		} else {
+			optional = append(optional, field)
+		}
+	}
+
+	d.fields = append(d.fields, optional...)
+	d.values = make([]string, len(d.fields))
+
+	for index, field := range d.fields {
+		if err := d.set(index, field.DefaultValue); err != nil {
+			d.printf("Invalid default value for %s: %v\n", field.Name, err)
+		}
+	}
+
+	return d
+}
+
+// applyProps sets the fields matching the props by name or key, reporting values that cannot be used.
+func (d *dialog) applyProps(props map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(props)) {
+		value := props[key]
+
+		index, err := d.findField(key)
+		if err != nil {
+			d.printf("Ignoring property %s: %v\n", color.HiYellowString(key), err)
+
+			continue
+		}
+
+		if err := d.set(index, value); err != nil {
+			d.printf("Ignoring property %s: %v\n", color.HiYellowString(key), err)
+
+			continue
+		}
+
+		d.printf("Using property %s for %s\n", color.HiCyanString(value), color.HiMagentaString(d.fields[index].Name))
+	}
+}
+
+// run prompts for the fields in order, until all values have been reviewed and accepted.
+func (d *dialog) run() error {
+	step := 0
+	fromReview := false
+
+	for {
+		if step >= len(d.fields) {
+			step = reviewStep
+		}
+
+		if step == reviewStep {
+			next, done, err := d.review()
+			if err != nil || done {
+				return err
+			}
+
+			step, fromReview = next, true
+
+			continue
+		}
+
+		next, err := d.prompt(step)
+		if err != nil {
+			return err
+		}
+
+		// After editing a field from the review, return to it instead of continuing with the next field
+		if fromReview && next == step+1 {
+			next = reviewStep
+		}
+
+		step = next
+	}
+}
+
+// prompt shows the field and reads input until a value is accepted or a command changes the step.
+// It returns the next step of the dialog.
+func (d *dialog) prompt(index int) (int, error) {
+	field := d.fields[index]
+	d.describe(index)
+
+	for {
+		if len(d.values[index]) > 0 {
+			d.printf("%s [%s]: ", color.HiWhiteString(field.Name), d.values[index])
+		} else {
+			d.printf("%s: ", color.HiWhiteString(field.Name))
+		}
+
+		input, ok, err := d.readLine()
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			return reviewStep, nil
+		}
+
+		if command, found := strings.CutPrefix(input, commandPrefix); found {
+			next, handled := d.runCommand(index, command)
+			if handled {
+				return next, nil
+			}
+
+			continue
+		}
+
+		if len(input) == 0 {
+			if field.Required && len(d.values[index]) == 0 {
+				d.printf("%s is required\n", field.Name)
+
+				continue
+			}
+
+			return index + 1, nil
+		}
+
+		if err := d.set(index, input); err != nil {
+			d.printf("%s %v\n", color.RedString("Invalid value:"), err)
+
+			continue
+		}
+
+		return index + 1, nil
+	}
+}
+
+// runCommand performs the dialog command, returning the next step if the command leaves the current field.
+func (d *dialog) runCommand(index int, command string) (int, bool) {
+	name, argument, _ := strings.Cut(strings.TrimSpace(command), " ")
+
+	switch strings.ToLower(name) {
+	case "back", "b":
+		return max(index-1, 0), true
+	case "edit", "e":
+		target, err := d.findField(strings.TrimSpace(argument))
+		if err != nil {
+			d.printf("%v\n", err)
+
+			return index, false
+		}
+
+		return target, true
+	case "clear":
+		if d.fields[index].Required {
+			d.printf("%s is required and cannot be cleared\n", d.fields[index].Name)
+
+			return index, false
+		}
+
+		_ = d.set(index, "")
+
+		return index + 1, true
+	case "list", "l":
+		d.list()
+	case "done", "d":
+		return reviewStep, true
+	case "help", "h", "?":
+		d.printf("%s", helpText)
+	default:
+		d.printf("Unknown command %s, enter %s for help\n",
+			color.HiYellowString(commandPrefix+name), color.HiYellowString(":help"))
+	}
+
+	return index, false
+}
+
+// review lists all values, and either finishes the dialog or returns the field to edit.
+// If required fields are missing, the first of them is returned instead.
+func (d *dialog) review() (int, bool, error) {
+	d.printf("\n")
+	d.list()
+
+	if missing := d.missing(); len(missing) > 0 {
+		d.printf("\nThe required field %s is missing a value\n", d.fields[missing[0]].Name)
+
+		return missing[0], false, nil
+	}
+
+	for {
+		d.printf("\nPress enter to finish, or enter the number or name of a field to edit: ")
+
+		input, ok, err := d.readLine()
+		if err != nil || !ok || len(input) == 0 {
+			return 0, true, err
+		}
+
+		index, err := d.findField(strings.TrimPrefix(input, commandPrefix+"edit "))
+		if err != nil {
+			d.printf("%v\n", err)
+
+			continue
+		}
+
+		return index, false, nil
+	}
+}
+
+// describe writes the position, description, default value and choices of the field.
+func (d *dialog) describe(index int) {
+	field := d.fields[index]
+
+	d.printf("\n[%d/%d] %s", index+1, len(d.fields), color.HiCyanString(field.Name))
+
+	if field.Required {
+		d.printf(" %s", color.HiRedString("(required)"))
+	}
+
+	d.printf("\n")
+
+	if len(field.Description) > 0 {
+		d.printf("  %s\n", field.Description)
+	}
+
+	if len(field.DefaultValue) > 0 {
+		d.printf("  Default: %s\n", field.DefaultValue)
+	}
+
+	if field.EnumFormatter != nil {
+		d.printf("  Choices: %s\n", strings.Join(field.EnumFormatter.Names(), ", "))
+	}
+}
+
+// list writes the numbered fields with their current values, marking the required fields.
+func (d *dialog) list() {
+	nameWidth := 0
+	for _, field := range d.fields {
+		nameWidth = max(nameWidth, len(field.Name))
+	}
+
+	for index, field := range d.fields {
+		marker := " "
+		if field.Required {
+			marker = color.HiRedString("*")
+		}
+
+		d.printf("%3d %s %-*s  %s\n", index+1, marker, nameWidth, field.Name, d.values[index])
+	}
+}
+
+// set validates the value by setting it on the config field, and keeps it as the current value.
+// An empty value resets the field.
+func (d *dialog) set(index int, value string) error {
+	field := d.fields[index]
+
+	if len(value) == 0 {
+		d.config.FieldByName(field.Name).SetZero()
+		d.values[index] = ""
+
+		return nil
+	}
+
+	valid, err := format.SetConfigField(d.config, *field, value)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %s", ErrInvalidValue, field.Type.Kind())
+	}
+
+	d.values[index] = value
+
+	return nil
+}
+
+// findField returns the index of the field matching the number, name or key, ignoring case.
+func (d *dialog) findField(reference string) (int, error) {
+	if number, err := strconv.Atoi(reference); err == nil {
+		if number < 1 || number > len(d.fields) {
+			return 0, fmt.Errorf("%w %d", ErrUnknownField, number)
+		}
+
+		return number - 1, nil
+	}
+
+	var names []string
+
+	for index, field := range d.fields {
+		if strings.EqualFold(field.Name, reference) {
+			return index, nil
+		}
+
+		for _, key := range field.Keys {
+			if strings.EqualFold(key, reference) {
+				return index, nil
+			}
+		}
+
+		names = append(names, strings.ToLower(field.Name))
+	}
+
+	if suggestion, found := util.ClosestMatch(strings.ToLower(reference), names); found {
+		return 0, fmt.Errorf("%w %q, did you mean %q?", ErrUnknownField, reference, suggestion)
+	}
+
+	return 0, fmt.Errorf("%w %q", ErrUnknownField, reference)
+}
+
+// missing returns the indexes of the required fields without a value.
+func (d *dialog) missing() []int {
+	var missing []int
+
+	for index, field := range d.fields {
+		if field.Required && len(d.values[index]) == 0 {
+			missing = append(missing, index)
+		}
+	}
+
+	return missing
+}
+
+// confirm asks a yes or no question, defaulting to no.
+func (d *dialog) confirm(question string) bool {
+	d.printf("\n%s [y/N]: ", question)
+
+	input, ok, err := d.readLine()
+	if err != nil || !ok {
+		return false
+	}
+
+	answer := strings.ToLower(input)
+
+	return answer == "y" || answer == "yes"
+}
+
+// readLine reads the next line of input, returning false when the input has ended.
+// If the input ends while required fields are missing, an error listing them is returned.
+func (d *dialog) readLine() (string, bool, error) {
+	if d.scanner.Scan() {
+		return strings.TrimSpace(d.scanner.Text()), true, nil
+	}
+
+	if err := d.scanner.Err(); err != nil {
+		return "", false, fmt.Errorf("reading input: %w", err)
+	}
+
+	d.printf("\n")
+
+	if missing := d.missing(); len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for _, index := range missing {
+			names = append(names, d.fields[index].Name)
+		}
+
+		return "", false, fmt.Errorf("%w: %s", ErrMissingFields, strings.Join(names, ", "))
+	}
+
+	return "", false, nil
+}
+
+func (d *dialog) printf(layout string, args ...any) {
+	_, _ = fmt.Fprintf(d.output, layout, args...)
+}
@@ -0,0 +1,217 @@
+package guided
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+var modeEnum = format.CreateEnumFormatter([]string{"Fast", "Safe"})
+
+// mockConfig implements types.ServiceConfig.
+type mockConfig struct {
+	Host string `default:"localhost" desc:"Server hostname" key:"host"`
+	Port int    `                    desc:"Server port"     key:"port" required:"true"`
+	Mode int    `default:"Fast"      desc:"Delivery mode"   key:"mode"`
+}
+
+func (m *mockConfig) Enums() map[string]types.EnumFormatter {
+	return map[string]types.EnumFormatter{"Mode": modeEnum}
+}
+
+func (m *mockConfig) GetURL() *url.URL {
+	return &url.URL{Scheme: "mock", Host: m.Host}
+}
+
+func (m *mockConfig) SetURL(_ *url.URL) error {
+	return nil
+}
+
+// mockService is a test implementation of types.Service.
+type mockService struct {
+	standard.Standard
+	Config *mockConfig
+}
+
+func (m *mockService) GetID() string {
+	return "mock"
+}
+
+func (m *mockService) Initialize(_ *url.URL, _ types.StdLogger) error {
+	return nil
+}
+
+func (m *mockService) Send(_ string, _ *types.Params) error {
+	return nil
+}
+
+// checkedService is a mockService that supports live checks.
+type checkedService struct {
+	mockService
+}
+
+func (c *checkedService) Check() (types.CheckResult, error) {
+	return types.CheckResult{}, nil
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   map[string]string
+		input   string
+		want    *mockConfig
+		output  []string
+		wantErr error
+	}{
+		{
+			name:  "defaults and required value",
+			input: "8080\n\n\n\n",
+			want:  &mockConfig{Host: "localhost", Port: 8080, Mode: 0},
+			output: []string{
+				"[1/3] Port (required)",
+				"Default: localhost",
+				"Choices: Fast, Safe",
+			},
+		},
+		{
+			name:   "required value missing",
+			input:  "\n8080\n\n\n\n",
+			want:   &mockConfig{Host: "localhost", Port: 8080},
+			output: []string{"Port is required"},
+		},
+		{
+			name:   "invalid values are prompted again",
+			input:  "http\n8080\n\nSlow\nsafe\n\n",
+			want:   &mockConfig{Host: "localhost", Port: 8080, Mode: 1},
+			output: []string{"Invalid value: failed to parse integer", "Invalid value: not a valid enum value"},
+		},
+		{
+			name:  "going back to a previous field",
+			input: "8080\n:back\n9090\nexample.com\n\n\n",
+			want:  &mockConfig{Host: "example.com", Port: 9090},
+		},
+		{
+			name:   "editing a field from the review",
+			props:  map[string]string{"port": "8080"},
+			input:  ":done\nhost\nexample.com\n\n",
+			want:   &mockConfig{Host: "example.com", Port: 8080},
+			output: []string{"Using property 8080 for Port", "  1 * Port  8080"},
+		},
+		{
+			name:    "input ending with required fields missing",
+			input:   ":edit 2\n",
+			wantErr: ErrMissingFields,
+		},
+	}
+
+	color.NoColor = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := &strings.Builder{}
+			g := &Generator{Input: strings.NewReader(tt.input), Output: output}
+
+			got, err := g.Generate(&mockService{}, tt.props, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Generate() = %+v, want %+v", got, tt.want)
+			}
+
+			for _, line := range tt.output {
+				if !strings.Contains(output.String(), line) {
+					t.Errorf("Generate() output does not contain %q:\n%s", line, output)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_CheckRequested(t *testing.T) {
+	tests := []struct {
+		name    string
+		service types.Service
+		input   string
+		want    bool
+	}{
+		{
+			name:    "check accepted",
+			service: &checkedService{},
+			input:   "8080\n:done\n\ny\n",
+			want:    true,
+		},
+		{
+			name:    "check declined by default",
+			service: &checkedService{},
+			input:   "8080\n:done\n\n\n",
+			want:    false,
+		},
+		{
+			name:    "check not supported",
+			service: &mockService{},
+			input:   "8080\n:done\n\ny\n",
+			want:    false,
+		},
+	}
+
+	color.NoColor = true
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Generator{Input: strings.NewReader(tt.input), Output: &strings.Builder{}}
+
+			if _, err := g.Generate(tt.service, nil, nil); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			if g.CheckRequested != tt.want {
+				t.Errorf("CheckRequested = %v, want %v", g.CheckRequested, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialog_findField(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		want      int
+		wantErr   string
+	}{
+		{name: "by number", reference: "3", want: 2},
+		{name: "by name", reference: "HOST", want: 1},
+		{name: "number out of range", reference: "4", wantErr: "unknown field 4"},
+		{name: "typo", reference: "hots", wantErr: `unknown field "hots", did you mean "host"?`},
+	}
+
+	config := &mockConfig{}
+	d := newDialog(strings.NewReader(""), &strings.Builder{}, reflect.ValueOf(config).Elem(),
+		format.GetConfigFormat(config))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.findField(tt.reference)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("findField() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("findField() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/generators/basic"
+	"github.com/nicholas-fedor/shoutrrr/pkg/generators/guided"
 	"github.com/nicholas-fedor/shoutrrr/pkg/generators/xouath2"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/telegram"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
@@ -15,6 +16,7 @@ var ErrUnknownGenerator = errors.New("unknown generator")
 
 var generatorMap = map[string]func() types.Generator{
 	"basic":    func() types.Generator { return &basic.Generator{} },
+	"guided":   func() types.Generator { return &guided.Generator{} },
 	"oauth2":   func() types.Generator { return &xouath2.Generator{} },
 	"telegram": func() types.Generator { return &telegram.Generator{} },
 }
@@ -249,17 +249,23 @@ func generateOauth2Config(conf *oauth2.Config, host string) (*smtp.Config, error
 		port = GmailSMTPPortStartTLS // Use 587 for Gmail with STARTTLS
 	}
 
+	// The refresh token and client credentials let the service obtain new access tokens,
+	// since the access token returned by the exchange is only valid for a short time
 	svcConf := &smtp.Config{
-		Host:        host,
-		Port:        port,
-		Username:    sender,
-		Password:    token.AccessToken,
-		FromAddress: sender,
-		FromName:    "Shoutrrr",
-		ToAddresses: []string{sender},
-		Auth:        smtp.AuthTypes.OAuth2,
-		UseStartTLS: true,
-		UseHTML:     true,
+		Host:         host,
+		Port:         port,
+		Username:     sender,
+		FromAddress:  sender,
+		FromName:     "Shoutrrr",
+		ToAddresses:  []string{sender},
+		Auth:         smtp.AuthTypes.OAuth2,
+		UseStartTLS:  true,
+		UseHTML:      true,
+		ClientID:     conf.ClientID,
+		ClientSecret: conf.ClientSecret,
+		TokenURL:     conf.Endpoint.TokenURL,
+		RefreshToken: token.RefreshToken,
+		Scopes:       conf.Scopes,
 	}
 
 	return svcConf, nil
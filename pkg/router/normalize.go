@@ -0,0 +1,71 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// ErrNormalizeLossy is returned when the service URL cannot be normalized without changing its config.
+var ErrNormalizeLossy = errors.New("normalized URL does not keep all the config values")
+
+// NormalizeURL returns the canonical form of the service URL, as built by its config from the parsed URL.
+// Props that are not set in the URL are given their default values. When stripDefaults is set,
+// query params with the default value of their prop are removed, keeping the URL as short as possible.
+// The normalized URL is parsed again, returning ErrNormalizeLossy if it does not give the same config.
+func (router *ServiceRouter) NormalizeURL(rawURL string, stripDefaults bool) (string, error) {
+	config, err := router.ParseURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	serviceURL := config.GetURL()
+
+	if stripDefaults {
+		stripDefaultParams(config, serviceURL)
+	}
+
+	normalized := serviceURL.String()
+
+	// Configs that do not write all of their values to the URL would silently change the service
+	reparsed, err := router.ParseURL(normalized)
+	if err != nil || !reflect.DeepEqual(reparsed, config) {
+		return "", fmt.Errorf("%s: %w", serviceURL.Scheme, ErrNormalizeLossy)
+	}
+
+	return normalized, nil
+}
+
+// stripDefaultParams removes the query params of the URL that have the default value of their config prop.
+// The order of the remaining params is kept, since some configs do not sort them.
+func stripDefaultParams(config types.ServiceConfig, serviceURL *url.URL) {
+	if serviceURL.RawQuery == "" {
+		return
+	}
+
+	pkr := format.NewPropKeyResolver(config)
+	params := strings.Split(serviceURL.RawQuery, "&")
+	kept := make([]string, 0, len(params))
+
+	for _, param := range params {
+		rawKey, rawValue, _ := strings.Cut(param, "=")
+
+		key, keyErr := url.QueryUnescape(rawKey)
+		value, valueErr := url.QueryUnescape(rawValue)
+
+		// Params that are not config props, like custom headers, are always kept
+		if keyErr == nil && valueErr == nil && pkr.IsDefault(key, value) {
+			continue
+		}
+
+		kept = append(kept, param)
+	}
+
+	serviceURL.RawQuery = strings.Join(kept, "&")
+	serviceURL.ForceQuery = serviceURL.ForceQuery && len(kept) > 0
+}
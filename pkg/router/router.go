@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
@@ -22,8 +24,31 @@ var (
 	ErrSendFailed             = errors.New("failed to send message")
 	ErrCustomURLConversion    = errors.New("failed to convert custom URL")
 	ErrInitializeFailed       = errors.New("failed to initialize service")
+	ErrDryRunNotSupported     = errors.New("dry run is not supported by service")
+	ErrDefaultPropsFailed     = errors.New("failed to set default config props")
 )
 
+// InitializeError is returned when a service could not be initialized from its URL.
+// As the error of the service may contain secrets from the URL, the message only describes the
+// invalid prop, if any. The error of the service itself is available using errors.As or Unwrap.
+type InitializeError struct {
+	Scheme string
+	Err    error
+}
+
+func (e *InitializeError) Error() string {
+	var propErr *format.PropError
+	if errors.As(e.Err, &propErr) {
+		return fmt.Sprintf("%s: %v: %s", e.Scheme, ErrInitializeFailed, propErr.Summary())
+	}
+
+	return fmt.Sprintf("%s: %v", e.Scheme, ErrInitializeFailed)
+}
+
+func (e *InitializeError) Unwrap() []error {
+	return []error{ErrInitializeFailed, e.Err}
+}
+
 // ServiceRouter is responsible for routing a message to a specific notification service using the notification URL.
 type ServiceRouter struct {
 	logger   types.StdLogger
@@ -32,6 +57,22 @@ type ServiceRouter struct {
 	Timeout  time.Duration
 }
 
+// Result is the outcome of sending a notification using one of the router's services.
+type Result struct {
+	// Index is the position of the service, in the order that the services were added to the router.
+	Index    int
+	Service  string
+	Err      error
+	Duration time.Duration
+	// Requests are the rendered requests of the service, only populated by DryRun.
+	Requests []types.Request
+}
+
+// itemSender is implemented by services that can send message items, keeping their levels and fields.
+type itemSender interface {
+	SendItems(items []types.MessageItem, params *types.Params) error
+}
+
 // New creates a new service router using the specified logger and service URLs.
 func New(logger types.StdLogger, serviceURLs ...string) (*ServiceRouter, error) {
 	router := ServiceRouter{
@@ -81,15 +122,9 @@ func (router *ServiceRouter) SendItems(items []types.MessageItem, params types.P
 		return []error{ErrNoSenders}
 	}
 
-	// Fallback using old API for now
-	message := strings.Builder{}
-	for _, item := range items {
-		message.WriteString(item.Text)
-	}
-
 	serviceCount := len(router.services)
 	errors := make([]error, serviceCount)
-	results := router.SendAsync(message.String(), &params)
+	results := router.SendItemsAsync(items, params)
 
 	for i := range router.services {
 		errors[i] = <-results
@@ -100,21 +135,97 @@ func (router *ServiceRouter) SendItems(items []types.MessageItem, params types.P
 
 // SendAsync sends the specified message using the routers underlying services.
 func (router *ServiceRouter) SendAsync(message string, params *types.Params) chan error {
-	serviceCount := len(router.services)
-	proxy := make(chan error, serviceCount)
-	errors := make(chan error, serviceCount)
+	return resultErrors(router.SendAsyncResults(message, params))
+}
+
+// SendItemsAsync sends the specified message items using the routers underlying services.
+// Services that do not support message items are sent the text of the items, joined by newlines.
+func (router *ServiceRouter) SendItemsAsync(items []types.MessageItem, params types.Params) chan error {
+	return resultErrors(router.SendItemsAsyncResults(items, params))
+}
 
+// SendAsyncResults sends the specified message using the routers underlying services,
+// returning the result of each service as soon as it is done.
+func (router *ServiceRouter) SendAsyncResults(message string, params *types.Params) chan Result {
 	if params == nil {
 		params = &types.Params{}
 	}
 
-	for _, service := range router.services {
-		go sendToService(service, proxy, router.Timeout, message, *params)
+	return router.sendAsync(func(service types.Service, params types.Params) error {
+		return service.Send(message, &params)
+	}, *params)
+}
+
+// SendItemsAsyncResults sends the specified message items using the routers underlying services,
+// returning the result of each service as soon as it is done.
+func (router *ServiceRouter) SendItemsAsyncResults(items []types.MessageItem, params types.Params) chan Result {
+	message := strings.TrimSuffix(types.ItemsToPlain(items), "\n")
+
+	return router.sendAsync(func(service types.Service, params types.Params) error {
+		if sender, ok := service.(itemSender); ok {
+			return sender.SendItems(items, &params)
+		}
+
+		return service.Send(message, &params)
+	}, params)
+}
+
+// DryRun renders the requests that each of the routers underlying services would perform to send the message,
+// without sending it. Services that do not implement types.DryRunner return ErrDryRunNotSupported.
+func (router *ServiceRouter) DryRun(message string, params *types.Params) []Result {
+	if params == nil {
+		params = &types.Params{}
+	}
+
+	results := make([]Result, len(router.services))
+
+	for index, service := range router.services {
+		result := Result{Index: index, Service: service.GetID()}
+		start := time.Now()
+
+		if dryRunner, ok := service.(types.DryRunner); ok {
+			result.Requests, result.Err = dryRunner.DryRun(message, params)
+		} else {
+			result.Err = fmt.Errorf("%w: %s", ErrDryRunNotSupported, service.GetID())
+		}
+
+		result.Duration = time.Since(start)
+		results[index] = result
+	}
+
+	return results
+}
+
+func (router *ServiceRouter) sendAsync(
+	send func(service types.Service, params types.Params) error,
+	params types.Params,
+) chan Result {
+	serviceCount := len(router.services)
+	proxy := make(chan Result, serviceCount)
+	results := make(chan Result, serviceCount)
+
+	for index, service := range router.services {
+		go sendToService(index, service, proxy, router.Timeout, send, params)
 	}
 
 	go func() {
 		for range serviceCount {
-			errors <- <-proxy
+			results <- <-proxy
+		}
+
+		close(results)
+	}()
+
+	return results
+}
+
+// resultErrors returns a channel with the errors of the results.
+func resultErrors(results chan Result) chan error {
+	errors := make(chan error, cap(results))
+
+	go func() {
+		for result := range results {
+			errors <- result.Err
 		}
 
 		close(errors)
@@ -124,24 +235,28 @@ func (router *ServiceRouter) SendAsync(message string, params *types.Params) cha
 }
 
 func sendToService(
+	index int,
 	service types.Service,
-	results chan error,
+	results chan Result,
 	timeout time.Duration,
-	message string,
+	send func(service types.Service, params types.Params) error,
 	params types.Params,
 ) {
-	result := make(chan error)
-
+	result := make(chan error, 1)
 	serviceID := service.GetID()
+	start := time.Now()
+
+	go func() { result <- send(service, params) }()
 
-	go func() { result <- service.Send(message, &params) }()
+	var err error
 
 	select {
-	case res := <-result:
-		results <- res
+	case err = <-result:
 	case <-time.After(timeout):
-		results <- fmt.Errorf("%w: using %v", ErrServiceTimeout, serviceID)
+		err = fmt.Errorf("%w: using %v", ErrServiceTimeout, serviceID)
 	}
+
+	results <- Result{Index: index, Service: serviceID, Err: err, Duration: time.Since(start)}
 }
 
 // Enqueue adds the message to an internal queue and sends it when Flush is invoked.
@@ -210,28 +325,108 @@ func (router *ServiceRouter) initService(rawURL string) (types.Service, error) {
 		return nil, err
 	}
 
-	if configURL.Scheme != scheme {
-		router.log("Got custom URL:", configURL.String())
+	configURL, err = router.serviceURL(service, scheme, configURL)
+	if err != nil {
+		return nil, err
+	}
 
-		customURLService, ok := service.(types.CustomURLService)
-		if !ok {
-			return nil, fmt.Errorf("%w: '%s' service", ErrCustomURLsNotSupported, scheme)
-		}
+	err = service.Initialize(configURL, router.logger)
+	if err != nil {
+		return service, &InitializeError{Scheme: scheme, Err: err}
+	}
 
-		configURL, err = customURLService.GetConfigURLFromCustom(configURL)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", configURL.String(), ErrCustomURLConversion)
-		}
+	return service, nil
+}
 
-		router.log("Converted service URL:", configURL.String())
+// serviceURL returns the service URL for the config URL, converting custom URLs using the service.
+func (router *ServiceRouter) serviceURL(service types.Service, scheme string, configURL *url.URL) (*url.URL, error) {
+	if configURL.Scheme == scheme {
+		return configURL, nil
 	}
 
-	err = service.Initialize(configURL, router.logger)
+	router.log("Got custom URL:", configURL.String())
+
+	customURLService, ok := service.(types.CustomURLService)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' service", ErrCustomURLsNotSupported, scheme)
+	}
+
+	serviceURL, err := customURLService.GetConfigURLFromCustom(configURL)
 	if err != nil {
-		return service, fmt.Errorf("%s: %w", scheme, ErrInitializeFailed)
+		return nil, fmt.Errorf("%s: %w", configURL.String(), ErrCustomURLConversion)
 	}
 
-	return service, nil
+	router.log("Converted service URL:", serviceURL.String())
+
+	return serviceURL, nil
+}
+
+// NewConfig returns a new config for the service, with all props set to their default values.
+// The service is not initialized, so this does not make any requests or keep any state.
+func (*ServiceRouter) NewConfig(serviceScheme string) (types.ServiceConfig, error) {
+	service, err := newService(serviceScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConfig(service)
+}
+
+// ParseURL parses the service URL into a new config of its service, without initializing the service.
+// Custom URLs are converted to service URLs first. The config URL can be rebuilt using its GetURL method,
+// after modifying its props using format.SetConfigProp.
+func (router *ServiceRouter) ParseURL(rawURL string) (types.ServiceConfig, error) {
+	scheme, configURL, err := router.ExtractServiceName(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := newService(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	configURL, err = router.serviceURL(service, scheme, configURL)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := newConfig(service)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.SetURL(configURL); err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", scheme, ErrParseURLFailed, err)
+	}
+
+	return config, nil
+}
+
+// newConfig returns a new config for the uninitialized service, with all props set to their default values.
+func newConfig(service types.Service) (types.ServiceConfig, error) {
+	config := format.GetServiceConfig(service)
+
+	pkr := format.NewPropKeyResolver(config)
+	if err := pkr.SetDefaultProps(config); err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", service.GetID(), ErrDefaultPropsFailed, err)
+	}
+
+	// Fields that are only set from the URL parts, like the port, are not covered by the query props
+	configValue := reflect.Indirect(reflect.ValueOf(config))
+
+	for _, node := range format.GetConfigFormat(config).Items {
+		field := node.Field()
+		if len(field.Keys) > 0 || field.DefaultValue == "" {
+			continue
+		}
+
+		if _, err := format.SetConfigField(configValue, *field, field.DefaultValue); err != nil {
+			return nil, fmt.Errorf("%s: %w: %w", service.GetID(), ErrDefaultPropsFailed, err)
+		}
+	}
+
+	return config, nil
 }
 
 // NewService returns a new uninitialized service instance.
@@ -14,6 +14,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/mattermost"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/ntfy"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/opsgenie"
+	"github.com/nicholas-fedor/shoutrrr/pkg/services/pagerduty"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/pushbullet"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/pushover"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/rocketchat"
@@ -42,6 +43,7 @@ var serviceMap = map[string]func() types.Service{
 	"mattermost": func() types.Service { return &mattermost.Service{} },
 	"ntfy":       func() types.Service { return &ntfy.Service{} },
 	"opsgenie":   func() types.Service { return &opsgenie.Service{} },
+	"pagerduty":  func() types.Service { return &pagerduty.Service{} },
 	"pushbullet": func() types.Service { return &pushbullet.Service{} },
 	"pushover":   func() types.Service { return &pushover.Service{} },
 	"rocketchat": func() types.Service { return &rocketchat.Service{} },
@@ -9,6 +9,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
@@ -40,6 +41,25 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for sending the message, without sending it.
+// The device key is redacted from the request body.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUpdateParamsFailed, err)
+	}
+
+	payload := createPushPayload(&config, message)
+
+	request, err := util.RedactedJSONRequest(config.GetAPIURL("push"), &payload, config.DeviceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
 // Initialize sets up the Service with configuration from configURL and assigns a logger.
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.SetLogger(logger)
@@ -58,18 +78,7 @@ func (service *Service) GetID() string {
 
 func (service *Service) sendAPI(config *Config, message string) error {
 	response := APIResponse{}
-	request := PushPayload{
-		Body:      message,
-		DeviceKey: config.DeviceKey,
-		Title:     config.Title,
-		Category:  config.Category,
-		Copy:      config.Copy,
-		Sound:     config.Sound,
-		Group:     config.Group,
-		Badge:     &config.Badge,
-		Icon:      config.Icon,
-		URL:       config.URL,
-	}
+	request := createPushPayload(config, message)
 	jsonClient := jsonclient.NewClient()
 
 	if err := jsonClient.Post(config.GetAPIURL("push"), &request, &response); err != nil {
@@ -90,3 +99,19 @@ func (service *Service) sendAPI(config *Config, message string) error {
 
 	return nil
 }
+
+// createPushPayload creates the payload for pushing the message to the device.
+func createPushPayload(config *Config, message string) PushPayload {
+	return PushPayload{
+		Body:      message,
+		DeviceKey: config.DeviceKey,
+		Title:     config.Title,
+		Category:  config.Category,
+		Copy:      config.Copy,
+		Sound:     config.Sound,
+		Group:     config.Group,
+		Badge:     &config.Badge,
+		Icon:      config.Icon,
+		URL:       config.URL,
+	}
+}
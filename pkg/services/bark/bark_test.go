@@ -14,6 +14,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/bark"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
 // TestBark runs the Ginkgo test suite for the bark package.
@@ -131,6 +132,20 @@ var _ = ginkgo.Describe("the bark service", func() {
 			gomega.Expect(service.Send("Message", nil)).To(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("renders the request with the device key redacted when doing a dry run", func() {
+			serviceURL := testutils.URLMust("bark://:devicekey@hostname")
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("Message", &types.Params{"title": "Title"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal(service.Config.GetAPIURL("push")))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"device_key":"REDACTED"`))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"title":"Title"`))
+			gomega.Expect(service.Config.Title).To(gomega.BeEmpty())
+		})
+
 		ginkgo.It("handles communication errors without panicking", func() {
 			httpmock.DeactivateAndReset() // Ensure no mocks interfere
 			serviceURL := testutils.URLMust("bark://:devicekey@nonresolvablehostname")
@@ -2,6 +2,7 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -51,14 +52,14 @@ func (service *Service) Send(message string, params *types.Params) error {
 	var firstErr error
 
 	if service.Config.JSON {
-		postURL := CreateAPIURLFromConfig(service.Config)
-		if err := doSend([]byte(message), postURL); err != nil {
+		if err := service.sendJSON(message); err != nil {
 			return fmt.Errorf("sending JSON message: %w", err)
 		}
 	} else {
 		batches := CreateItemsFromPlain(message, service.Config.SplitLines)
-		for _, items := range batches {
-			if err := service.sendItems(items, params); err != nil {
+		for i, items := range batches {
+			// Files are only uploaded together with the first batch
+			if _, err := service.sendItems(items, nil, params, i == 0); err != nil {
 				service.Log(err)
 
 				if firstErr == nil {
@@ -77,31 +78,167 @@ func (service *Service) Send(message string, params *types.Params) error {
 
 // SendItems delivers message items with enhanced metadata and formatting to Discord.
 func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
-	return service.sendItems(items, params)
+	_, err := service.sendItems(items, nil, params, true)
+
+	return err
 }
 
-func (service *Service) sendItems(items []types.MessageItem, params *types.Params) error {
-	config := *service.Config
-	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
-		return fmt.Errorf("updating config from params: %w", err)
+// PostItems delivers message items to Discord together with the files, and waits for the message to be created.
+// It returns the ID of the created message.
+func (service *Service) PostItems(
+	items []types.MessageItem,
+	files []File,
+	params *types.Params,
+) (string, error) {
+	waitParams := types.Params{}
+	if params != nil {
+		for key, value := range *params {
+			waitParams[key] = value
+		}
+	}
+
+	waitParams["wait"] = "yes"
+
+	return service.sendItems(items, files, &waitParams, true)
+}
+
+func (service *Service) sendItems(
+	items []types.MessageItem,
+	files []File,
+	params *types.Params,
+	uploadConfigFiles bool,
+) (string, error) {
+	req, _, config, err := service.createItemsRequest(items, files, params, uploadConfigFiles)
+	if err != nil {
+		return "", err
+	}
+
+	return doSend(req, config.Wait)
+}
+
+// createItemsRequest creates the request for sending the message items, returning it together with its body
+// and the config updated with the params.
+func (service *Service) createItemsRequest(
+	items []types.MessageItem,
+	files []File,
+	params *types.Params,
+	uploadConfigFiles bool,
+) (*http.Request, []byte, *Config, error) {
+	config, fields, err := service.configFromParams(params)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	payload, err := CreatePayloadFromItems(items, config.Title, config.LevelColors())
 	if err != nil {
-		return fmt.Errorf("creating payload: %w", err)
+		return nil, nil, nil, fmt.Errorf("creating payload: %w", err)
 	}
 
-	payload.Username = config.Username
-	payload.AvatarURL = config.Avatar
+	if err := applyConfig(&payload, config, fields); err != nil {
+		return nil, nil, nil, fmt.Errorf("creating payload: %w", err)
+	}
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshaling payload to JSON: %w", err)
+		return nil, nil, nil, fmt.Errorf("marshaling payload to JSON: %w", err)
+	}
+
+	if uploadConfigFiles {
+		configFiles, err := filesFromPaths(config.Files)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		files = append(configFiles, files...)
+	}
+
+	req, body, err := createRequest(payloadBytes, files, CreateAPIURLFromConfig(config))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return req, body, config, nil
+}
+
+// sendJSON sends the message as the raw JSON payload, together with any configured files.
+func (service *Service) sendJSON(message string) error {
+	req, _, err := service.createJSONRequest(message)
+	if err != nil {
+		return err
 	}
 
-	postURL := CreateAPIURLFromConfig(&config)
+	_, err = doSend(req, service.Config.Wait)
+
+	return err
+}
+
+// createJSONRequest creates the request for sending the message as the raw JSON payload,
+// returning it together with its body.
+func (service *Service) createJSONRequest(message string) (*http.Request, []byte, error) {
+	files, err := filesFromPaths(service.Config.Files)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return doSend(payloadBytes, postURL)
+	return createRequest([]byte(message), files, CreateAPIURLFromConfig(service.Config))
+}
+
+// DryRun renders the requests used for sending the message, without sending them.
+// The webhook token is redacted from the request URLs.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	secret := strings.TrimSpace(service.Config.Token)
+
+	if service.Config.JSON {
+		req, body, err := service.createJSONRequest(message)
+		if err != nil {
+			return nil, err
+		}
+
+		return []types.Request{util.RedactedRequest(req, body, secret)}, nil
+	}
+
+	batches := CreateItemsFromPlain(message, service.Config.SplitLines)
+	requests := make([]types.Request, 0, len(batches))
+
+	for i, items := range batches {
+		req, body, _, err := service.createItemsRequest(items, nil, params, i == 0)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, util.RedactedRequest(req, body, secret))
+	}
+
+	return requests, nil
+}
+
+// configFromParams returns a copy of the config updated with the params matching a config key,
+// together with the remaining params, which are added to the message as embed fields.
+func (service *Service) configFromParams(params *types.Params) (*Config, map[string]string, error) {
+	configParams := types.Params{}
+	fields := map[string]string{}
+
+	if params != nil {
+		keys := make(map[string]bool)
+		for _, key := range service.pkr.QueryFields() {
+			keys[key] = true
+		}
+
+		for key, value := range *params {
+			if keys[strings.ToLower(key)] {
+				configParams[strings.ToLower(key)] = value
+			} else {
+				fields[key] = value
+			}
+		}
+	}
+
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, &configParams); err != nil {
+		return nil, nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	return &config, fields, nil
 }
 
 // CreateItemsFromPlain converts plain text into MessageItems suitable for Discord's webhook payload.
@@ -151,7 +288,7 @@ func (service *Service) GetID() string {
 // CreateAPIURLFromConfig builds a POST URL from the Discord configuration.
 func CreateAPIURLFromConfig(config *Config) string {
 	if config.WebhookID == "" || config.Token == "" {
-		return "" // Invalid cases are caught in doSend
+		return "" // Invalid cases are caught in createRequest
 	}
 	// Trim whitespace to prevent malformed URLs
 	webhookID := strings.TrimSpace(config.WebhookID)
@@ -159,56 +296,100 @@ func CreateAPIURLFromConfig(config *Config) string {
 
 	baseURL := fmt.Sprintf("%s/%s/%s", HooksBaseURL, webhookID, token)
 
+	query := url.Values{}
+
 	if config.ThreadID != "" {
 		// Append thread_id as a query parameter
-		query := url.Values{}
 		query.Set("thread_id", strings.TrimSpace(config.ThreadID))
+	}
 
+	if config.Wait {
+		// Make the API return the created message instead of an empty response
+		query.Set("wait", "true")
+	}
+
+	if len(query) > 0 {
 		return baseURL + "?" + query.Encode()
 	}
 
 	return baseURL
 }
 
-// doSend executes an HTTP POST request to deliver the payload to Discord.
-//
-//nolint:gosec,noctx
-func doSend(payload []byte, postURL string) error {
+// createRequest creates an HTTP POST request for delivering the payload to Discord, returning it together with its body.
+// Files are uploaded using a multipart request, with the payload in the payload_json field.
+func createRequest(payload []byte, files []File, postURL string) (*http.Request, []byte, error) {
 	if postURL == "" {
-		return ErrEmptyURL
+		return nil, nil, ErrEmptyURL
 	}
 
 	parsedURL, err := url.ParseRequestURI(postURL)
 	if err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
+		return nil, nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
 	if !strings.HasPrefix(parsedURL.String(), HooksBaseURL) {
-		return ErrInvalidURLPrefix
+		return nil, nil, ErrInvalidURLPrefix
 	}
 
 	parts := strings.Split(strings.TrimPrefix(postURL, HooksBaseURL+"/"), "/")
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
-		return ErrMalformedURL
+		return nil, nil, ErrMalformedURL
 	}
 
 	webhookID := strings.TrimSpace(parts[0])
 	token := strings.TrimSpace(parts[1])
 	safeURL := fmt.Sprintf("%s/%s/%s", HooksBaseURL, webhookID, token)
 
-	res, err := http.Post(safeURL, "application/json", bytes.NewBuffer(payload))
+	body := bytes.NewBuffer(payload)
+	contentType := "application/json"
+
+	if len(files) > 0 {
+		if body, contentType, err = createMultipartBody(payload, files); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	bodyBytes := body.Bytes()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, safeURL, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return fmt.Errorf("making HTTP POST request: %w", err)
+		return nil, nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	return req, bodyBytes, nil
+}
+
+// doSend executes the HTTP POST request to deliver the payload to Discord.
+// When wait is set, the ID of the created message is returned.
+func doSend(req *http.Request, wait bool) (string, error) {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("making HTTP POST request: %w", err)
 	}
 
 	if res == nil {
-		return ErrUnknownAPIError
+		return "", ErrUnknownAPIError
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	if !wait {
+		if res.StatusCode != http.StatusNoContent {
+			return "", fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+		}
+
+		return "", nil
 	}
 
-	return nil
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	}
+
+	response := messageResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("decoding message response: %w", err)
+	}
+
+	return response.ID, nil
 }
@@ -19,24 +19,35 @@ var (
 	ErrIllegalURLArgument = errors.New("illegal argument in config URL")
 	ErrMissingWebhookID   = errors.New("webhook ID missing from config URL")
 	ErrMissingToken       = errors.New("token missing from config URL")
+	ErrInvalidMention     = errors.New("invalid mention type")
 )
 
 // Config holds the settings required for sending Discord notifications.
 type Config struct {
 	standard.EnumlessConfig
-	WebhookID  string `url:"host"`
-	Token      string `url:"user"`
-	Title      string `           default:""         key:"title"`
-	Username   string `           default:""         key:"username"         desc:"Override the webhook default username"`
-	Avatar     string `           default:""         key:"avatar,avatarurl" desc:"Override the webhook default avatar with specified URL"`
-	Color      uint   `           default:"0x50D9ff" key:"color"            desc:"The color of the left border for plain messages"                                                  base:"16"`
-	ColorError uint   `           default:"0xd60510" key:"colorError"       desc:"The color of the left border for error messages"                                                  base:"16"`
-	ColorWarn  uint   `           default:"0xffc441" key:"colorWarn"        desc:"The color of the left border for warning messages"                                                base:"16"`
-	ColorInfo  uint   `           default:"0x2488ff" key:"colorInfo"        desc:"The color of the left border for info messages"                                                   base:"16"`
-	ColorDebug uint   `           default:"0x7b00ab" key:"colorDebug"       desc:"The color of the left border for debug messages"                                                  base:"16"`
-	SplitLines bool   `           default:"Yes"      key:"splitLines"       desc:"Whether to send each line as a separate embedded item"`
-	JSON       bool   `           default:"No"       key:"json"             desc:"Whether to send the whole message as the JSON payload instead of using it as the 'content' field"`
-	ThreadID   string `           default:""         key:"thread_id"        desc:"The thread ID to send the message to"`
+	WebhookID  string   `url:"host"`
+	Token      string   `url:"user"`
+	Title      string   `           default:""         key:"title"`
+	Username   string   `           default:""         key:"username"         desc:"Override the webhook default username"`
+	Avatar     string   `           default:""         key:"avatar,avatarurl" desc:"Override the webhook default avatar with specified URL"`
+	Color      uint     `           default:"0x50D9ff" key:"color"            desc:"The color of the left border for plain messages"                                                  base:"16"`
+	ColorError uint     `           default:"0xd60510" key:"colorError"       desc:"The color of the left border for error messages"                                                  base:"16"`
+	ColorWarn  uint     `           default:"0xffc441" key:"colorWarn"        desc:"The color of the left border for warning messages"                                                base:"16"`
+	ColorInfo  uint     `           default:"0x2488ff" key:"colorInfo"        desc:"The color of the left border for info messages"                                                   base:"16"`
+	ColorDebug uint     `           default:"0x7b00ab" key:"colorDebug"       desc:"The color of the left border for debug messages"                                                  base:"16"`
+	SplitLines bool     `           default:"Yes"      key:"splitLines"       desc:"Whether to send each line as a separate embedded item"`
+	JSON       bool     `           default:"No"       key:"json"             desc:"Whether to send the whole message as the JSON payload instead of using it as the 'content' field"`
+	ThreadID   string   `           default:""         key:"thread_id"        desc:"The thread ID to send the message to"`
+	Author     string   `           default:""         key:"author"           desc:"The name of the author shown above the first embed"`
+	AuthorURL  string   `           default:""         key:"authorurl"        desc:"The URL that the author name links to"`
+	AuthorIcon string   `           default:""         key:"authoricon"       desc:"The URL of the icon shown next to the author name"`
+	Footer     string   `           default:""         key:"footer"           desc:"The footer text of the last embed"`
+	FooterIcon string   `           default:""         key:"footericon"       desc:"The URL of the icon shown next to the footer text"`
+	Thumbnail  string   `           default:""         key:"thumbnail"        desc:"The URL of the thumbnail shown in the first embed"`
+	Image      string   `           default:""         key:"image"            desc:"The URL of the image shown in the last embed"`
+	Mentions   []string `           default:""         key:"mentions"         desc:"The mention types to allow (users, roles and everyone, or none), allows all when empty"`
+	Files      []string `           default:""         key:"files"            desc:"Paths of local files to upload with the message"`
+	Wait       bool     `           default:"No"       key:"wait"             desc:"Whether to wait for the message to be created and confirmed by Discord"`
 }
 
 // LevelColors returns an array of colors indexed by MessageLevel.
@@ -0,0 +1,80 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File is a file that is uploaded together with the message.
+// Embeds can reference an uploaded image using the URL "attachment://<name>".
+type File struct {
+	Name   string
+	Reader io.Reader
+}
+
+// FileFromPath creates a File by reading the local file at the given path.
+func FileFromPath(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("reading file %q: %w", path, err)
+	}
+
+	return File{
+		Name:   filepath.Base(path),
+		Reader: bytes.NewReader(data),
+	}, nil
+}
+
+// filesFromPaths creates Files from the configured paths, skipping any empty entries.
+func filesFromPaths(paths []string) ([]File, error) {
+	files := make([]File, 0, len(paths))
+
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		file, err := FileFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// createMultipartBody creates a multipart/form-data request body containing the JSON payload and the files.
+// It returns the body together with the content type, which includes the multipart boundary.
+func createMultipartBody(payload []byte, files []File) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	if err := writer.WriteField("payload_json", string(payload)); err != nil {
+		return nil, "", fmt.Errorf("writing payload field: %w", err)
+	}
+
+	for i, file := range files {
+		part, err := writer.CreateFormFile(fmt.Sprintf("files[%d]", i), file.Name)
+		if err != nil {
+			return nil, "", fmt.Errorf("creating form file %q: %w", file.Name, err)
+		}
+
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return nil, "", fmt.Errorf("writing form file %q: %w", file.Name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
@@ -2,6 +2,10 @@ package discord
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
@@ -9,17 +13,24 @@ import (
 )
 
 const (
-	MaxEmbeds = 9
+	MaxEmbeds      = 9
+	MaxEmbedFields = 25 // Maximum number of fields in a single embed
+	maxFieldName   = 256
+	maxFieldValue  = 1024
 )
 
 // Static error definition.
 var ErrEmptyMessage = errors.New("message is empty")
 
+// mentionTypes are the mention types that can be allowed to be parsed from the message content.
+var mentionTypes = []string{"users", "roles", "everyone"}
+
 // WebhookPayload is the webhook endpoint payload.
 type WebhookPayload struct {
-	Embeds    []embedItem `json:"embeds"`
-	Username  string      `json:"username,omitempty"`
-	AvatarURL string      `json:"avatar_url,omitempty"`
+	Embeds          []embedItem      `json:"embeds"`
+	Username        string           `json:"username,omitempty"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	AllowedMentions *allowedMentions `json:"allowed_mentions,omitempty"`
 }
 
 // JSON is the actual notification payload.
@@ -30,6 +41,10 @@ type embedItem struct {
 	Timestamp string       `json:"timestamp,omitempty"`
 	Color     uint         `json:"color,omitempty"`
 	Footer    *embedFooter `json:"footer,omitempty"`
+	Author    *embedAuthor `json:"author,omitempty"`
+	Thumbnail *embedImage  `json:"thumbnail,omitempty"`
+	Image     *embedImage  `json:"image,omitempty"`
+	Fields    []embedField `json:"fields,omitempty"`
 }
 
 type embedFooter struct {
@@ -37,6 +52,33 @@ type embedFooter struct {
 	IconURL string `json:"icon_url,omitempty"`
 }
 
+type embedAuthor struct {
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type embedImage struct {
+	URL string `json:"url"`
+}
+
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// allowedMentions controls which mentions in the message content will notify the mentioned users.
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// messageResponse is the message object returned by the API when waiting for the message to be created.
+type messageResponse struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+}
+
 // CreatePayloadFromItems creates a JSON payload to be sent to the discord webhook API.
 func CreatePayloadFromItems(
 	items []types.MessageItem,
@@ -72,6 +114,10 @@ func CreatePayloadFromItems(
 			embeddedItem.Timestamp = item.Timestamp.UTC().Format(time.RFC3339)
 		}
 
+		for _, field := range item.Fields {
+			embeddedItem.addField(field.Key, field.Value)
+		}
+
 		embeds = append(embeds, embeddedItem)
 	}
 
@@ -84,3 +130,117 @@ func CreatePayloadFromItems(
 		Embeds: embeds,
 	}, nil
 }
+
+// applyConfig adds the author, thumbnail, image, footer, extra fields and allowed mentions from the config
+// to the payload. The author and thumbnail are set on the first embed, while the rest are set on the last one.
+func applyConfig(payload *WebhookPayload, config *Config, fields map[string]string) error {
+	payload.Username = config.Username
+	payload.AvatarURL = config.Avatar
+
+	mentions, err := parseMentions(config.Mentions)
+	if err != nil {
+		return err
+	}
+
+	payload.AllowedMentions = mentions
+
+	if len(payload.Embeds) < 1 {
+		return nil
+	}
+
+	first := &payload.Embeds[0]
+	last := &payload.Embeds[len(payload.Embeds)-1]
+
+	if config.Author != "" {
+		first.Author = &embedAuthor{
+			Name:    config.Author,
+			URL:     config.AuthorURL,
+			IconURL: config.AuthorIcon,
+		}
+	}
+
+	if config.Thumbnail != "" {
+		first.Thumbnail = &embedImage{URL: config.Thumbnail}
+	}
+
+	if config.Image != "" {
+		last.Image = &embedImage{URL: config.Image}
+	}
+
+	if config.Footer != "" {
+		footer := embedFooter{Text: config.Footer, IconURL: config.FooterIcon}
+		if last.Footer != nil {
+			footer.Text = last.Footer.Text + " | " + footer.Text
+		}
+
+		last.Footer = &footer
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		last.addField(key, fields[key])
+	}
+
+	return nil
+}
+
+// addField adds an inline field to the embed, unless the embed already has the maximum number of fields.
+func (e *embedItem) addField(name, value string) {
+	if len(e.Fields) >= MaxEmbedFields {
+		return
+	}
+
+	e.Fields = append(e.Fields, embedField{
+		Name:   truncate(name, maxFieldName),
+		Value:  truncate(value, maxFieldValue),
+		Inline: true,
+	})
+}
+
+// parseMentions creates the allowed mentions of the payload from the configured mention types.
+// An empty list allows all mentions, which is the Discord default, while "none" disables all mentions.
+func parseMentions(mentions []string) (*allowedMentions, error) {
+	parse := make([]string, 0, len(mentionTypes))
+	restricted := false
+
+	for _, mention := range mentions {
+		mention = strings.ToLower(strings.TrimSpace(mention))
+		if mention == "" {
+			continue
+		}
+
+		restricted = true
+
+		if mention == "none" {
+			continue
+		}
+
+		if !slices.Contains(mentionTypes, mention) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMention, mention)
+		}
+
+		parse = append(parse, mention)
+	}
+
+	if !restricted {
+		return nil, nil
+	}
+
+	return &allowedMentions{Parse: parse}, nil
+}
+
+// truncate shortens the text to at most limit runes.
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit])
+}
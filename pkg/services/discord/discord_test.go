@@ -1,10 +1,15 @@
 package discord_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -281,6 +286,87 @@ var _ = ginkgo.Describe("the discord service", func() {
 				gomega.Expect(service.Send("Message", nil)).NotTo(gomega.Succeed())
 			})
 		})
+		ginkgo.It("should add the embed options and extra params to the payload", func() {
+			var payload map[string]any
+			respondCapturingJSON("https://discord.com/api/webhooks/1/dummyToken", http.StatusNoContent, &payload)
+
+			items := []types.MessageItem{
+				{Text: "First", Fields: []types.Field{{Key: "Host", Value: "web01"}}},
+				{Text: "Second", Level: types.Warning},
+			}
+			gomega.Expect(service.SendItems(items, &types.Params{
+				"author":    "Monitor",
+				"thumbnail": "https://example.com/thumb.png",
+				"image":     "https://example.com/graph.png",
+				"footer":    "via shoutrrr",
+				"mentions":  "none",
+				"Region":    "eu-west",
+			})).To(gomega.Succeed())
+
+			payloadJSON, err := json.Marshal(payload)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(payloadJSON).To(gomega.MatchJSON(`{
+				"allowed_mentions": {"parse": []},
+				"embeds": [
+					{
+						"description": "First",
+						"author": {"name": "Monitor"},
+						"thumbnail": {"url": "https://example.com/thumb.png"},
+						"fields": [{"name": "Host", "value": "web01", "inline": true}]
+					},
+					{
+						"description": "Second",
+						"footer": {"text": "Warning | via shoutrrr"},
+						"image": {"url": "https://example.com/graph.png"},
+						"fields": [{"name": "Region", "value": "eu-west", "inline": true}]
+					}
+				]
+			}`))
+		})
+		ginkgo.It("should report an error if a mention type is invalid", func() {
+			setupResponder(&dummyConfig, 204)
+			gomega.Expect(service.Send("Message", &types.Params{"mentions": "channels"})).
+				To(gomega.MatchError(gomega.ContainSubstring(discord.ErrInvalidMention.Error())))
+		})
+		ginkgo.It("should upload files using a multipart request", func() {
+			filePath := filepath.Join(ginkgo.GinkgoT().TempDir(), "report.txt")
+			gomega.Expect(os.WriteFile(filePath, []byte("report contents"), 0o600)).To(gomega.Succeed())
+
+			var form *multipart.Form
+			httpmock.RegisterResponder(
+				"POST",
+				"https://discord.com/api/webhooks/1/dummyToken",
+				func(req *http.Request) (*http.Response, error) {
+					gomega.Expect(req.ParseMultipartForm(1 << 20)).To(gomega.Succeed())
+					form = req.MultipartForm
+
+					return httpmock.NewStringResponse(http.StatusNoContent, ""), nil
+				},
+			)
+
+			gomega.Expect(service.Send("Message", &types.Params{"files": filePath})).To(gomega.Succeed())
+			gomega.Expect(form.Value["payload_json"]).To(gomega.HaveLen(1))
+			gomega.Expect(form.Value["payload_json"][0]).To(gomega.ContainSubstring(`"description":"Message"`))
+			gomega.Expect(form.File["files[0]"]).To(gomega.HaveLen(1))
+			gomega.Expect(form.File["files[0]"][0].Filename).To(gomega.Equal("report.txt"))
+		})
+		ginkgo.It("should return the message ID when waiting for the message to be created", func() {
+			httpmock.RegisterResponder(
+				"POST",
+				"https://discord.com/api/webhooks/1/dummyToken?wait=true",
+				testutils.JSONRespondMust(http.StatusOK, map[string]string{
+					"id":         "1234567890",
+					"channel_id": "42",
+				}),
+			)
+
+			messageID, err := service.PostItems([]types.MessageItem{{Text: "Message"}}, []discord.File{{
+				Name:   "log.txt",
+				Reader: strings.NewReader("log contents"),
+			}}, nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(messageID).To(gomega.Equal("1234567890"))
+		})
 		ginkgo.It("should trim whitespace from thread_id in API URL", func() {
 			config := discord.Config{
 				WebhookID: "1",
@@ -300,6 +386,16 @@ var _ = ginkgo.Describe("the discord service", func() {
 			gomega.Expect(targetURL).
 				To(gomega.Equal("https://discord.com/api/webhooks/1/dummyToken?thread_id=123456789"))
 		})
+		ginkgo.It("should render the request with the token redacted when doing a dry run", func() {
+			requests, err := service.DryRun("Message", nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].Method).To(gomega.Equal("POST"))
+			gomega.Expect(requests[0].URL).To(gomega.Equal("https://discord.com/api/webhooks/1/REDACTED"))
+			gomega.Expect(requests[0].Headers.Get("Content-Type")).To(gomega.Equal("application/json"))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"description":"Message"`))
+		})
 	})
 })
 
@@ -330,3 +426,14 @@ func setupResponder(config *discord.Config, code int) {
 	targetURL := discord.CreateAPIURLFromConfig(config)
 	httpmock.RegisterResponder("POST", targetURL, httpmock.NewStringResponder(code, ""))
 }
+
+// respondCapturingJSON registers a responder for the URL that decodes the posted JSON payload into target.
+func respondCapturingJSON(targetURL string, code int, target any) {
+	httpmock.RegisterResponder("POST", targetURL, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(json.Unmarshal(body, target)).To(gomega.Succeed())
+
+		return httpmock.NewStringResponse(code, ""), nil
+	})
+}
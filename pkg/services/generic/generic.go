@@ -10,10 +10,15 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // JSONTemplate identifies the JSON format for webhook payloads.
@@ -31,12 +36,54 @@ var (
 // Service implements a generic notification service for custom webhooks.
 type Service struct {
 	standard.Standard
-	Config *Config
-	pkr    format.PropKeyResolver
+	Config      *Config
+	pkr         format.PropKeyResolver
+	tokenSource oauth2.TokenSource
+	tokenKey    string
+	tokenMutex  sync.Mutex
 }
 
 // Send delivers a notification message to a generic webhook endpoint.
 func (service *Service) Send(message string, paramsPtr *types.Params) error {
+	_, err := service.Post(message, paramsPtr)
+
+	return err
+}
+
+// Post delivers a notification message to a generic webhook endpoint, and returns the response.
+// The response contains the fields captured from the response body, when the capture prop is set.
+func (service *Service) Post(message string, paramsPtr *types.Params) (*Response, error) {
+	config, sendParams, err := service.prepareSend(message, paramsPtr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+
+	response, err := service.doSend(config, sendParams)
+	if err != nil {
+		return response, fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+
+	return response, nil
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// Credentials are redacted, and no OAuth2 access token is requested.
+func (service *Service) DryRun(message string, paramsPtr *types.Params) ([]types.Request, error) {
+	config, sendParams, err := service.prepareSend(message, paramsPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	req, body, err := service.createRequest(context.Background(), config, sendParams, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{util.RedactedRequest(req, body, config.secrets()...)}, nil
+}
+
+// prepareSend returns a copy of the config updated with the params, together with the params for the payload.
+func (service *Service) prepareSend(message string, paramsPtr *types.Params) (*Config, types.Params, error) {
 	config := *service.Config
 
 	var params types.Params
@@ -50,12 +97,11 @@ func (service *Service) Send(message string, paramsPtr *types.Params) error {
 		service.Logf("Failed to update params: %v", err)
 	}
 
-	sendParams := createSendParams(&config, params, message)
-	if err := service.doSend(&config, sendParams); err != nil {
-		return fmt.Errorf("%w: %s", ErrSendFailed, err.Error())
+	if err := config.validate(); err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	return &config, createSendParams(&config, params, message), nil
 }
 
 // Initialize configures the service with a URL and logger.
@@ -65,8 +111,13 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	config, pkr := DefaultConfig()
 	service.Config = config
 	service.pkr = pkr
+	service.tokenSource = nil
 
-	return service.Config.setURL(&service.pkr, configURL)
+	if err := service.Config.setURL(&service.pkr, configURL); err != nil {
+		return err
+	}
+
+	return service.Config.validate()
 }
 
 // GetID returns the identifier for this service.
@@ -89,78 +140,150 @@ func (*Service) GetConfigURLFromCustom(customURL *url.URL) (*url.URL, error) {
 	return config.getURL(&pkr), nil
 }
 
-// doSend executes the HTTP request to send a notification to the webhook.
-func (service *Service) doSend(config *Config, params types.Params) error {
-	postURL := config.WebhookURL().String()
+// doSend executes the HTTP request to send a notification to the webhook, and checks the response.
+func (service *Service) doSend(config *Config, params types.Params) (*Response, error) {
+	req, _, err := service.createRequest(context.Background(), config, params, false)
+	if err != nil {
+		return nil, err
+	}
 
-	payload, err := service.GetPayload(config, params)
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("sending HTTP request: %w", err)
 	}
 
-	ctx := context.Background()
+	defer res.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, config.RequestMethod, postURL, payload)
+	resBody, err := io.ReadAll(res.Body)
 	if err != nil {
-		return fmt.Errorf("creating HTTP request: %w", err)
+		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
-	req.Header.Set("Content-Type", config.ContentType)
-	req.Header.Set("Accept", config.ContentType)
+	service.Log("Server response: ", string(resBody))
 
-	for key, value := range config.headers {
-		req.Header.Set(key, value)
+	return checkResponse(config, res, resBody)
+}
+
+// createRequest creates the HTTP request for sending a notification to the webhook, returning it together with its body.
+// For dry runs, a placeholder is used instead of requesting an OAuth2 access token.
+func (service *Service) createRequest(
+	ctx context.Context,
+	config *Config,
+	params types.Params,
+	dryRun bool,
+) (*http.Request, []byte, error) {
+	webhookURL := config.WebhookURL()
+
+	var body []byte
+
+	contentType := ""
+
+	if config.payloadInQuery() {
+		// The params are added to the query of the webhook URL, and the request is sent without a body
+		query := webhookURL.Query()
+		for key, value := range payloadValues(config, params) {
+			query.Set(key, value)
+		}
+
+		webhookURL.RawQuery = query.Encode()
+	} else {
+		payload, payloadType, err := service.getPayload(config, params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The payload is read in full, since the signature needs to be calculated from it
+		if body, err = io.ReadAll(payload); err != nil {
+			return nil, nil, fmt.Errorf("reading payload: %w", err)
+		}
+
+		contentType = payloadType
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	req, err := http.NewRequestWithContext(ctx, config.RequestMethod, webhookURL.String(), bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("sending HTTP request: %w", err)
+		return nil, nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
-	if res != nil && res.Body != nil {
-		defer res.Body.Close()
+	req.Header.Set("Accept", config.ContentType)
 
-		if body, err := io.ReadAll(res.Body); err == nil {
-			service.Log("Server response: ", string(body))
-		}
+	if dryRun && config.TokenURL != "" {
+		req.Header.Set("Authorization", "Bearer "+util.RedactedValue)
+	} else if err := service.authenticate(ctx, config, req); err != nil {
+		return nil, nil, err
+	}
+
+	if config.SigningSecret != "" {
+		sign(config, req, body, time.Now())
 	}
 
-	if res.StatusCode >= http.StatusMultipleChoices {
-		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	for key, value := range config.headers {
+		req.Header.Set(key, value)
 	}
 
-	return nil
+	return req, body, nil
 }
 
 // GetPayload prepares the request payload based on the configured template.
 func (service *Service) GetPayload(config *Config, params types.Params) (io.Reader, error) {
-	switch config.Template {
+	payload, _, err := service.getPayload(config, params)
+
+	return payload, err
+}
+
+// getPayload prepares the request payload based on the configured template,
+// and returns it together with its content type.
+func (service *Service) getPayload(config *Config, params types.Params) (io.Reader, string, error) {
+	switch strings.ToLower(config.Template) {
 	case "":
-		return bytes.NewBufferString(params[config.MessageKey]), nil
-	case "json", JSONTemplate:
-		for key, value := range config.extraData {
-			params[key] = value
+		return bytes.NewBufferString(params[config.MessageKey]), config.ContentType, nil
+	case "json":
+		values := payloadValues(config, params)
+
+		var object any = values
+
+		if config.NestKeys {
+			nested, err := nestValues(values)
+			if err != nil {
+				return nil, "", err
+			}
+
+			object = nested
 		}
 
-		jsonBytes, err := json.Marshal(params)
+		jsonBytes, err := json.Marshal(object)
 		if err != nil {
-			return nil, fmt.Errorf("marshaling params to JSON: %w", err)
+			return nil, "", fmt.Errorf("marshaling params to JSON: %w", err)
+		}
+
+		return bytes.NewBuffer(jsonBytes), config.ContentType, nil
+	case FormTemplate:
+		// The content type is only kept when it has been changed from the JSON default
+		contentType := config.ContentType
+		if contentType == defaultContentType {
+			contentType = formContentType
 		}
 
-		return bytes.NewBuffer(jsonBytes), nil
+		return strings.NewReader(encodeForm(payloadValues(config, params)).Encode()), contentType, nil
+	case MultipartTemplate:
+		return createMultipart(payloadValues(config, params))
 	}
 
 	tpl, found := service.GetTemplate(config.Template)
 	if !found {
-		return nil, fmt.Errorf("%w: %q", ErrTemplateNotLoaded, config.Template)
+		return nil, "", fmt.Errorf("%w: %q", ErrTemplateNotLoaded, config.Template)
 	}
 
 	bb := &bytes.Buffer{}
 	if err := tpl.Execute(bb, params); err != nil {
-		return nil, fmt.Errorf("executing template %q: %w", config.Template, err)
+		return nil, "", fmt.Errorf("executing template %q: %w", config.Template, err)
 	}
 
-	return bb, nil
+	return bb, config.ContentType, nil
 }
 
 // createSendParams constructs parameters for sending a notification.
@@ -0,0 +1,178 @@
+package generic
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // SHA-1 is still used for HMAC signatures by some webhook receivers
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Signature formats, selecting the signed content and the value of the signature header.
+const (
+	// SignatureHex signs the payload, using the hex encoded signature as the header value.
+	SignatureHex = "hex"
+	// SignaturePrefixed signs the payload, prefixing the hex encoded signature with the algorithm, as in `sha256=<hex>`.
+	SignaturePrefixed = "prefixed"
+	// SignatureTimestamped signs the current unix time and the payload joined by a dot,
+	// using `t=<time>,v1=<hex>` as the header value.
+	SignatureTimestamped = "timestamped"
+)
+
+// Errors returned for invalid authentication and signing settings.
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnsupportedFormat    = errors.New("unsupported signature format")
+	ErrConflictingAuth      = errors.New("only one of bearer, basic and OAuth2 authentication can be used")
+	ErrMissingClientID      = errors.New("a client ID is needed to obtain an OAuth2 access token")
+)
+
+// signingHashes maps the supported signing algorithms to their hash functions.
+var signingHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// validate checks that the config can be used for sending.
+func (config *Config) validate() error {
+	if err := config.validateAuth(); err != nil {
+		return err
+	}
+
+	if err := config.validatePayloadProps(); err != nil {
+		return err
+	}
+
+	return config.validateResponseProps()
+}
+
+// validateAuth checks that the authentication and signing settings can be used for sending.
+func (config *Config) validateAuth() error {
+	modes := 0
+
+	for _, enabled := range []bool{config.BearerToken != "", config.BasicUser != "", config.TokenURL != ""} {
+		if enabled {
+			modes++
+		}
+	}
+
+	if modes > 1 {
+		return ErrConflictingAuth
+	}
+
+	if config.TokenURL != "" && config.ClientID == "" {
+		return ErrMissingClientID
+	}
+
+	if config.SigningSecret == "" {
+		return nil
+	}
+
+	if _, found := signingHashes[strings.ToLower(config.SigningAlgorithm)]; !found {
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, config.SigningAlgorithm)
+	}
+
+	switch strings.ToLower(config.SignatureFormat) {
+	case SignatureHex, SignaturePrefixed, SignatureTimestamped:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, config.SignatureFormat)
+	}
+}
+
+// authenticate adds the Authorization header for the configured authentication mode to the request.
+func (service *Service) authenticate(ctx context.Context, config *Config, req *http.Request) error {
+	switch {
+	case config.TokenURL != "":
+		token, err := service.getToken(ctx, config)
+		if err != nil {
+			return err
+		}
+
+		token.SetAuthHeader(req)
+	case config.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	case config.BasicUser != "":
+		req.SetBasicAuth(config.BasicUser, config.BasicPassword)
+	}
+
+	return nil
+}
+
+// getToken returns an access token obtained with the OAuth2 client credentials grant.
+// The token source is kept between sends, so that a token is only requested again when it has expired.
+func (service *Service) getToken(ctx context.Context, config *Config) (*oauth2.Token, error) {
+	credentials := clientcredentials.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		TokenURL:     config.TokenURL,
+		Scopes:       nonEmpty(config.Scopes),
+	}
+
+	service.tokenMutex.Lock()
+	defer service.tokenMutex.Unlock()
+
+	key := strings.Join(
+		append([]string{credentials.TokenURL, credentials.ClientID, credentials.ClientSecret}, credentials.Scopes...),
+		"\n",
+	)
+	if service.tokenSource == nil || service.tokenKey != key {
+		// The context of the token source is only used for its HTTP client, not for cancellation
+		service.tokenSource = credentials.TokenSource(context.WithoutCancel(ctx))
+		service.tokenKey = key
+	}
+
+	token, err := service.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("getting OAuth2 access token: %w", err)
+	}
+
+	return token, nil
+}
+
+// sign adds the HMAC signature of the payload to the request.
+func sign(config *Config, req *http.Request, payload []byte, now time.Time) {
+	algorithm := strings.ToLower(config.SigningAlgorithm)
+	mac := hmac.New(signingHashes[algorithm], []byte(config.SigningSecret))
+
+	var value string
+
+	switch strings.ToLower(config.SignatureFormat) {
+	case SignatureTimestamped:
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(payload)
+		value = "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+	case SignaturePrefixed:
+		mac.Write(payload)
+		value = algorithm + "=" + hex.EncodeToString(mac.Sum(nil))
+	default:
+		mac.Write(payload)
+		value = hex.EncodeToString(mac.Sum(nil))
+	}
+
+	req.Header.Set(config.SignatureHeader, value)
+}
+
+// secrets returns the credentials in the config, which are redacted from rendered requests.
+func (config *Config) secrets() []string {
+	secrets := []string{config.BearerToken, config.BasicPassword, config.ClientSecret, config.SigningSecret}
+
+	if password, found := config.webhookURL.User.Password(); found {
+		secrets = append(secrets, password)
+	}
+
+	return secrets
+}
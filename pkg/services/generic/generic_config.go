@@ -3,6 +3,7 @@ package generic
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
@@ -18,16 +19,33 @@ const (
 // Config holds settings for the generic notification service.
 type Config struct {
 	standard.EnumlessConfig
-	webhookURL    *url.URL
-	headers       map[string]string
-	extraData     map[string]string
-	ContentType   string `default:"application/json" desc:"The value of the Content-Type header"               key:"contenttype"`
-	DisableTLS    bool   `default:"No"                                                                         key:"disabletls"`
-	Template      string `                           desc:"The template used for creating the request payload" key:"template"    optional:""`
-	Title         string `default:""                                                                           key:"title"`
-	TitleKey      string `default:"title"            desc:"The key that will be used for the title value"      key:"titlekey"`
-	MessageKey    string `default:"message"          desc:"The key that will be used for the message value"    key:"messagekey"`
-	RequestMethod string `default:"POST"                                                                       key:"method"`
+	webhookURL       *url.URL
+	headers          map[string]string
+	extraData        map[string]string
+	ContentType      string   `default:"application/json" desc:"The value of the Content-Type header"                               key:"contenttype"`
+	DisableTLS       bool     `default:"No"                                                                                         key:"disabletls"`
+	Template         string   `                           desc:"The template used for creating the request payload"                 key:"template"      optional:""`
+	NestKeys         bool     `default:"No"               desc:"Create nested objects from dotted keys in the JSON payload"         key:"nestkeys"`
+	Title            string   `default:""                                                                                           key:"title"`
+	TitleKey         string   `default:"title"            desc:"The key that will be used for the title value"                      key:"titlekey"`
+	MessageKey       string   `default:"message"          desc:"The key that will be used for the message value"                    key:"messagekey"`
+	RequestMethod    string   `default:"POST"                                                                                       key:"method"`
+	PayloadIn        string   `default:"auto"             desc:"Where the payload is sent (auto, body or query)"                    key:"payloadin"`
+	BearerToken      string   `                           desc:"Token sent in a bearer Authorization header"                        key:"bearertoken"   optional:""`
+	BasicUser        string   `                           desc:"Username used for basic authentication"                             key:"basicuser"     optional:""`
+	BasicPassword    string   `                           desc:"Password used for basic authentication"                             key:"basicpassword" optional:""`
+	TokenURL         string   `                           desc:"OAuth2 token endpoint for the client credentials grant"             key:"tokenurl"      optional:""`
+	ClientID         string   `                           desc:"OAuth2 client ID for the client credentials grant"                  key:"clientid"      optional:""`
+	ClientSecret     string   `                           desc:"OAuth2 client secret for the client credentials grant"              key:"clientsecret"  optional:""`
+	Scopes           []string `                           desc:"OAuth2 scopes requested with the client credentials grant"          key:"scopes"        optional:""`
+	SigningSecret    string   `                           desc:"Secret used for signing the request with HMAC"                      key:"signingsecret" optional:""`
+	SigningAlgorithm string   `default:"sha256"           desc:"Hash function used for the HMAC signature (sha1, sha256 or sha512)" key:"signingalgorithm"`
+	SignatureHeader  string   `default:"X-Signature"      desc:"Name of the header containing the signature"                        key:"signatureheader"`
+	SignatureFormat  string   `default:"prefixed"         desc:"Signed content and signature format (hex, prefixed or timestamped)" key:"signatureformat"`
+	ExpectStatus     []string `                           desc:"Accepted response status codes, like 200, 200-204 or 2xx"           key:"expectstatus"  optional:""`
+	Expect           []string `                           desc:"Conditions on fields of the JSON response, like ok == true"         key:"expect"        optional:""`
+	ExpectMatch      string   `                           desc:"Regular expression that the response body must match"               key:"expectmatch"   optional:""`
+	Capture          []string `                           desc:"Response fields returned in the result, as name=path or path"       key:"capture"       optional:""`
 }
 
 // DefaultConfig creates a new Config with default values and its associated PropKeyResolver.
@@ -121,3 +139,16 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, serviceURL *url
 
 	return nil
 }
+
+// nonEmpty returns the values that are not blank, since list props without a value contain an empty entry.
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
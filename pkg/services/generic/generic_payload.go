@@ -0,0 +1,138 @@
+package generic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// Built in templates for form encoded payloads.
+const (
+	FormTemplate      = "form"      // FormTemplate sends the params as an application/x-www-form-urlencoded body.
+	MultipartTemplate = "multipart" // MultipartTemplate sends the params as a multipart/form-data body.
+)
+
+// Locations for sending the payload, used by the payloadin prop.
+const (
+	// PayloadInAuto sends the params in the query for GET and HEAD requests, and in the body otherwise.
+	PayloadInAuto = "auto"
+	// PayloadInBody always sends the payload in the request body.
+	PayloadInBody = "body"
+	// PayloadInQuery always sends the params in the query of the request URL.
+	PayloadInQuery = "query"
+)
+
+const (
+	defaultContentType = "application/json"
+	formContentType    = "application/x-www-form-urlencoded"
+)
+
+// Errors returned when creating the payload.
+var (
+	ErrConflictingKeys    = errors.New("conflicting nested JSON keys")
+	ErrUnsupportedPayload = errors.New("unsupported payload location")
+)
+
+// payloadInQuery reports whether the params are sent in the query of the request URL instead of the body.
+func (config *Config) payloadInQuery() bool {
+	switch strings.ToLower(config.PayloadIn) {
+	case PayloadInQuery:
+		return true
+	case PayloadInBody:
+		return false
+	default:
+		return config.RequestMethod == http.MethodGet || config.RequestMethod == http.MethodHead
+	}
+}
+
+// validatePayloadProps checks that the payload location is supported.
+func (config *Config) validatePayloadProps() error {
+	switch strings.ToLower(config.PayloadIn) {
+	case PayloadInAuto, PayloadInBody, PayloadInQuery:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedPayload, config.PayloadIn)
+	}
+}
+
+// payloadValues returns the params together with the extra data from the config.
+func payloadValues(config *Config, params types.Params) map[string]string {
+	values := make(map[string]string, len(params)+len(config.extraData))
+	maps.Copy(values, params)
+	maps.Copy(values, config.extraData)
+
+	return values
+}
+
+// nestValues creates a JSON object from the values, where dots in the keys separate the keys of nested objects.
+// A key like `data.user.name` results in `{"data": {"user": {"name": value}}}`. It is used when nestkeys is enabled.
+func nestValues(values map[string]string) (map[string]any, error) {
+	root := map[string]any{}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		segments := strings.Split(key, ".")
+		object := root
+
+		for _, segment := range segments[:len(segments)-1] {
+			child, found := object[segment]
+			if !found {
+				child = map[string]any{}
+				object[segment] = child
+			}
+
+			nested, isObject := child.(map[string]any)
+			if !isObject {
+				return nil, fmt.Errorf("%w: %q", ErrConflictingKeys, key)
+			}
+
+			object = nested
+		}
+
+		last := segments[len(segments)-1]
+		if _, found := object[last]; found {
+			return nil, fmt.Errorf("%w: %q", ErrConflictingKeys, key)
+		}
+
+		object[last] = values[key]
+	}
+
+	return root, nil
+}
+
+// encodeForm returns the values encoded as a form, with the keys sorted.
+func encodeForm(values map[string]string) url.Values {
+	form := url.Values{}
+	for key, value := range values {
+		form.Set(key, value)
+	}
+
+	return form
+}
+
+// createMultipart returns the values as multipart/form-data fields,
+// together with the content type including the boundary.
+func createMultipart(values map[string]string) (io.Reader, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if err := writer.WriteField(key, values[key]); err != nil {
+			return nil, "", fmt.Errorf("writing multipart field %q: %w", key, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
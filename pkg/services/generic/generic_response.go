@@ -0,0 +1,293 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Errors returned when the response does not fulfill the configured expectations.
+var (
+	ErrInvalidStatusRange = errors.New("invalid expected status")
+	ErrInvalidCondition   = errors.New("invalid response condition")
+	ErrConditionFailed    = errors.New("response condition not fulfilled")
+	ErrBodyMismatch       = errors.New("response body does not match the expected pattern")
+	ErrFieldNotFound      = errors.New("field not found in response")
+	ErrInvalidResponse    = errors.New("response body is not valid JSON")
+)
+
+var (
+	conditionPattern = regexp.MustCompile(`^\s*(.+?)\s*(==|!=)\s*(.*?)\s*$`)
+	pathIndexPattern = regexp.MustCompile(`\[(\d+)\]`)
+)
+
+const statusClassSuffix = "xx"
+
+// Response contains the result of sending a notification to the webhook.
+type Response struct {
+	StatusCode int
+	// Fields contains the values captured from the response body, using the names from the capture prop.
+	Fields map[string]string
+}
+
+// condition is an assertion on a field of the JSON response body.
+type condition struct {
+	path     string
+	operator string
+	value    string
+}
+
+// parseCondition parses conditions like `ok == true` or `error.code != 5`.
+// Without an operator, the condition requires the field to have a truthy value.
+func parseCondition(text string) (condition, error) {
+	match := conditionPattern.FindStringSubmatch(text)
+	if match == nil {
+		path := strings.TrimSpace(text)
+		if path == "" {
+			return condition{}, fmt.Errorf("%w: %q", ErrInvalidCondition, text)
+		}
+
+		return condition{path: path}, nil
+	}
+
+	value := match[3]
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	} else if len(value) > 1 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = value[1 : len(value)-1]
+	}
+
+	return condition{path: match[1], operator: match[2], value: value}, nil
+}
+
+// check returns an error if the condition is not fulfilled by the JSON document.
+func (cond condition) check(document any) error {
+	value, found := lookupPath(document, cond.path)
+
+	switch cond.operator {
+	case "":
+		if !found || !truthy(value) {
+			return fmt.Errorf("%w: %s", ErrConditionFailed, cond.path)
+		}
+	case "==":
+		if !found || formatValue(value) != cond.value {
+			return fmt.Errorf("%w: %s == %s (got %s)", ErrConditionFailed, cond.path, cond.value, formatFound(value, found))
+		}
+	case "!=":
+		if found && formatValue(value) == cond.value {
+			return fmt.Errorf("%w: %s != %s", ErrConditionFailed, cond.path, cond.value)
+		}
+	}
+
+	return nil
+}
+
+// statusMatches reports whether the status code is matched by the entries, which are either
+// single codes like `200`, ranges like `200-204`, or classes like `2xx`.
+func statusMatches(entries []string, status int) bool {
+	for _, entry := range entries {
+		low, high, _ := parseStatusRange(entry)
+		if low <= status && status <= high {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseStatusRange returns the lowest and highest status code matched by the entry.
+func parseStatusRange(entry string) (int, int, error) {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+
+	if class, isClass := strings.CutSuffix(entry, statusClassSuffix); isClass {
+		digit, err := strconv.Atoi(class)
+		if err != nil || len(class) != 1 {
+			return 0, 0, fmt.Errorf("%w: %q", ErrInvalidStatusRange, entry)
+		}
+
+		return digit * 100, digit*100 + 99, nil
+	}
+
+	lowText, highText, isRange := strings.Cut(entry, "-")
+	if !isRange {
+		highText = lowText
+	}
+
+	low, lowErr := strconv.Atoi(lowText)
+	high, highErr := strconv.Atoi(highText)
+
+	if lowErr != nil || highErr != nil || low > high {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidStatusRange, entry)
+	}
+
+	return low, high, nil
+}
+
+// validateResponseProps checks that the response expectations and captures can be parsed.
+func (config *Config) validateResponseProps() error {
+	for _, entry := range nonEmpty(config.ExpectStatus) {
+		if _, _, err := parseStatusRange(entry); err != nil {
+			return err
+		}
+	}
+
+	for _, text := range nonEmpty(config.Expect) {
+		if _, err := parseCondition(text); err != nil {
+			return err
+		}
+	}
+
+	if config.ExpectMatch != "" {
+		if _, err := regexp.Compile(config.ExpectMatch); err != nil {
+			return fmt.Errorf("compiling expected body pattern: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkResponse verifies the response against the configured expectations, and captures the configured fields.
+func checkResponse(config *Config, res *http.Response, body []byte) (*Response, error) {
+	response := &Response{StatusCode: res.StatusCode, Fields: map[string]string{}}
+	expectStatus := nonEmpty(config.ExpectStatus)
+	expect := nonEmpty(config.Expect)
+	capture := nonEmpty(config.Capture)
+
+	if len(expectStatus) > 0 {
+		if !statusMatches(expectStatus, res.StatusCode) {
+			return response, fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+		}
+	} else if res.StatusCode >= http.StatusMultipleChoices {
+		return response, fmt.Errorf("%w: %s", ErrUnexpectedStatus, res.Status)
+	}
+
+	if config.ExpectMatch != "" && !regexp.MustCompile(config.ExpectMatch).Match(body) {
+		return response, fmt.Errorf("%w: %s", ErrBodyMismatch, config.ExpectMatch)
+	}
+
+	if len(expect) == 0 && len(capture) == 0 {
+		return response, nil
+	}
+
+	var document any
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&document); err != nil {
+		return response, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	for _, text := range expect {
+		cond, _ := parseCondition(text)
+		if err := cond.check(document); err != nil {
+			return response, err
+		}
+	}
+
+	for _, entry := range capture {
+		name, path, hasName := strings.Cut(entry, "=")
+		if !hasName {
+			path = name
+		}
+
+		value, found := lookupPath(document, strings.TrimSpace(path))
+		if !found {
+			return response, fmt.Errorf("%w: %s", ErrFieldNotFound, path)
+		}
+
+		response.Fields[strings.TrimSpace(name)] = formatValue(value)
+	}
+
+	return response, nil
+}
+
+// lookupPath returns the value at the path in the JSON document. Paths use dots to separate keys,
+// and either brackets or dots for array indexes, like `$.items[0].id` or `items.0.id`.
+func lookupPath(document any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = pathIndexPattern.ReplaceAllString(path, ".$1")
+
+	value := document
+
+	if path == "" {
+		return value, true
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			child, found := node[segment]
+			if !found {
+				return nil, false
+			}
+
+			value = child
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// formatValue returns the string form of a JSON value, used for comparisons and captured fields.
+// Strings are returned without quotes, while objects and arrays are returned as JSON.
+func formatValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		encoded, _ := json.Marshal(typed)
+
+		return string(encoded)
+	}
+}
+
+func formatFound(value any, found bool) string {
+	if !found {
+		return "nothing"
+	}
+
+	return formatValue(value)
+}
+
+// truthy reports whether the JSON value is not null, false, zero or empty.
+func truthy(value any) bool {
+	switch typed := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typed
+	case string:
+		return typed != ""
+	case json.Number:
+		number, err := typed.Float64()
+
+		return err != nil || number != 0
+	case []any:
+		return len(typed) > 0
+	case map[string]any:
+		return len(typed) > 0
+	default:
+		return true
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // ErrUnexpectedStatus indicates an unexpected HTTP status code from the Google Chat API.
@@ -72,6 +73,19 @@ func (service *Service) Send(message string, _ *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for sending the message, without sending it.
+// The key and token are redacted from the request URL.
+func (service *Service) DryRun(message string, _ *types.Params) ([]types.Request, error) {
+	config := service.Config
+
+	request, err := util.RedactedJSONRequest(getAPIURL(config).String(), JSON{Text: message}, config.Key, config.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
 // getAPIURL constructs the API URL for Google Chat notifications.
 func getAPIURL(config *Config) *url.URL {
 	query := url.Values{}
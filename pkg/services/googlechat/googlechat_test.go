@@ -199,6 +199,21 @@ var _ = ginkgo.Describe("Google Chat Service", func() {
 				err = service.Send("Valid Message", nil)
 				gomega.Expect(err).NotTo(gomega.HaveOccurred())
 			})
+			ginkgo.It("renders the request with the key and token redacted when doing a dry run", func() {
+				configURL := testutils.URLMust(
+					"googlechat://chat.googleapis.com/v1/spaces/FOO/messages?key=secret-key&token=secret-token",
+				)
+				gomega.Expect(service.Initialize(configURL, logger)).To(gomega.Succeed())
+
+				requests, err := service.DryRun("Message", nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).To(gomega.Equal(
+					"https://chat.googleapis.com/v1/spaces/FOO/messages?key=REDACTED&token=REDACTED",
+				))
+				gomega.Expect(requests[0].Body).To(gomega.MatchJSON(`{"text":"Message"}`))
+			})
 			ginkgo.It("returns formatted error if HTTP POST fails", func() {
 				configURL := testutils.URLMust(
 					"googlechat://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz",
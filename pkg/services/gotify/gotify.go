@@ -12,6 +12,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
@@ -91,8 +92,13 @@ func isTokenValid(token string) bool {
 	return true
 }
 
-// buildURL constructs the Gotify API URL with scheme, host, path, and token.
+// buildURL constructs the Gotify API URL for posting messages with scheme, host, path, and token.
 func buildURL(config *Config) (string, error) {
+	return buildEndpointURL(config, "/message")
+}
+
+// buildEndpointURL constructs the Gotify API URL for the endpoint with scheme, host, path, and token.
+func buildEndpointURL(config *Config, endpoint string) (string, error) {
 	token := config.Token
 	if !isTokenValid(token) {
 		return "", fmt.Errorf("%w: %q", ErrInvalidToken, token)
@@ -103,23 +109,66 @@ func buildURL(config *Config) (string, error) {
 		scheme = "http" // Use HTTP if TLS is disabled
 	}
 
-	return fmt.Sprintf("%s://%s%s/message?token=%s", scheme, config.Host, config.Path, token), nil
+	return fmt.Sprintf("%s://%s%s%s?token=%s", scheme, config.Host, config.Path, endpoint, token), nil
 }
 
 // Send delivers a notification message to Gotify.
 func (service *Service) Send(message string, params *types.Params) error {
+	postURL, request, err := service.newMessageRequest(service.Config, message, params)
+	if err != nil {
+		return err
+	}
+
+	response := &messageResponse{}
+
+	err = service.client.Post(postURL, request, response)
+	if err != nil {
+		errorRes := &responseError{}
+		if service.client.ErrorResponse(err, errorRes) {
+			return errorRes
+		}
+
+		return fmt.Errorf("failed to send notification to Gotify: %w", err)
+	}
+
+	return nil
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The application token is redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+
+	postURL, request, err := service.newMessageRequest(&config, message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := util.RedactedJSONRequest(postURL, request, config.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{rendered}, nil
+}
+
+// newMessageRequest updates the config with the params, returning the API URL and the request for the message.
+func (service *Service) newMessageRequest(
+	config *Config,
+	message string,
+	params *types.Params,
+) (string, *messageRequest, error) {
 	if params == nil {
 		params = &types.Params{}
 	}
 
-	config := service.Config
 	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
 		service.Logf("Failed to update params: %v", err)
 	}
 
 	postURL, err := buildURL(config)
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 
 	request := &messageRequest{
@@ -127,19 +176,30 @@ func (service *Service) Send(message string, params *types.Params) error {
 		Title:    config.Title,
 		Priority: config.Priority,
 	}
-	response := &messageResponse{}
 
-	err = service.client.Post(postURL, request, response)
+	return postURL, request, nil
+}
+
+// Check verifies the application token using the current application endpoint, without sending a message.
+func (service *Service) Check() (types.CheckResult, error) {
+	checkURL, err := buildEndpointURL(service.Config, "/current/application")
 	if err != nil {
+		return types.CheckResult{}, err
+	}
+
+	response := &applicationResponse{}
+	if err := service.client.Get(checkURL, response); err != nil {
+		result := types.CheckResult{Reachable: jsonclient.StatusCode(err) != 0}
+
 		errorRes := &responseError{}
 		if service.client.ErrorResponse(err, errorRes) {
-			return errorRes
+			return result, errorRes
 		}
 
-		return fmt.Errorf("failed to send notification to Gotify: %w", err)
+		return result, fmt.Errorf("failed to check Gotify token: %w", err)
 	}
 
-	return nil
+	return types.CheckResult{Reachable: true, Authenticated: true, Identity: response.Name}, nil
 }
 
 // GetHTTPClient returns the HTTP client for testing purposes.
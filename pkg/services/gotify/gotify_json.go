@@ -16,6 +16,12 @@ type messageResponse struct {
 	Date  string `json:"date"`
 }
 
+// applicationResponse is the application that a token belongs to.
+type applicationResponse struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
 type responseError struct {
 	Name        string `json:"error"`
 	Code        uint64 `json:"errorCode"`
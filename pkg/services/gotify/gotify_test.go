@@ -242,5 +242,55 @@ var _ = ginkgo.Describe("the Gotify service", func() {
 					To(gomega.ContainSubstring("Failed to update params"))
 			})
 		})
+		ginkgo.When("checking the token", func() {
+			checkURL := "https://my.gotify.tld/current/application?token=Aaa.bbb.ccc.ddd"
+			ginkgo.It("reports the application of the token", func() {
+				httpmock.RegisterResponder(
+					"GET",
+					checkURL,
+					testutils.JSONRespondMust(200, map[string]any{"id": float64(1), "name": "Backups"}),
+				)
+				result, err := service.Check()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(result).To(gomega.Equal(types.CheckResult{
+					Reachable:     true,
+					Authenticated: true,
+					Identity:      "Backups",
+				}))
+			})
+			ginkgo.It("reports a rejected token as reachable but not authenticated", func() {
+				httpmock.RegisterResponder(
+					"GET",
+					checkURL,
+					testutils.JSONRespondMust(401, map[string]any{
+						"error":            "Unauthorized",
+						"errorCode":        float64(401),
+						"errorDescription": "you need to provide a valid access token",
+					}),
+				)
+				result, err := service.Check()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(result).To(gomega.Equal(types.CheckResult{Reachable: true}))
+			})
+			ginkgo.It("reports a network error as unreachable", func() {
+				httpmock.RegisterResponder("GET", checkURL, httpmock.NewErrorResponder(errors.New("network failure")))
+				result, err := service.Check()
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(result.Reachable).To(gomega.BeFalse())
+			})
+		})
+		ginkgo.When("doing a dry run", func() {
+			ginkgo.It("renders the message request with the token redacted", func() {
+				params := types.Params{"title": "Backup"}
+				requests, err := service.DryRun("Message", &params)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).To(gomega.Equal("https://my.gotify.tld/message?token=REDACTED"))
+				gomega.Expect(requests[0].Body).
+					To(gomega.Equal(`{"message":"Message","title":"Backup","priority":0}`))
+				gomega.Expect(service.Config.Title).NotTo(gomega.Equal("Backup"))
+			})
+		})
 	})
 })
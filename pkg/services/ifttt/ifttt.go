@@ -3,6 +3,7 @@ package ifttt
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // apiURLFormat defines the IFTTT webhook URL template.
@@ -73,6 +75,35 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for triggering each event, without sending them.
+// The webhook ID is redacted from the request URLs.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	payload, err := createJSONToSend(&config, message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]types.Request, 0, len(config.Events))
+
+	for _, event := range config.Events {
+		apiURL := fmt.Sprintf(apiURLFormat, event, config.WebHookID)
+
+		request, err := util.RedactedJSONRequest(apiURL, json.RawMessage(payload), config.WebHookID)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
 // createAPIURLForEvent builds an IFTTT webhook URL for a specific event.
 func (service *Service) createAPIURLForEvent(event string) string {
 	return fmt.Sprintf(apiURLFormat, event, service.Config.WebHookID)
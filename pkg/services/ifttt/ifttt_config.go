@@ -93,14 +93,12 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		return ErrTitleMessageConflict
 	}
 
-	if url.String() != "ifttt://dummy@dummy.com" {
-		if len(config.Events) < MinLength {
-			return ErrMissingEvents
-		}
+	if len(config.Events) < MinLength {
+		return ErrMissingEvents
+	}
 
-		if len(config.WebHookID) < MinLength {
-			return ErrMissingWebhookID
-		}
+	if len(config.WebHookID) < MinLength {
+		return ErrMissingWebhookID
 	}
 
 	return nil
@@ -182,6 +182,18 @@ var _ = ginkgo.Describe("the IFTTT service", func() {
 			err = service.Send("hello", nil)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+		ginkgo.It("renders a request for each event with the webhook ID redacted when doing a dry run", func() {
+			configURL := testutils.URLMust("ifttt://webhookid/?events=foo,bar")
+			gomega.Expect(service.Initialize(configURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("hello", nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(2))
+			gomega.Expect(requests[0].URL).To(gomega.Equal("https://maker.ifttt.com/trigger/foo/with/key/REDACTED"))
+			gomega.Expect(requests[1].URL).To(gomega.Equal("https://maker.ifttt.com/trigger/bar/with/key/REDACTED"))
+			gomega.Expect(requests[1].Body).To(gomega.ContainSubstring(`"value2":"hello"`))
+		})
 		ginkgo.It("returns an error if params update fails", func() { // Line 55
 			configURL := testutils.URLMust("ifttt://dummy/?events=event1")
 			err := service.Initialize(configURL, logger)
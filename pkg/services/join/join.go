@@ -11,6 +11,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 const (
@@ -31,6 +32,40 @@ type Service struct {
 
 // Send delivers a notification message to Join devices.
 func (service *Service) Send(message string, params *types.Params) error {
+	req, err := service.createRequest(message, params)
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending HTTP request to Join: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		devices := strings.Join(service.Config.Devices, ",")
+
+		return fmt.Errorf("%w: %q, response status %q", ErrSendFailed, devices, res.Status)
+	}
+
+	return nil
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The API key is redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	req, err := service.createRequest(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{util.RedactedRequest(req, nil, service.Config.APIKey)}, nil
+}
+
+// createRequest creates the request for pushing the message to the configured devices.
+// The title and icon params take precedence over the ones in the config.
+func (service *Service) createRequest(message string, params *types.Params) (*http.Request, error) {
 	config := service.Config
 
 	if params == nil {
@@ -47,21 +82,13 @@ func (service *Service) Send(message string, params *types.Params) error {
 		icon = config.Icon
 	}
 
-	devices := strings.Join(config.Devices, ",")
-
-	return service.sendToDevices(devices, message, title, icon)
-}
-
-func (service *Service) sendToDevices(devices, message, title, icon string) error {
-	config := service.Config
-
 	apiURL, err := url.Parse(hookURL)
 	if err != nil {
-		return fmt.Errorf("parsing Join API URL: %w", err)
+		return nil, fmt.Errorf("parsing Join API URL: %w", err)
 	}
 
 	data := url.Values{}
-	data.Set("deviceIds", devices)
+	data.Set("deviceIds", strings.Join(config.Devices, ","))
 	data.Set("apikey", config.APIKey)
 	data.Set("text", message)
 
@@ -82,22 +109,12 @@ func (service *Service) sendToDevices(devices, message, title, icon string) erro
 		nil,
 	)
 	if err != nil {
-		return fmt.Errorf("creating HTTP request: %w", err)
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", contentType)
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("sending HTTP request to Join: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %q, response status %q", ErrSendFailed, devices, res.Status)
-	}
-
-	return nil
+	return req, nil
 }
 
 // Initialize configures the service with a URL and logger.
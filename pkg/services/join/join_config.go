@@ -65,14 +65,12 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		}
 	}
 
-	if url.String() != "join://dummy@dummy.com" {
-		if len(config.Devices) < 1 {
-			return ErrDevicesMissing
-		}
+	if len(config.Devices) < 1 {
+		return ErrDevicesMissing
+	}
 
-		if len(config.APIKey) < 1 {
-			return ErrAPIKeyMissing
-		}
+	if len(config.APIKey) < 1 {
+		return ErrAPIKeyMissing
 	}
 
 	return nil
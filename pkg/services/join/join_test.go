@@ -12,6 +12,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/join"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
 func TestJoin(t *testing.T) {
@@ -155,6 +156,23 @@ var _ = ginkgo.Describe("the join config", func() {
 			err = service.Send("Message", nil)
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		})
+		ginkgo.It("should render the request with the API key redacted when doing a dry run", func() {
+			config := join.Config{
+				APIKey:  "secret-key",
+				Devices: []string{"dev1", "dev2"},
+			}
+			service := join.Service{}
+			gomega.Expect(service.Initialize(config.GetURL(), nil)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("Message", &types.Params{"title": "Title"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal(
+				"https://joinjoaomgcd.appspot.com/_ah/api/messaging/v1/sendPush" +
+					"?apikey=REDACTED&deviceIds=dev1%2Cdev2&text=Message&title=Title",
+			))
+		})
 	})
 })
 
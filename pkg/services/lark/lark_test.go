@@ -210,6 +210,20 @@ var _ = ginkgo.Describe("Lark Test", func() {
 				gomega.Expect(err).
 					To(gomega.MatchError(gomega.ContainSubstring("unexpected status 400")))
 			})
+
+			ginkgo.It("should render the request with the token and secret redacted when doing a dry run", func() {
+				err := service.Initialize(testutils.URLMust(fullURL), logger)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+				requests, err := service.DryRun("message", &types.Params{"title": "title"})
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).
+					To(gomega.Equal("https://open.larksuite.com/open-apis/bot/v2/hook/REDACTED"))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"sign":"REDACTED"`))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"title":"title"`))
+			})
 		})
 	})
 })
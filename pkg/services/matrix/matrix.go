@@ -3,11 +3,14 @@ package matrix
 import (
 	"errors"
 	"fmt"
+	"html"
 	"net/url"
+	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/markdown"
 )
 
 // Scheme identifies this service in configuration URLs.
@@ -34,15 +37,25 @@ func (s *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 		return err
 	}
 
-	if configURL.String() != "matrix://dummy@dummy.com" {
-		s.client = newClient(s.Config.Host, s.Config.DisableTLS, logger)
-		if s.Config.User != "" {
-			return s.client.login(s.Config.User, s.Config.Password)
+	s.client = newClient(s.Config.Host, s.Config.DisableTLS, logger)
+
+	if s.Config.Encryption {
+		storePath, err := s.Config.cryptoStorePath()
+		if err != nil {
+			return err
+		}
+
+		if err := s.client.useCryptoStore(storePath); err != nil {
+			return err
 		}
+	}
 
-		s.client.useToken(s.Config.Password)
+	if s.Config.User != "" {
+		return s.client.login(s.Config.User, s.Config.Password)
 	}
 
+	s.client.useToken(s.Config.Password)
+
 	return nil
 }
 
@@ -51,6 +64,25 @@ func (s *Service) GetID() string {
 	return Scheme
 }
 
+// Check verifies the access token using the whoami endpoint, without sending a message.
+// When the URL contains a user, the credentials have already been checked by logging in on initialization.
+func (s *Service) Check() (types.CheckResult, error) {
+	if s.client == nil {
+		return types.CheckResult{}, ErrClientNotInitialized
+	}
+
+	userID, err := s.client.whoAmI()
+	if err != nil {
+		var apiErr *apiResError
+
+		reachable := errors.As(err, &apiErr) || errors.Is(err, ErrUnexpectedStatus)
+
+		return types.CheckResult{Reachable: reachable}, err
+	}
+
+	return types.CheckResult{Reachable: true, Authenticated: true, Identity: userID}, nil
+}
+
 // Send delivers a notification message to Matrix rooms.
 func (s *Service) Send(message string, params *types.Params) error {
 	config := *s.Config
@@ -62,7 +94,7 @@ func (s *Service) Send(message string, params *types.Params) error {
 		return ErrClientNotInitialized
 	}
 
-	errors := s.client.sendMessage(message, s.Config.Rooms)
+	errors := s.client.sendMessage(createSendRequest(message, &config), normalizeRooms(config.Rooms))
 	if len(errors) > 0 {
 		for _, err := range errors {
 			s.Logf("error sending message: %w", err)
@@ -77,3 +109,97 @@ func (s *Service) Send(message string, params *types.Params) error {
 
 	return nil
 }
+
+// createSendRequest creates the content of the message event from the message and config.
+// Mentioned users are prefixed to the message, and the formatted body is only set when
+// markdown is enabled or users are mentioned.
+func createSendRequest(message string, config *Config) apiReqSend {
+	request := apiReqSend{
+		MsgType: msgTypeText,
+		Body:    message,
+	}
+
+	if config.Notice {
+		request.MsgType = msgTypeNotice
+	}
+
+	formatted := ""
+	if config.Markdown {
+		formatted = markdown.ToHTML(message)
+	}
+
+	if userIDs, room := parseMentions(config.Mentions); len(userIDs) > 0 || room {
+		request.Mentions = &mentions{UserIDs: userIDs, Room: room}
+
+		if !config.Markdown {
+			formatted = strings.ReplaceAll(html.EscapeString(message), "\n", "<br>")
+		}
+
+		prefix, htmlPrefix := formatMentions(userIDs, room)
+		request.Body = prefix + " " + request.Body
+		formatted = htmlPrefix + " " + formatted
+	}
+
+	if formatted != "" {
+		request.Format = formatHTML
+		request.FormattedBody = formatted
+	}
+
+	switch {
+	case config.Thread != "":
+		// Clients without thread support show the message as a reply, to the thread root unless specified
+		replyTo := config.ReplyTo
+		if replyTo == "" {
+			replyTo = config.Thread
+		}
+
+		request.RelatesTo = &relatesTo{
+			RelType:       relTypeThread,
+			EventID:       config.Thread,
+			IsFallingBack: config.ReplyTo == "",
+			InReplyTo:     &inReplyTo{EventID: replyTo},
+		}
+	case config.ReplyTo != "":
+		request.RelatesTo = &relatesTo{InReplyTo: &inReplyTo{EventID: config.ReplyTo}}
+	}
+
+	return request
+}
+
+// parseMentions splits the mentions into user IDs and whether the whole room is mentioned.
+func parseMentions(values []string) ([]string, bool) {
+	var userIDs []string
+
+	room := false
+
+	for _, value := range values {
+		switch value = strings.TrimSpace(value); value {
+		case "":
+		case mentionRoom:
+			room = true
+		default:
+			userIDs = append(userIDs, value)
+		}
+	}
+
+	return userIDs, room
+}
+
+// formatMentions returns the mentions as plain text and as HTML, where users are linked as pills.
+func formatMentions(userIDs []string, room bool) (string, string) {
+	plain := make([]string, 0, len(userIDs)+1)
+	links := make([]string, 0, len(userIDs)+1)
+
+	if room {
+		plain = append(plain, mentionRoom)
+		links = append(links, mentionRoom)
+	}
+
+	for _, userID := range userIDs {
+		plain = append(plain, userID)
+		links = append(links, fmt.Sprintf(`<a href="https://matrix.to/#/%s">%s</a>`,
+			url.PathEscape(userID), html.EscapeString(userID)))
+	}
+
+	return strings.Join(plain, " "), strings.Join(links, " ")
+}
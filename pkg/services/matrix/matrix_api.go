@@ -1,5 +1,7 @@
 package matrix
 
+import "encoding/json"
+
 type (
 	messageType    string
 	flowType       string
@@ -9,16 +11,40 @@ type (
 const (
 	apiLogin       = "/_matrix/client/r0/login"
 	apiRoomJoin    = "/_matrix/client/r0/join/%s"
-	apiSendMessage = "/_matrix/client/r0/rooms/%s/send/m.room.message"
+	apiSendMessage = "/_matrix/client/r0/rooms/%s/send/m.room.message/%s"
 	apiJoinedRooms = "/_matrix/client/r0/joined_rooms"
 
+	apiWhoAmI         = "/_matrix/client/r0/account/whoami"
+	apiSendEncrypted  = "/_matrix/client/r0/rooms/%s/send/m.room.encrypted/%s"
+	apiRoomEncryption = "/_matrix/client/r0/rooms/%s/state/m.room.encryption"
+	apiJoinedMembers  = "/_matrix/client/r0/rooms/%s/joined_members"
+	apiKeysUpload     = "/_matrix/client/r0/keys/upload"
+	apiKeysQuery      = "/_matrix/client/r0/keys/query"
+	apiKeysClaim      = "/_matrix/client/r0/keys/claim"
+	apiSendToDevice   = "/_matrix/client/r0/sendToDevice/m.room.encrypted/%s"
+
 	contentType = "application/json"
 
 	accessTokenKey = "access_token"
 
 	msgTypeText       messageType    = "m.text"
+	msgTypeNotice     messageType    = "m.notice"
 	flowLoginPassword flowType       = "m.login.password"
 	idTypeUser        identifierType = "m.id.user"
+
+	relTypeThread = "m.thread"
+	mentionRoom   = "@room"
+	formatHTML    = "org.matrix.custom.html"
+
+	algorithmOlm       = "m.olm.v1.curve25519-aes-sha2"
+	algorithmMegolm    = "m.megolm.v1.aes-sha2"
+	keyAlgorithmOTK    = "signed_curve25519"
+	eventTypeMessage   = "m.room.message"
+	eventTypeRoomKey   = "m.room_key"
+	errCodeNotFound    = "M_NOT_FOUND"
+	olmMessageTypePre  = 0
+	defaultRotationMS  = 7 * 24 * 60 * 60 * 1000
+	defaultRotationMsg = 100
 )
 
 type apiResLoginFlows struct {
@@ -30,6 +56,7 @@ type apiReqLogin struct {
 	Identifier *identifier `json:"identifier"`
 	Password   string      `json:"password,omitempty"`
 	Token      string      `json:"token,omitempty"`
+	DeviceID   string      `json:"device_id,omitempty"`
 }
 
 type apiResLogin struct {
@@ -40,8 +67,127 @@ type apiResLogin struct {
 }
 
 type apiReqSend struct {
-	MsgType messageType `json:"msgtype"`
-	Body    string      `json:"body"`
+	MsgType       messageType `json:"msgtype"`
+	Body          string      `json:"body"`
+	Format        string      `json:"format,omitempty"`
+	FormattedBody string      `json:"formatted_body,omitempty"`
+	Mentions      *mentions   `json:"m.mentions,omitempty"`
+	RelatesTo     *relatesTo  `json:"m.relates_to,omitempty"`
+}
+
+type mentions struct {
+	UserIDs []string `json:"user_ids,omitempty"`
+	Room    bool     `json:"room,omitempty"`
+}
+
+type relatesTo struct {
+	RelType       string     `json:"rel_type,omitempty"`
+	EventID       string     `json:"event_id,omitempty"`
+	IsFallingBack bool       `json:"is_falling_back,omitempty"`
+	InReplyTo     *inReplyTo `json:"m.in_reply_to,omitempty"`
+}
+
+type inReplyTo struct {
+	EventID string `json:"event_id"`
+}
+
+type apiReqEncrypted struct {
+	Algorithm  string     `json:"algorithm"`
+	SenderKey  string     `json:"sender_key"`
+	Ciphertext string     `json:"ciphertext"`
+	SessionID  string     `json:"session_id"`
+	DeviceID   string     `json:"device_id"`
+	RelatesTo  *relatesTo `json:"m.relates_to,omitempty"`
+}
+
+type apiResWhoAmI struct {
+	UserID   string `json:"user_id"`
+	DeviceID string `json:"device_id"`
+}
+
+type apiResRoomEncryption struct {
+	Algorithm          string `json:"algorithm"`
+	RotationPeriodMS   int64  `json:"rotation_period_ms"`
+	RotationPeriodMsgs int    `json:"rotation_period_msgs"`
+}
+
+type apiResJoinedMembers struct {
+	Joined map[string]struct{} `json:"joined"`
+}
+
+type deviceKeys struct {
+	UserID     string                       `json:"user_id"`
+	DeviceID   string                       `json:"device_id"`
+	Algorithms []string                     `json:"algorithms"`
+	Keys       map[string]string            `json:"keys"`
+	Signatures map[string]map[string]string `json:"signatures,omitempty"`
+}
+
+type apiReqKeysUpload struct {
+	DeviceKeys *deviceKeys `json:"device_keys"`
+}
+
+type apiResKeysUpload struct {
+	OneTimeKeyCounts map[string]int `json:"one_time_key_counts"`
+}
+
+type apiReqKeysQuery struct {
+	DeviceKeys map[string][]string `json:"device_keys"`
+}
+
+type apiResKeysQuery struct {
+	DeviceKeys map[string]map[string]json.RawMessage `json:"device_keys"`
+}
+
+type apiReqKeysClaim struct {
+	OneTimeKeys map[string]map[string]string `json:"one_time_keys"`
+}
+
+type apiResKeysClaim struct {
+	OneTimeKeys map[string]map[string]map[string]json.RawMessage `json:"one_time_keys"`
+}
+
+type oneTimeKey struct {
+	Key        string                       `json:"key"`
+	Signatures map[string]map[string]string `json:"signatures,omitempty"`
+}
+
+type apiReqSendToDevice struct {
+	Messages map[string]map[string]olmEncrypted `json:"messages"`
+}
+
+type olmEncrypted struct {
+	Algorithm  string                   `json:"algorithm"`
+	SenderKey  string                   `json:"sender_key"`
+	Ciphertext map[string]olmCiphertext `json:"ciphertext"`
+}
+
+type olmCiphertext struct {
+	Type int    `json:"type"`
+	Body string `json:"body"`
+}
+
+type olmPayload struct {
+	Type          string            `json:"type"`
+	Content       roomKey           `json:"content"`
+	Sender        string            `json:"sender"`
+	SenderDevice  string            `json:"sender_device"`
+	Keys          map[string]string `json:"keys"`
+	Recipient     string            `json:"recipient"`
+	RecipientKeys map[string]string `json:"recipient_keys"`
+}
+
+type roomKey struct {
+	Algorithm  string `json:"algorithm"`
+	RoomID     string `json:"room_id"`
+	SessionID  string `json:"session_id"`
+	SessionKey string `json:"session_key"`
+}
+
+type megolmPayload struct {
+	Type    string     `json:"type"`
+	Content apiReqSend `json:"content"`
+	RoomID  string     `json:"room_id"`
 }
 
 type apiResRoom struct {
@@ -59,6 +205,7 @@ type apiResEvent struct {
 type apiResError struct {
 	Message string `json:"error"`
 	Code    string `json:"errcode"`
+	status  int
 }
 
 func (e *apiResError) Error() string {
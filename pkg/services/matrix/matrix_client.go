@@ -9,7 +9,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
@@ -22,7 +24,11 @@ const (
 	tokenHintLength        = 3
 	minSliceLength         = 1
 	httpClientErrorStatus  = 400
+	httpServerErrorStatus  = 500
 	defaultHTTPTimeout     = 10 * time.Second // defaultHTTPTimeout is the timeout for HTTP requests.
+	defaultRetryDelay      = time.Second      // defaultRetryDelay is the delay before retrying a failed send.
+	maxSendAttempts        = 3                // maxSendAttempts is the number of times a message send is attempted.
+	errCodeForbidden       = "M_FORBIDDEN"
 )
 
 // ErrUnsupportedLoginFlows indicates that none of the server login flows are supported.
@@ -37,6 +43,16 @@ type client struct {
 	accessToken string
 	logger      types.StdLogger
 	httpClient  *http.Client
+	retryDelay  time.Duration
+	roomIDs     map[string]string // roomIDs caches the IDs of joined rooms, keyed by the configured room.
+	txnCounter  atomic.Uint64
+	userID      string
+	deviceID    string
+
+	// crypto is the store of the encryption keys, or nil when end-to-end encryption is disabled.
+	crypto *cryptoStore
+	// roomEncryption caches the encryption settings of rooms, which are nil for unencrypted rooms.
+	roomEncryption map[string]*apiResRoomEncryption
 }
 
 // newClient creates a new Matrix client with the specified host and TLS settings.
@@ -50,6 +66,8 @@ func newClient(host string, disableTLS bool, logger types.StdLogger) *client {
 		httpClient: &http.Client{
 			Timeout: defaultHTTPTimeout,
 		},
+		retryDelay: defaultRetryDelay,
+		roomIDs:    make(map[string]string),
 	}
 
 	if client.logger == nil {
@@ -97,16 +115,25 @@ func (c *client) login(user string, password string) error {
 
 // loginPassword performs a password-based login to the Matrix server.
 func (c *client) loginPassword(user string, password string) error {
-	response := apiResLogin{}
-	if err := c.apiPost(apiLogin, apiReqLogin{
+	request := apiReqLogin{
 		Type:       flowLoginPassword,
 		Password:   password,
 		Identifier: newUserIdentifier(user),
-	}, &response); err != nil {
+	}
+
+	// Logging in to the device of the stored keys prevents a new device from being created for every login
+	if c.crypto != nil {
+		request.DeviceID = c.crypto.DeviceID
+	}
+
+	response := apiResLogin{}
+	if err := c.apiPost(apiLogin, request, &response); err != nil {
 		return fmt.Errorf("failed to log in: %w", err)
 	}
 
 	c.accessToken = response.AccessToken
+	c.userID = response.UserID
+	c.deviceID = response.DeviceID
 
 	tokenHint := ""
 	if len(response.AccessToken) > tokenHintLength {
@@ -121,33 +148,34 @@ func (c *client) loginPassword(user string, password string) error {
 }
 
 // sendMessage sends a message to the specified rooms or all joined rooms if none are specified.
-func (c *client) sendMessage(message string, rooms []string) []error {
+func (c *client) sendMessage(request apiReqSend, rooms []string) []error {
 	if len(rooms) >= minSliceLength {
-		return c.sendToExplicitRooms(rooms, message)
+		return c.sendToExplicitRooms(rooms, request)
 	}
 
-	return c.sendToJoinedRooms(message)
+	return c.sendToJoinedRooms(request)
 }
 
 // sendToExplicitRooms sends a message to explicitly specified rooms and collects any errors.
-func (c *client) sendToExplicitRooms(rooms []string, message string) []error {
+func (c *client) sendToExplicitRooms(rooms []string, request apiReqSend) []error {
 	var errors []error
 
 	for _, room := range rooms {
 		c.logf("Sending message to '%v'...\n", room)
 
-		roomID, err := c.joinRoom(room)
+		roomID, err := c.resolveRoom(room)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("error joining room %v: %w", roomID, err))
 
 			continue
 		}
 
-		if room != roomID {
-			c.logf("Resolved room alias '%v' to ID '%v'", room, roomID)
-		}
+		if err := c.sendMessageToRoom(request, roomID); err != nil {
+			// The user might have been removed from the room, so it needs to be joined again on the next send
+			if isForbidden(err) {
+				delete(c.roomIDs, room)
+			}
 
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
 			errors = append(
 				errors,
 				fmt.Errorf("failed to send message to room '%v': %w", roomID, err),
@@ -159,7 +187,7 @@ func (c *client) sendToExplicitRooms(rooms []string, message string) []error {
 }
 
 // sendToJoinedRooms sends a message to all joined rooms and collects any errors.
-func (c *client) sendToJoinedRooms(message string) []error {
+func (c *client) sendToJoinedRooms(request apiReqSend) []error {
 	var errors []error
 
 	joinedRooms, err := c.getJoinedRooms()
@@ -170,7 +198,7 @@ func (c *client) sendToJoinedRooms(message string) []error {
 	for _, roomID := range joinedRooms {
 		c.logf("Sending message to '%v'...\n", roomID)
 
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
+		if err := c.sendMessageToRoom(request, roomID); err != nil {
 			errors = append(
 				errors,
 				fmt.Errorf("failed to send message to room '%v': %w", roomID, err),
@@ -181,6 +209,26 @@ func (c *client) sendToJoinedRooms(message string) []error {
 	return errors
 }
 
+// resolveRoom returns the ID of the room, joining it unless it has been joined before.
+func (c *client) resolveRoom(room string) (string, error) {
+	if roomID, found := c.roomIDs[room]; found {
+		return roomID, nil
+	}
+
+	roomID, err := c.joinRoom(room)
+	if err != nil {
+		return roomID, err
+	}
+
+	if room != roomID {
+		c.logf("Resolved room alias '%v' to ID '%v'", room, roomID)
+	}
+
+	c.roomIDs[room] = roomID
+
+	return roomID, nil
+}
+
 // joinRoom joins a specified room and returns its ID.
 func (c *client) joinRoom(room string) (string, error) {
 	resRoom := apiResRoom{}
@@ -192,93 +240,134 @@ func (c *client) joinRoom(room string) (string, error) {
 }
 
 // sendMessageToRoom sends a message to a specific room.
-func (c *client) sendMessageToRoom(message string, roomID string) error {
-	resEvent := apiResEvent{}
+// Failed attempts are retried using the same transaction ID, which lets the server discard
+// any duplicates of a message that was received even though the request failed.
+func (c *client) sendMessageToRoom(request apiReqSend, roomID string) error {
+	txnID := c.newTxnID()
+	path := fmt.Sprintf(apiSendMessage, roomID, txnID)
 
-	return c.apiPost(fmt.Sprintf(apiSendMessage, roomID), apiReqSend{
-		MsgType: msgTypeText,
-		Body:    message,
-	}, &resEvent)
-}
+	var content any = request
 
-// apiGet performs a GET request to the Matrix API.
-func (c *client) apiGet(path string, response any) error {
-	c.apiURL.Path = path
+	if c.crypto != nil {
+		settings, err := c.getRoomEncryption(roomID)
+		if err != nil {
+			return fmt.Errorf("failed to get room encryption: %w", err)
+		}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
-	defer cancel()
+		if settings != nil {
+			encrypted, err := c.encryptMessage(request, roomID, settings)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt message: %w", err)
+			}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL.String(), nil)
-	if err != nil {
-		return fmt.Errorf("creating GET request: %w", err)
+			path = fmt.Sprintf(apiSendEncrypted, roomID, txnID)
+			content = encrypted
+		}
 	}
 
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("executing GET request: %w", err)
-	}
+	var err error
 
-	defer res.Body.Close()
+	for attempt := 1; attempt <= maxSendAttempts; attempt++ {
+		resEvent := apiResEvent{}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return fmt.Errorf("reading GET response body: %w", err)
-	}
+		err = c.apiPut(path, content, &resEvent)
+		if err == nil {
+			c.logf("Sent event '%v' to room '%v'\n", resEvent.EventID, roomID)
 
-	if res.StatusCode >= httpClientErrorStatus {
-		resError := &apiResError{}
-		if err = json.Unmarshal(body, resError); err == nil {
-			return resError
+			return nil
 		}
 
-		return fmt.Errorf("%w: %v (unmarshal error: %w)", ErrUnexpectedStatus, res.Status, err)
+		if attempt == maxSendAttempts || !isRetryable(err) {
+			break
+		}
+
+		c.logf("Attempt %v of sending message failed, retrying: %v\n", attempt, err)
+		time.Sleep(c.retryDelay)
 	}
 
-	if err = json.Unmarshal(body, response); err != nil {
-		return fmt.Errorf("unmarshaling GET response: %w", err)
+	return err
+}
+
+// newTxnID returns a transaction ID that is unique for the lifetime of the access token.
+func (c *client) newTxnID() string {
+	return "shoutrrr" + strconv.FormatInt(time.Now().UnixNano(), 36) + "." +
+		strconv.FormatUint(c.txnCounter.Add(1), 36)
+}
+
+// isForbidden reports whether the request failed because the user is not allowed to perform it.
+func isForbidden(err error) bool {
+	resError := &apiResError{}
+
+	return errors.As(err, &resError) && resError.Code == errCodeForbidden
+}
+
+// isRetryable reports whether a request that failed with err might succeed when sent again.
+func isRetryable(err error) bool {
+	if resError := (&apiResError{}); errors.As(err, &resError) {
+		return resError.status >= httpServerErrorStatus || resError.status == http.StatusTooManyRequests
 	}
 
-	return nil
+	urlError := &url.Error{}
+
+	return errors.As(err, &urlError) || errors.Is(err, ErrUnexpectedStatus)
+}
+
+// apiGet performs a GET request to the Matrix API.
+func (c *client) apiGet(path string, response any) error {
+	return c.apiRequest(http.MethodGet, path, nil, response)
 }
 
 // apiPost performs a POST request to the Matrix API.
 func (c *client) apiPost(path string, request any, response any) error {
+	return c.apiRequest(http.MethodPost, path, request, response)
+}
+
+// apiPut performs a PUT request to the Matrix API.
+func (c *client) apiPut(path string, request any, response any) error {
+	return c.apiRequest(http.MethodPut, path, request, response)
+}
+
+// apiRequest performs a request to the Matrix API, sending the request as JSON unless the method is GET.
+func (c *client) apiRequest(method string, path string, request any, response any) error {
 	c.apiURL.Path = path
 
-	body, err := json.Marshal(request)
-	if err != nil {
-		return fmt.Errorf("marshaling POST request: %w", err)
+	var reqBody io.Reader
+
+	if method != http.MethodGet {
+		body, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("marshaling %s request: %w", method, err)
+		}
+
+		reqBody = bytes.NewReader(body)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		c.apiURL.String(),
-		bytes.NewReader(body),
-	)
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL.String(), reqBody)
 	if err != nil {
-		return fmt.Errorf("creating POST request: %w", err)
+		return fmt.Errorf("creating %s request: %w", method, err)
 	}
 
-	req.Header.Set("Content-Type", contentType)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
 
 	res, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing POST request: %w", err)
+		return fmt.Errorf("executing %s request: %w", method, err)
 	}
 
 	defer res.Body.Close()
 
-	body, err = io.ReadAll(res.Body)
+	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return fmt.Errorf("reading POST response body: %w", err)
+		return fmt.Errorf("reading %s response body: %w", method, err)
 	}
 
 	if res.StatusCode >= httpClientErrorStatus {
-		resError := &apiResError{}
+		resError := &apiResError{status: res.StatusCode}
 		if err = json.Unmarshal(body, resError); err == nil {
 			return resError
 		}
@@ -287,7 +376,7 @@ func (c *client) apiPost(path string, request any, response any) error {
 	}
 
 	if err = json.Unmarshal(body, response); err != nil {
-		return fmt.Errorf("unmarshaling POST response: %w", err)
+		return fmt.Errorf("unmarshaling %s response: %w", method, err)
 	}
 
 	return nil
@@ -305,6 +394,16 @@ func (c *client) logf(format string, v ...any) {
 	c.logger.Printf(format, v...)
 }
 
+// whoAmI returns the ID of the user that the access token belongs to.
+func (c *client) whoAmI() (string, error) {
+	response := apiResWhoAmI{}
+	if err := c.apiGet(apiWhoAmI, &response); err != nil {
+		return "", fmt.Errorf("getting user ID: %w", err)
+	}
+
+	return response.UserID, nil
+}
+
 // getJoinedRooms retrieves the list of rooms the client has joined.
 func (c *client) getJoinedRooms() ([]string, error) {
 	response := apiResJoinedRooms{}
@@ -1,24 +1,39 @@
 package matrix
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
+// tokenHashLength is the number of bytes of the token hash used in the default crypto store name.
+const tokenHashLength = 4
+
 // Config is the configuration for the matrix service.
 type Config struct {
 	standard.EnumlessConfig
 
-	User       string   `desc:"Username or empty when using access token" optional:"" url:"user"`
-	Password   string   `desc:"Password or access token"                              url:"password"`
-	DisableTLS bool     `                                                                            default:"No" key:"disableTLS"`
-	Host       string   `                                                             url:"host"`
-	Rooms      []string `desc:"Room aliases, or with ! prefix, room IDs"  optional:""                             key:"rooms,room"`
-	Title      string   `                                                                            default:""   key:"title"`
+	User       string   `desc:"Username or empty when using access token"           optional:"" url:"user"`
+	Password   string   `desc:"Password or access token"                                        url:"password"`
+	DisableTLS bool     `                                                                                      default:"No" key:"disableTLS"`
+	Host       string   `                                                                       url:"host"`
+	Rooms      []string `desc:"Room aliases, or with ! prefix, room IDs"            optional:""                             key:"rooms,room"`
+	Title      string   `                                                                                      default:""   key:"title"`
+	Markdown   bool     `desc:"Render the message as formatted HTML from markdown"                             default:"No" key:"markdown"`
+	Notice     bool     `desc:"Send the message as a notice instead of a text"                                 default:"No" key:"notice"`
+	Mentions   []string `desc:"User IDs to mention, or @room to mention the room"   optional:""                             key:"mentions"`
+	Thread     string   `desc:"Event ID of the thread root to reply in"             optional:""                             key:"thread"`
+	ReplyTo    string   `desc:"Event ID of the message to reply to"                 optional:""                             key:"replyto"`
+	Encryption bool     `desc:"Encrypt messages sent to end-to-end encrypted rooms"                            default:"No" key:"encryption"`
+	Store      string   `desc:"Path of the file where encryption keys are stored"   optional:""                             key:"cryptostore"`
 }
 
 // GetURL returns a URL representation of it's current field values.
@@ -57,12 +72,50 @@ func (c *Config) setURL(resolver types.ConfigQueryResolver, configURL *url.URL)
 		}
 	}
 
-	for r, room := range c.Rooms {
+	c.Rooms = normalizeRooms(c.Rooms)
+
+	return nil
+}
+
+// cryptoStorePath returns the path of the crypto store. Unless specified, the store is kept in the user
+// config directory, in a file named after the user, or a hash of the access token when logging in with a token.
+func (c *Config) cryptoStorePath() (string, error) {
+	if c.Store != "" {
+		return c.Store, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("getting config directory: %w", err)
+	}
+
+	name := c.User
+	if name == "" {
+		hash := sha256.Sum256([]byte(c.Password))
+		name = "token-" + hex.EncodeToString(hash[:tokenHashLength])
+	}
+
+	host := strings.ReplaceAll(c.Host, ":", "_")
+
+	return filepath.Join(configDir, "shoutrrr", "matrix", host, name+".json"), nil
+}
+
+// normalizeRooms prepends a '#' to rooms that are neither aliases nor IDs, skipping any empty entries.
+func normalizeRooms(rooms []string) []string {
+	normalized := make([]string, 0, len(rooms))
+
+	for _, room := range rooms {
+		if room == "" {
+			continue
+		}
+
 		// If room does not begin with a '#' let's prepend it
 		if room[0] != '#' && room[0] != '!' {
-			c.Rooms[r] = "#" + room
+			room = "#" + room
 		}
+
+		normalized = append(normalized, room)
 	}
 
-	return nil
+	return normalized
 }
@@ -0,0 +1,472 @@
+package matrix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	cryptoStoreDirMode  = 0o700
+	cryptoStoreFileMode = 0o600
+)
+
+// ErrUnsupportedAlgorithm indicates that the room is encrypted using an algorithm other than Megolm.
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported room encryption algorithm")
+	ErrNoDeviceID           = errors.New("the access token is not associated with a device")
+	ErrInvalidCryptoStore   = errors.New("invalid crypto store")
+)
+
+// cryptoStore is the persistent state of the end-to-end encryption, which is saved as JSON.
+// It contains the private keys of the device, so the file is only readable by the owner.
+type cryptoStore struct {
+	path          string
+	UserID        string                    `json:"user_id"`
+	DeviceID      string                    `json:"device_id"`
+	Account       *olmAccount               `json:"account"`
+	KeysUploaded  bool                      `json:"keys_uploaded"`
+	OlmSessions   map[string]*olmSession    `json:"olm_sessions"`   // OlmSessions are keyed by the identity key of the device.
+	GroupSessions map[string]*megolmSession `json:"group_sessions"` // GroupSessions are keyed by the room ID.
+}
+
+// loadCryptoStore reads the crypto store from path, or creates an empty store if the file does not exist.
+func loadCryptoStore(path string) (*cryptoStore, error) {
+	store := &cryptoStore{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading crypto store: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, store); err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidCryptoStore, path, err)
+		}
+	}
+
+	if account := store.Account; account != nil &&
+		(len(account.IdentityKey) != olmKeyLength || len(account.SigningKey) != olmKeyLength) {
+		return nil, fmt.Errorf("%w %q: malformed account keys", ErrInvalidCryptoStore, path)
+	}
+
+	store.path = path
+
+	if store.OlmSessions == nil {
+		store.OlmSessions = make(map[string]*olmSession)
+	}
+
+	if store.GroupSessions == nil {
+		store.GroupSessions = make(map[string]*megolmSession)
+	}
+
+	return store, nil
+}
+
+// reset discards all sessions and creates a new account for the device.
+func (st *cryptoStore) reset(userID string, deviceID string) error {
+	account, err := newOlmAccount()
+	if err != nil {
+		return err
+	}
+
+	st.UserID = userID
+	st.DeviceID = deviceID
+	st.Account = account
+	st.KeysUploaded = false
+	st.OlmSessions = make(map[string]*olmSession)
+	st.GroupSessions = make(map[string]*megolmSession)
+
+	return nil
+}
+
+// save writes the store to disk, replacing the previous file atomically.
+func (st *cryptoStore) save() error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("marshaling crypto store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(st.path), cryptoStoreDirMode); err != nil {
+		return fmt.Errorf("creating crypto store directory: %w", err)
+	}
+
+	tempPath := st.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, cryptoStoreFileMode); err != nil {
+		return fmt.Errorf("writing crypto store: %w", err)
+	}
+
+	if err := os.Rename(tempPath, st.path); err != nil {
+		return fmt.Errorf("replacing crypto store: %w", err)
+	}
+
+	return nil
+}
+
+// useCryptoStore enables end-to-end encryption for the client, keeping the keys in the store at path.
+func (c *client) useCryptoStore(path string) error {
+	store, err := loadCryptoStore(path)
+	if err != nil {
+		return err
+	}
+
+	c.crypto = store
+	c.roomEncryption = make(map[string]*apiResRoomEncryption)
+
+	return nil
+}
+
+// setupEncryption makes sure that the store has keys for the current device, and that they have been uploaded.
+func (c *client) setupEncryption() error {
+	if c.deviceID == "" {
+		response := apiResWhoAmI{}
+		if err := c.apiGet(apiWhoAmI, &response); err != nil {
+			return fmt.Errorf("getting device ID: %w", err)
+		}
+
+		if response.DeviceID == "" {
+			return ErrNoDeviceID
+		}
+
+		c.userID = response.UserID
+		c.deviceID = response.DeviceID
+	}
+
+	store := c.crypto
+	if store.Account == nil || store.UserID != c.userID || store.DeviceID != c.deviceID {
+		c.logf("Creating encryption keys for device '%v'\n", c.deviceID)
+
+		if err := store.reset(c.userID, c.deviceID); err != nil {
+			return err
+		}
+	}
+
+	if store.KeysUploaded {
+		return nil
+	}
+
+	keys := &deviceKeys{
+		UserID:     c.userID,
+		DeviceID:   c.deviceID,
+		Algorithms: []string{algorithmOlm, algorithmMegolm},
+		Keys: map[string]string{
+			"curve25519:" + c.deviceID: store.Account.curve25519Key(),
+			"ed25519:" + c.deviceID:    store.Account.ed25519Key(),
+		},
+	}
+
+	message, err := canonicalJSON(keys)
+	if err != nil {
+		return err
+	}
+
+	keys.Signatures = map[string]map[string]string{
+		c.userID: {"ed25519:" + c.deviceID: store.Account.sign(message)},
+	}
+
+	if err := c.apiPost(apiKeysUpload, apiReqKeysUpload{DeviceKeys: keys}, &apiResKeysUpload{}); err != nil {
+		return fmt.Errorf("uploading device keys: %w", err)
+	}
+
+	store.KeysUploaded = true
+
+	return store.save()
+}
+
+// getRoomEncryption returns the encryption settings of the room, or nil if the room is not encrypted.
+func (c *client) getRoomEncryption(roomID string) (*apiResRoomEncryption, error) {
+	if settings, found := c.roomEncryption[roomID]; found {
+		return settings, nil
+	}
+
+	settings := &apiResRoomEncryption{}
+	if err := c.apiGet(fmt.Sprintf(apiRoomEncryption, roomID), settings); err != nil {
+		if resError := (&apiResError{}); !errors.As(err, &resError) || resError.Code != errCodeNotFound {
+			return nil, err
+		}
+
+		settings = nil
+	}
+
+	if settings != nil && settings.Algorithm != algorithmMegolm {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedAlgorithm, settings.Algorithm)
+	}
+
+	c.roomEncryption[roomID] = settings
+
+	return settings, nil
+}
+
+// encryptMessage encrypts the message for the room, sharing the room key with any new devices in the room.
+// The room key is replaced when it has expired, or when any device it was shared with has left the room.
+func (c *client) encryptMessage(
+	request apiReqSend,
+	roomID string,
+	settings *apiResRoomEncryption,
+) (*apiReqEncrypted, error) {
+	if err := c.setupEncryption(); err != nil {
+		return nil, fmt.Errorf("setting up encryption: %w", err)
+	}
+
+	devices, err := c.getRoomDevices(roomID)
+	if err != nil {
+		return nil, fmt.Errorf("getting room devices: %w", err)
+	}
+
+	session := c.crypto.GroupSessions[roomID]
+	if session == nil || sessionExpired(session, settings) || sessionLeaked(session, devices) {
+		c.logf("Creating room key for room '%v'\n", roomID)
+
+		if session, err = newMegolmSession(); err != nil {
+			return nil, err
+		}
+
+		c.crypto.GroupSessions[roomID] = session
+	}
+
+	if err := c.shareRoomKey(roomID, session, devices); err != nil {
+		return nil, fmt.Errorf("sharing room key: %w", err)
+	}
+
+	// Relations are kept unencrypted, so that the server can aggregate threads
+	relatesTo := request.RelatesTo
+	request.RelatesTo = nil
+
+	plaintext, err := json.Marshal(megolmPayload{Type: eventTypeMessage, Content: request, RoomID: roomID})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling message: %w", err)
+	}
+
+	ciphertext, err := session.encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	// The advanced ratchet is saved before sending, so that a message index is never reused
+	if err := c.crypto.save(); err != nil {
+		return nil, err
+	}
+
+	return &apiReqEncrypted{
+		Algorithm:  algorithmMegolm,
+		SenderKey:  c.crypto.Account.curve25519Key(),
+		Ciphertext: ciphertext,
+		SessionID:  session.id(),
+		DeviceID:   c.deviceID,
+		RelatesTo:  relatesTo,
+	}, nil
+}
+
+// getRoomDevices returns the devices of all joined members of the room, keyed by "user|device".
+// Devices with keys that are not correctly signed, as well as the device of the client, are left out.
+func (c *client) getRoomDevices(roomID string) (map[string]deviceKeys, error) {
+	members := apiResJoinedMembers{}
+	if err := c.apiGet(fmt.Sprintf(apiJoinedMembers, roomID), &members); err != nil {
+		return nil, err
+	}
+
+	query := apiReqKeysQuery{DeviceKeys: make(map[string][]string, len(members.Joined))}
+	for userID := range members.Joined {
+		query.DeviceKeys[userID] = []string{}
+	}
+
+	response := apiResKeysQuery{}
+	if err := c.apiPost(apiKeysQuery, query, &response); err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]deviceKeys)
+
+	for userID, userDevices := range response.DeviceKeys {
+		if _, joined := members.Joined[userID]; !joined {
+			continue
+		}
+
+		for deviceID, raw := range userDevices {
+			if userID == c.userID && deviceID == c.deviceID {
+				continue
+			}
+
+			keys := deviceKeys{}
+			if err := json.Unmarshal(raw, &keys); err != nil || keys.UserID != userID ||
+				keys.DeviceID != deviceID || keys.Keys["curve25519:"+deviceID] == "" ||
+				!verifySignature(raw, keys.Signatures, userID, deviceID, keys.Keys["ed25519:"+deviceID]) {
+				c.logf("Ignoring device '%v' of '%v' with invalid keys\n", deviceID, userID)
+
+				continue
+			}
+
+			devices[userID+"|"+deviceID] = keys
+		}
+	}
+
+	return devices, nil
+}
+
+// shareRoomKey sends the room key to all devices it has not been shared with, using Olm encrypted to-device messages.
+func (c *client) shareRoomKey(roomID string, session *megolmSession, devices map[string]deviceKeys) error {
+	pending := make(map[string]deviceKeys)
+
+	for key, device := range devices {
+		if _, shared := session.SharedWith[key]; !shared {
+			pending[key] = device
+		}
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := c.createOlmSessions(pending); err != nil {
+		return err
+	}
+
+	account := c.crypto.Account
+	request := apiReqSendToDevice{Messages: make(map[string]map[string]olmEncrypted)}
+	shared := make(map[string]string)
+
+	for key, device := range pending {
+		identityKey := device.Keys["curve25519:"+device.DeviceID]
+
+		olmSession := c.crypto.OlmSessions[identityKey]
+		if olmSession == nil {
+			continue
+		}
+
+		plaintext, err := json.Marshal(olmPayload{
+			Type: eventTypeRoomKey,
+			Content: roomKey{
+				Algorithm:  algorithmMegolm,
+				RoomID:     roomID,
+				SessionID:  session.id(),
+				SessionKey: session.sessionKey(),
+			},
+			Sender:        c.userID,
+			SenderDevice:  c.deviceID,
+			Keys:          map[string]string{"ed25519": account.ed25519Key()},
+			Recipient:     device.UserID,
+			RecipientKeys: map[string]string{"ed25519": device.Keys["ed25519:"+device.DeviceID]},
+		})
+		if err != nil {
+			return fmt.Errorf("marshaling room key: %w", err)
+		}
+
+		body, err := olmSession.encrypt(account, plaintext)
+		if err != nil {
+			return err
+		}
+
+		if request.Messages[device.UserID] == nil {
+			request.Messages[device.UserID] = make(map[string]olmEncrypted)
+		}
+
+		request.Messages[device.UserID][device.DeviceID] = olmEncrypted{
+			Algorithm:  algorithmOlm,
+			SenderKey:  account.curve25519Key(),
+			Ciphertext: map[string]olmCiphertext{identityKey: {Type: olmMessageTypePre, Body: body}},
+		}
+		shared[key] = identityKey
+	}
+
+	if len(shared) == 0 {
+		return nil
+	}
+
+	if err := c.apiPut(fmt.Sprintf(apiSendToDevice, c.newTxnID()), request, &struct{}{}); err != nil {
+		return err
+	}
+
+	for key, identityKey := range shared {
+		session.SharedWith[key] = identityKey
+	}
+
+	return c.crypto.save()
+}
+
+// createOlmSessions creates Olm sessions for the devices that have none yet, by claiming one of their one-time keys.
+func (c *client) createOlmSessions(devices map[string]deviceKeys) error {
+	request := apiReqKeysClaim{OneTimeKeys: make(map[string]map[string]string)}
+
+	for _, device := range devices {
+		if c.crypto.OlmSessions[device.Keys["curve25519:"+device.DeviceID]] != nil {
+			continue
+		}
+
+		if request.OneTimeKeys[device.UserID] == nil {
+			request.OneTimeKeys[device.UserID] = make(map[string]string)
+		}
+
+		request.OneTimeKeys[device.UserID][device.DeviceID] = keyAlgorithmOTK
+	}
+
+	if len(request.OneTimeKeys) == 0 {
+		return nil
+	}
+
+	response := apiResKeysClaim{}
+	if err := c.apiPost(apiKeysClaim, request, &response); err != nil {
+		return fmt.Errorf("claiming one-time keys: %w", err)
+	}
+
+	for userID, deviceIDs := range request.OneTimeKeys {
+		for deviceID := range deviceIDs {
+			device := devices[userID+"|"+deviceID]
+			identityKey := device.Keys["curve25519:"+deviceID]
+
+			for _, raw := range response.OneTimeKeys[userID][deviceID] {
+				key := oneTimeKey{}
+				if err := json.Unmarshal(raw, &key); err != nil ||
+					!verifySignature(raw, key.Signatures, userID, deviceID, device.Keys["ed25519:"+deviceID]) {
+					continue
+				}
+
+				session, err := newOlmSession(c.crypto.Account, identityKey, key.Key)
+				if err != nil {
+					return err
+				}
+
+				c.crypto.OlmSessions[identityKey] = session
+
+				break
+			}
+
+			if c.crypto.OlmSessions[identityKey] == nil {
+				c.logf("No one-time key available for device '%v' of '%v', skipping it\n", deviceID, userID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sessionExpired reports whether the room key has been used for longer than the room allows.
+func sessionExpired(session *megolmSession, settings *apiResRoomEncryption) bool {
+	periodMS := settings.RotationPeriodMS
+	if periodMS <= 0 {
+		periodMS = defaultRotationMS
+	}
+
+	periodMsgs := settings.RotationPeriodMsgs
+	if periodMsgs <= 0 {
+		periodMsgs = defaultRotationMsg
+	}
+
+	return session.Messages >= periodMsgs || time.Since(session.CreatedAt) >= time.Duration(periodMS)*time.Millisecond
+}
+
+// sessionLeaked reports whether the room key has been shared with any device that is no longer in the room,
+// or that has changed its identity key since.
+func sessionLeaked(session *megolmSession, devices map[string]deviceKeys) bool {
+	for key, identityKey := range session.SharedWith {
+		device, found := devices[key]
+		if !found || device.Keys["curve25519:"+device.DeviceID] != identityKey {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,401 @@
+package matrix
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lengths and constants of the Olm and Megolm protocols.
+const (
+	olmKeyLength          = 32
+	olmMACLength          = 8
+	olmProtocolVersion    = 3
+	megolmRatchetParts    = 4
+	megolmRatchetLength   = megolmRatchetParts * olmKeyLength
+	megolmSessionVersion  = 2
+	derivedAESKeyLength   = 32
+	derivedMACKeyLength   = 32
+	derivedIVLength       = 16
+	olmInfoRoot           = "OLM_ROOT"
+	olmInfoKeys           = "OLM_KEYS"
+	megolmInfoKeys        = "MEGOLM_KEYS"
+	olmMessageKeySeed     = 0x01
+	olmChainKeySeed       = 0x02
+	olmTagRatchetKey      = 0x0A
+	olmTagChainIndex      = 0x10
+	olmTagCiphertext      = 0x22
+	olmTagOneTimeKey      = 0x0A
+	olmTagBaseKey         = 0x12
+	olmTagIdentityKey     = 0x1A
+	olmTagMessage         = 0x22
+	megolmTagMessageIndex = 0x08
+	megolmTagCiphertext   = 0x12
+)
+
+// ErrInvalidKey indicates that a key received from the server could not be decoded.
+var ErrInvalidKey = errors.New("invalid key")
+
+// rawBase64 is the unpadded base64 encoding used for all keys, signatures and messages.
+var rawBase64 = base64.RawStdEncoding
+
+// olmAccount holds the long-term identity keys of the device.
+type olmAccount struct {
+	IdentityKey []byte `json:"identity_key"` // IdentityKey is the curve25519 private key.
+	SigningKey  []byte `json:"signing_key"`  // SigningKey is the ed25519 private key seed.
+}
+
+// olmSession is an outbound Olm session with a single device.
+// Since messages from the device are never received, every message is sent as a pre-key message
+// and the sending chain is never ratcheted, so only the public keys and the chain key are kept.
+type olmSession struct {
+	TheirIdentityKey string `json:"their_identity_key"`
+	OneTimeKey       []byte `json:"one_time_key"`
+	BaseKey          []byte `json:"base_key"`
+	RatchetKey       []byte `json:"ratchet_key"`
+	ChainKey         []byte `json:"chain_key"`
+	ChainIndex       uint32 `json:"chain_index"`
+}
+
+// megolmSession is an outbound Megolm session, used to encrypt the messages sent to a room.
+type megolmSession struct {
+	Ratchet    []byte            `json:"ratchet"`
+	Counter    uint32            `json:"counter"`
+	SigningKey []byte            `json:"signing_key"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Messages   int               `json:"messages"`
+	SharedWith map[string]string `json:"shared_with"` // SharedWith maps "user|device" to the identity key the session was shared with.
+}
+
+// newOlmAccount creates an account with new random identity keys.
+func newOlmAccount() (*olmAccount, error) {
+	identityKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating identity key: %w", err)
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+
+	return &olmAccount{IdentityKey: identityKey.Bytes(), SigningKey: seed}, nil
+}
+
+// curve25519Key returns the encoded public identity key of the account.
+func (a *olmAccount) curve25519Key() string {
+	return rawBase64.EncodeToString(a.identityPrivateKey().PublicKey().Bytes())
+}
+
+// ed25519Key returns the encoded public signing key of the account.
+func (a *olmAccount) ed25519Key() string {
+	return rawBase64.EncodeToString(a.signingPrivateKey().Public().(ed25519.PublicKey))
+}
+
+// sign returns the encoded signature of the message, made with the signing key of the account.
+func (a *olmAccount) sign(message []byte) string {
+	return rawBase64.EncodeToString(ed25519.Sign(a.signingPrivateKey(), message))
+}
+
+func (a *olmAccount) identityPrivateKey() *ecdh.PrivateKey {
+	// The key is validated when the store is loaded, and X25519 accepts any 32 byte key
+	key, _ := ecdh.X25519().NewPrivateKey(a.IdentityKey)
+
+	return key
+}
+
+func (a *olmAccount) signingPrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(a.SigningKey)
+}
+
+// newOlmSession creates an outbound session with the device owning the identity and one-time key,
+// performing the triple Diffie-Hellman key agreement of the Olm protocol.
+func newOlmSession(account *olmAccount, theirIdentityKey string, theirOneTimeKey string) (*olmSession, error) {
+	baseKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating base key: %w", err)
+	}
+
+	ratchetKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating ratchet key: %w", err)
+	}
+
+	return newOlmSessionWithKeys(account, theirIdentityKey, theirOneTimeKey, baseKey, ratchetKey)
+}
+
+// newOlmSessionWithKeys creates an outbound session like newOlmSession, using the given base and ratchet keys.
+func newOlmSessionWithKeys(
+	account *olmAccount,
+	theirIdentityKey string,
+	theirOneTimeKey string,
+	baseKey *ecdh.PrivateKey,
+	ratchetKey *ecdh.PrivateKey,
+) (*olmSession, error) {
+	identityKey, err := decodeCurve25519Key(theirIdentityKey)
+	if err != nil {
+		return nil, err
+	}
+
+	oneTimeKey, err := decodeCurve25519Key(theirOneTimeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 0, 3*olmKeyLength)
+
+	for _, pair := range []struct {
+		private *ecdh.PrivateKey
+		public  *ecdh.PublicKey
+	}{
+		{account.identityPrivateKey(), oneTimeKey},
+		{baseKey, identityKey},
+		{baseKey, oneTimeKey},
+	} {
+		shared, err := pair.private.ECDH(pair.public)
+		if err != nil {
+			return nil, fmt.Errorf("computing shared secret: %w", err)
+		}
+
+		secret = append(secret, shared...)
+	}
+
+	// The first half of the derived secret is the root key, which is never needed for sending only
+	derived, err := hkdf.Key(sha256.New, secret, nil, olmInfoRoot, 2*olmKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("deriving root key: %w", err)
+	}
+
+	return &olmSession{
+		TheirIdentityKey: theirIdentityKey,
+		OneTimeKey:       oneTimeKey.Bytes(),
+		BaseKey:          baseKey.PublicKey().Bytes(),
+		RatchetKey:       ratchetKey.PublicKey().Bytes(),
+		ChainKey:         derived[olmKeyLength:],
+	}, nil
+}
+
+// encrypt encrypts the plaintext as an encoded pre-key message, advancing the sending chain.
+func (s *olmSession) encrypt(account *olmAccount, plaintext []byte) (string, error) {
+	messageKey := hmacSHA256(s.ChainKey, []byte{olmMessageKeySeed})
+
+	ciphertext, macKey, err := encryptAESSHA2(messageKey, olmInfoKeys, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	message := []byte{olmProtocolVersion}
+	message = appendBytesField(message, olmTagRatchetKey, s.RatchetKey)
+	message = appendVarintField(message, olmTagChainIndex, uint64(s.ChainIndex))
+	message = appendBytesField(message, olmTagCiphertext, ciphertext)
+	message = append(message, hmacSHA256(macKey, message)[:olmMACLength]...)
+
+	identityKey := account.identityPrivateKey().PublicKey().Bytes()
+
+	preKeyMessage := []byte{olmProtocolVersion}
+	preKeyMessage = appendBytesField(preKeyMessage, olmTagOneTimeKey, s.OneTimeKey)
+	preKeyMessage = appendBytesField(preKeyMessage, olmTagBaseKey, s.BaseKey)
+	preKeyMessage = appendBytesField(preKeyMessage, olmTagIdentityKey, identityKey)
+	preKeyMessage = appendBytesField(preKeyMessage, olmTagMessage, message)
+
+	s.ChainKey = hmacSHA256(s.ChainKey, []byte{olmChainKeySeed})
+	s.ChainIndex++
+
+	return rawBase64.EncodeToString(preKeyMessage), nil
+}
+
+// newMegolmSession creates an outbound group session with a random ratchet and signing key.
+func newMegolmSession() (*megolmSession, error) {
+	ratchet := make([]byte, megolmRatchetLength)
+	if _, err := rand.Read(ratchet); err != nil {
+		return nil, fmt.Errorf("generating ratchet: %w", err)
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("generating signing key: %w", err)
+	}
+
+	return &megolmSession{
+		Ratchet:    ratchet,
+		SigningKey: seed,
+		CreatedAt:  time.Now(),
+		SharedWith: make(map[string]string),
+	}, nil
+}
+
+// id returns the session ID, which is the encoded public signing key.
+func (s *megolmSession) id() string {
+	return rawBase64.EncodeToString(s.signingPrivateKey().Public().(ed25519.PublicKey))
+}
+
+// sessionKey returns the encoded session key, which lets recipients decrypt messages from the current index.
+func (s *megolmSession) sessionKey() string {
+	key := []byte{megolmSessionVersion}
+	key = binary.BigEndian.AppendUint32(key, s.Counter)
+	key = append(key, s.Ratchet...)
+	key = append(key, s.signingPrivateKey().Public().(ed25519.PublicKey)...)
+	key = append(key, ed25519.Sign(s.signingPrivateKey(), key)...)
+
+	return rawBase64.EncodeToString(key)
+}
+
+// encrypt encrypts the plaintext as an encoded group message, advancing the ratchet.
+func (s *megolmSession) encrypt(plaintext []byte) (string, error) {
+	ciphertext, macKey, err := encryptAESSHA2(s.Ratchet, megolmInfoKeys, plaintext)
+	if err != nil {
+		return "", err
+	}
+
+	message := []byte{olmProtocolVersion}
+	message = appendVarintField(message, megolmTagMessageIndex, uint64(s.Counter))
+	message = appendBytesField(message, megolmTagCiphertext, ciphertext)
+	message = append(message, hmacSHA256(macKey, message)[:olmMACLength]...)
+	message = append(message, ed25519.Sign(s.signingPrivateKey(), message)...)
+
+	s.advance()
+	s.Messages++
+
+	return rawBase64.EncodeToString(message), nil
+}
+
+// advance moves the ratchet forward by one step. Only the parts of the ratchet that change
+// when incrementing the counter are rehashed, from the most significant changed part.
+func (s *megolmSession) advance() {
+	s.Counter++
+
+	from := 0
+	for mask := uint32(0x00FFFFFF); from < megolmRatchetParts && s.Counter&mask != 0; mask >>= 8 {
+		from++
+	}
+
+	source := bytes.Clone(s.Ratchet[from*olmKeyLength : (from+1)*olmKeyLength])
+
+	for part := megolmRatchetParts - 1; part >= from; part-- {
+		copy(s.Ratchet[part*olmKeyLength:], hmacSHA256(source, []byte{byte(part)}))
+	}
+}
+
+func (s *megolmSession) signingPrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(s.SigningKey)
+}
+
+// encryptAESSHA2 encrypts the plaintext with AES-256-CBC, using the keys derived from the secret.
+// It returns the ciphertext and the derived key that is used for authenticating the message.
+func encryptAESSHA2(secret []byte, info string, plaintext []byte) ([]byte, []byte, error) {
+	derived, err := hkdf.Key(
+		sha256.New, secret, nil, info, derivedAESKeyLength+derivedMACKeyLength+derivedIVLength)
+	if err != nil {
+		return nil, nil, fmt.Errorf("deriving message keys: %w", err)
+	}
+
+	aesKey := derived[:derivedAESKeyLength]
+	macKey := derived[derivedAESKeyLength : derivedAESKeyLength+derivedMACKeyLength]
+	iv := derived[derivedAESKeyLength+derivedMACKeyLength:]
+
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating cipher: %w", err)
+	}
+
+	// PKCS#7 padding, which always adds at least one byte
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	return ciphertext, macKey, nil
+}
+
+// canonicalJSON encodes the value as canonical JSON, as used for signing, with sorted keys and no
+// insignificant whitespace. Any signatures and unsigned data in the value are left out.
+func canonicalJSON(value any) ([]byte, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling value: %w", err)
+	}
+
+	object := map[string]any{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("unmarshaling value: %w", err)
+	}
+
+	delete(object, "signatures")
+	delete(object, "unsigned")
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(object); err != nil {
+		return nil, fmt.Errorf("encoding canonical JSON: %w", err)
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// verifySignature reports whether the value has been signed by the user's device with the signing key.
+func verifySignature(value any, signatures map[string]map[string]string, userID string, deviceID string,
+	signingKey string,
+) bool {
+	publicKey, err := rawBase64.DecodeString(signingKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+
+	signature, err := rawBase64.DecodeString(signatures[userID]["ed25519:"+deviceID])
+	if err != nil {
+		return false
+	}
+
+	message, err := canonicalJSON(value)
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(publicKey, message, signature)
+}
+
+func decodeCurve25519Key(encoded string) (*ecdh.PublicKey, error) {
+	raw, err := rawBase64.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return key, nil
+}
+
+func hmacSHA256(key []byte, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+
+	return mac.Sum(nil)
+}
+
+// appendVarintField appends a protobuf style integer field to the message.
+func appendVarintField(message []byte, tag byte, value uint64) []byte {
+	return binary.AppendUvarint(append(message, tag), value)
+}
+
+// appendBytesField appends a protobuf style length-delimited field to the message.
+func appendBytesField(message []byte, tag byte, value []byte) []byte {
+	return append(binary.AppendUvarint(append(message, tag), uint64(len(value))), value...)
+}
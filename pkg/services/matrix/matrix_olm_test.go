@@ -0,0 +1,116 @@
+package matrix
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/hex"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+)
+
+// The expected values below were derived from the Olm and Megolm specifications independently of this
+// package, computing X25519, HKDF, AES-CBC and Ed25519 with OpenSSL. The Olm session was derived from the
+// receiving side of the triple Diffie-Hellman, and the Megolm ratchet from its definition of R(i,j).
+const (
+	olmTestIdentityKey  = "/y7kVgHsG2cxDHeQQEWFrmlzMe7hwfjPJBlzHB//Pms"
+	olmTestOneTimeKey   = "OKtmS9hvd9fma92a4HkpE6lP2LM6EmACfktGwfSITGc"
+	olmTestFirstMessage = "AwogOKtmS9hvd9fma92a4HkpE6lP2LM6EmACfktGwfSITGcSIA+qaE7SiGe5f0pqLe5d+M6XTna3AY4/IqHEzyZ4Vw8gGiB7" +
+		"TpCbvn/+RMRloiADfWCO41iX0x75cvB/dIkssPc/EyI/Awogew1H2TQn+DERYHgcfHM/2J+IlwrvSQ2KoO4ZpMuKGxQQACIQBnSz" +
+		"La5tKY65T3tbejDTOVnAjqblR1Nv"
+	olmTestSecondMessage = "AwogOKtmS9hvd9fma92a4HkpE6lP2LM6EmACfktGwfSITGcSIA+qaE7SiGe5f0pqLe5d+M6XTna3AY4/IqHEzyZ4Vw8gGiB7" +
+		"TpCbvn/+RMRloiADfWCO41iX0x75cvB/dIkssPc/EyI/Awogew1H2TQn+DERYHgcfHM/2J+IlwrvSQ2KoO4ZpMuKGxQQASIQDMAT" +
+		"2Z2XhtxZJCYOjj/EyEe5ac1zky22"
+	megolmTestRatchet1 = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+		"202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f" +
+		"404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f" +
+		"550744e334115fa1fd73d3b71176d4157288631cb37045a49fd62cd0608c8f5d"
+	megolmTestRatchet010203 = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" +
+		"797f21ad97515c3ed5cb1f8e6ec28cd83627aa8dc4ced0717120abbb03397159" +
+		"201a0fb884f73490cd5f073da95216d73e02b9d2fb0fc19bae3266158693b80e" +
+		"60e847fb896937043a0f7095c52a9def2c3bf9fb23442bfb6be3cc253aee55d3"
+	megolmTestRatchet1000000 = "e711546e3faad4c7c4aa756bc26cad6abea8241984a0f6b0839c70ca61c4ef88" +
+		"9b4c8120a4823a95f47cde17a244f4507244ee6e3957d1fab9fa29b44d3829b7" +
+		"4304c22c84a53755ab08ead8d97a8d429be5efa480682d7ad1da27f73e1fbe1d" +
+		"2a24d008789d3c74daf5e02636c675df8f09ec5e740c1bdf6305f9261f7b1c32"
+	megolmTestSessionID  = "NLTZBDFWy23PC+sKKUm3VZyUDSvLbb6MU6mzAnjjp0Y"
+	megolmTestSessionKey = "AgABAgMAAQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eH3l/Ia2XUVw+1csfjm7CjNg2J6qNxM7QcXEgq7sDOXFZ" +
+		"IBoPuIT3NJDNXwc9qVIW1z4CudL7D8GbrjJmFYaTuA5g6Ef7iWk3BDoPcJXFKp3vLDv5+yNEK/tr48wlOu5V0zS02QQxVsttzwvr" +
+		"CilJt1WclA0ry22+jFOpswJ446dGPKuGEoPbk2B+ab4Evzq9h7aAXToU7g7PSl47I1rznyRfJ+d1a+c6URdIHbQreUqorPRifcke" +
+		"CiGWt4MJhrjcAA"
+	megolmTestMessage = "AwiDhAQSEAjQu/405qtJPu29/xgMjONgv2BMZ/7knmYVjv16+iVjL8+vS45JWXap+LDfF/eB6NCADgt3e0u8F80hW4Jz" +
+		"XQMrbfOiTMMV4ZiwZTIBlpe9tfQ4RU+Fswc"
+)
+
+// testKey returns a key consisting of the repeated byte.
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, olmKeyLength)
+}
+
+// testCurve25519Key returns the X25519 private key consisting of the repeated byte.
+func testCurve25519Key(b byte) *ecdh.PrivateKey {
+	key, err := ecdh.X25519().NewPrivateKey(testKey(b))
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	return key
+}
+
+// testMegolmRatchet returns the initial ratchet of the Megolm test vectors, consisting of the bytes 0 to 127.
+func testMegolmRatchet() []byte {
+	ratchet := make([]byte, megolmRatchetLength)
+	for i := range ratchet {
+		ratchet[i] = byte(i)
+	}
+
+	return ratchet
+}
+
+var _ = ginkgo.Describe("the Olm and Megolm implementation", func() {
+	ginkgo.It("should create the pre-key messages of the test vectors", func() {
+		account := &olmAccount{IdentityKey: testKey(0x11), SigningKey: testKey(0x77)}
+
+		session, err := newOlmSessionWithKeys(
+			account, olmTestIdentityKey, olmTestOneTimeKey, testCurve25519Key(0x22), testCurve25519Key(0x33),
+		)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		message, err := session.encrypt(account, []byte("first message"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(message).To(gomega.Equal(olmTestFirstMessage))
+
+		message, err = session.encrypt(account, []byte("second message"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(message).To(gomega.Equal(olmTestSecondMessage))
+	})
+
+	ginkgo.It("should advance the Megolm ratchet as in the test vectors", func() {
+		session := &megolmSession{Ratchet: testMegolmRatchet()}
+
+		session.advance()
+		gomega.Expect(hex.EncodeToString(session.Ratchet)).To(gomega.Equal(megolmTestRatchet1))
+
+		for session.Counter < 0x010203 {
+			session.advance()
+		}
+
+		gomega.Expect(hex.EncodeToString(session.Ratchet)).To(gomega.Equal(megolmTestRatchet010203))
+
+		session = &megolmSession{Ratchet: testMegolmRatchet(), Counter: 0x00FFFFFF}
+		session.advance()
+		gomega.Expect(hex.EncodeToString(session.Ratchet)).To(gomega.Equal(megolmTestRatchet1000000))
+	})
+
+	ginkgo.It("should create the Megolm session key and message of the test vectors", func() {
+		ratchet, err := hex.DecodeString(megolmTestRatchet010203)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		session := &megolmSession{Ratchet: ratchet, Counter: 0x010203, SigningKey: testKey(0x66)}
+		gomega.Expect(session.id()).To(gomega.Equal(megolmTestSessionID))
+		gomega.Expect(session.sessionKey()).To(gomega.Equal(megolmTestSessionKey))
+
+		message, err := session.encrypt([]byte("group message"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(message).To(gomega.Equal(megolmTestMessage))
+		gomega.Expect(session.Counter).To(gomega.Equal(uint32(0x010204)))
+	})
+})
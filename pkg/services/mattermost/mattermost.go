@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // defaultHTTPTimeout is the default timeout for HTTP requests.
@@ -105,6 +107,28 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for sending the message, without sending it.
+// The webhook token is redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	payload, err := CreateJSONPayload(&config, message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := util.RedactedJSONRequest(buildURL(&config), json.RawMessage(payload), config.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
 // buildURL constructs the API URL for Mattermost based on the Config.
 func buildURL(config *Config) string {
 	scheme := "https"
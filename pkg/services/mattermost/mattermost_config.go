@@ -103,9 +103,8 @@ func (c *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) error
 // parsePath extracts Token and Channel from the URL path and validates arguments.
 func (c *Config) parsePath(url *url.URL) error {
 	path := strings.Split(strings.Trim(url.Path, "/"), "/")
-	isDummy := url.String() == "mattermost://dummy@dummy.com"
 
-	if !isDummy && (len(path) < 1 || path[0] == "") {
+	if len(path) < 1 || path[0] == "" {
 		return ErrNotEnoughArguments
 	}
 
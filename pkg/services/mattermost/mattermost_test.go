@@ -221,6 +221,23 @@ var _ = ginkgo.Describe("the mattermost service", func() {
 					httpmock.ResponderFromResponse(resp),
 				)
 			})
+			ginkgo.It("should render the request with the token redacted when doing a dry run", func() {
+				config := Config{
+					Host:  "mattermost.host",
+					Token: "secret-token",
+				}
+				service := Service{}
+				gomega.Expect(service.Initialize(config.GetURL(), nil)).To(gomega.Succeed())
+				httpmock.ActivateNonDefault(service.httpClient)
+
+				requests, err := service.DryRun("Message", &types.Params{"icon": "https://example.com/icon.png"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).To(gomega.Equal("https://mattermost.host/hooks/REDACTED"))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"icon_url":"https://example.com/icon.png"`))
+				gomega.Expect(service.Config.Icon).To(gomega.BeEmpty())
+			})
 		})
 	})
 
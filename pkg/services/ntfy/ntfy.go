@@ -1,6 +1,7 @@
 package ntfy
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
@@ -35,6 +37,24 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for sending the message, without sending it.
+// The credentials are redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, config.GetAPIURL(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	setHeaders(req.Header, &config)
+
+	return []types.Request{util.RedactedRequest(req, []byte(message), config.Password)}, nil
+}
+
 // Initialize configures the service with a URL and logger.
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.SetLogger(logger)
@@ -59,6 +79,22 @@ func (service *Service) sendAPI(config *Config, message string) error {
 
 	headers := jsonClient.Headers()
 	headers.Del("Content-Type")
+	setHeaders(headers, config)
+
+	if err := jsonClient.Post(config.GetAPIURL(), request, &response); err != nil {
+		if jsonClient.ErrorResponse(err, &response) {
+			// apiResponse implements Error
+			return &response
+		}
+
+		return fmt.Errorf("posting to Ntfy API: %w", err)
+	}
+
+	return nil
+}
+
+// setHeaders sets the headers used for passing the config options to the Ntfy API.
+func setHeaders(headers http.Header, config *Config) {
 	headers.Set("User-Agent", "shoutrrr/"+meta.Version)
 	addHeaderIfNotEmpty(&headers, "Title", config.Title)
 	addHeaderIfNotEmpty(&headers, "Priority", config.Priority.String())
@@ -78,17 +114,6 @@ func (service *Service) sendAPI(config *Config, message string) error {
 	if !config.Firebase {
 		headers.Add("Firebase", "no")
 	}
-
-	if err := jsonClient.Post(config.GetAPIURL(), request, &response); err != nil {
-		if jsonClient.ErrorResponse(err, &response) {
-			// apiResponse implements Error
-			return &response
-		}
-
-		return fmt.Errorf("posting to Ntfy API: %w", err)
-	}
-
-	return nil
 }
 
 // addHeaderIfNotEmpty adds a header to the request if the value is non-empty.
@@ -109,10 +109,8 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		}
 	}
 
-	if url.String() != "ntfy://dummy@dummy.com" {
-		if config.Topic == "" {
-			return ErrTopicRequired
-		}
+	if config.Topic == "" {
+		return ErrTopicRequired
 	}
 
 	return nil
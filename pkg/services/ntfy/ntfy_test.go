@@ -15,6 +15,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
 func TestNtfy(t *testing.T) {
@@ -128,6 +129,20 @@ var _ = ginkgo.Describe("the ntfy service", func() {
 			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
 			gomega.Expect(service.Send("Message", nil)).To(gomega.HaveOccurred())
 		})
+
+		ginkgo.It("should render the request with the credentials redacted", func() {
+			serviceURL := testutils.URLMust("ntfy://:devicekey@hostname/testtopic")
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("Message", &types.Params{"title": "Backup"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal("https://REDACTED@hostname/testtopic"))
+			gomega.Expect(requests[0].Headers.Get("Title")).To(gomega.Equal("Backup"))
+			gomega.Expect(requests[0].Body).To(gomega.Equal("Message"))
+			gomega.Expect(service.Config.Title).To(gomega.BeEmpty())
+		})
 	})
 
 	ginkgo.Describe("the basic service API", func() {
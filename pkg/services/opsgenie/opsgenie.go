@@ -14,6 +14,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // alertEndpointTemplate is the OpsGenie API endpoint template for sending alerts.
@@ -36,24 +37,14 @@ type Service struct {
 
 // sendAlert sends an alert to OpsGenie using the specified URL and API key.
 func (service *Service) sendAlert(url string, apiKey string, payload AlertPayload) error {
-	jsonBody, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("marshaling alert payload to JSON: %w", err)
-	}
-
-	jsonBuffer := bytes.NewBuffer(jsonBody)
-
 	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, jsonBuffer)
+	req, _, err := newAlertRequest(ctx, url, apiKey, payload)
 	if err != nil {
-		return fmt.Errorf("creating HTTP request: %w", err)
+		return err
 	}
 
-	req.Header.Add("Authorization", "GenieKey "+apiKey)
-	req.Header.Add("Content-Type", "application/json")
-
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send notification to OpsGenie: %w", err)
@@ -78,6 +69,29 @@ func (service *Service) sendAlert(url string, apiKey string, payload AlertPayloa
 	return nil
 }
 
+// newAlertRequest creates the HTTP request for sending the alert, returning it together with its body.
+func newAlertRequest(
+	ctx context.Context,
+	url string,
+	apiKey string,
+	payload AlertPayload,
+) (*http.Request, []byte, error) {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling alert payload to JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+
+	req.Header.Add("Authorization", "GenieKey "+apiKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	return req, jsonBody, nil
+}
+
 // Initialize configures the service with a URL and logger.
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.SetLogger(logger)
@@ -106,6 +120,25 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return service.sendAlert(endpointURL, config.APIKey, payload)
 }
 
+// DryRun renders the request used for sending the message as an alert, without sending it.
+// The API key is redacted from the Authorization header.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := service.Config
+	endpointURL := fmt.Sprintf(alertEndpointTemplate, config.Host, config.Port)
+
+	payload, err := service.newAlertPayload(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, body, err := newAlertRequest(context.Background(), endpointURL, config.APIKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{util.RedactedRequest(req, body, config.APIKey)}, nil
+}
+
 // newAlertPayload creates a new alert payload for OpsGenie based on the message and parameters.
 func (service *Service) newAlertPayload(
 	message string,
@@ -80,12 +80,10 @@ func (config *Config) SetURL(url *url.URL) error {
 func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) error {
 	config.Host = url.Hostname()
 
-	if url.String() != "opsgenie://dummy@dummy.com" {
-		if len(url.Path) > 0 {
-			config.APIKey = url.Path[1:]
-		} else {
-			return ErrAPIKeyMissing
-		}
+	if len(url.Path) > 0 {
+		config.APIKey = url.Path[1:]
+	} else {
+		return ErrAPIKeyMissing
 	}
 
 	if url.Port() != "" {
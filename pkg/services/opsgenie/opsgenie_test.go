@@ -97,6 +97,21 @@ var _ = ginkgo.Describe("the OpsGenie service", func() {
 			})
 		})
 
+		ginkgo.When("doing a dry run", func() {
+			ginkgo.It("should render the request with the API key redacted", func() {
+				checkRequest = func(string, http.Header) {
+					ginkgo.Fail("no request should be sent")
+				}
+
+				requests, err := service.DryRun("hello world", nil)
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).To(gomega.Equal(fmt.Sprintf("https://%s/v2/alerts", mockHost)))
+				gomega.Expect(requests[0].Headers.Get("Authorization")).To(gomega.Equal("GenieKey REDACTED"))
+				gomega.Expect(requests[0].Body).To(gomega.Equal(`{"message":"hello world"}`))
+			})
+		})
+
 		ginkgo.When("sending an alert with runtime parameters", func() {
 			ginkgo.It(
 				"should send a request to our mock OpsGenie server with all fields populated from runtime parameters",
@@ -0,0 +1,237 @@
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
+)
+
+const (
+	enqueuePath      = "/v2/enqueue" // enqueuePath is the Events API v2 endpoint path.
+	MaxSummaryLength = 1024          // MaxSummaryLength is the maximum number of characters in the event summary.
+	messageDetailKey = "message"     // messageDetailKey is the custom details key used for the full message.
+)
+
+// ErrDedupKeyRequired indicates that an acknowledge or resolve event was sent without a dedup key.
+var ErrDedupKeyRequired = errors.New("a dedup key is required to acknowledge or resolve an incident")
+
+// Service provides PagerDuty as a notification service.
+type Service struct {
+	standard.Standard
+	Config *Config
+	pkr    format.PropKeyResolver
+}
+
+// Initialize configures the service with a URL and logger.
+func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
+	service.SetLogger(logger)
+	service.Config = &Config{}
+	service.pkr = format.NewPropKeyResolver(service.Config)
+
+	if err := service.pkr.SetDefaultProps(service.Config); err != nil {
+		return fmt.Errorf("setting default properties: %w", err)
+	}
+
+	return service.Config.setURL(&service.pkr, configURL)
+}
+
+// GetID returns the service identifier.
+func (service *Service) GetID() string {
+	return Scheme
+}
+
+// Send delivers a notification message to PagerDuty as an Events API v2 event.
+// Params that do not match a config key are added to the event's custom details.
+// See: https://developer.pagerduty.com/docs/send-alert-event
+func (service *Service) Send(message string, params *types.Params) error {
+	payload, err := service.newEventPayload(message, types.Unknown, time.Time{}, params)
+	if err != nil {
+		return err
+	}
+
+	return service.sendEvent(payload)
+}
+
+// SendItems delivers message items to PagerDuty as a single event.
+// The severity of the event is derived from the most severe item level, unless
+// the severity is explicitly set using params.
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	level := types.Unknown
+	timestamp := time.Time{}
+
+	for _, item := range items {
+		if item.Level > level && item.Level < types.MessageLevel(types.MessageLevelCount) {
+			level = item.Level
+		}
+
+		if item.Timestamp.After(timestamp) {
+			timestamp = item.Timestamp
+		}
+	}
+
+	message := strings.TrimSuffix(types.ItemsToPlain(items), "\n")
+
+	payload, err := service.newEventPayload(message, level, timestamp, params)
+	if err != nil {
+		return err
+	}
+
+	return service.sendEvent(payload)
+}
+
+// DryRun renders the request used for sending the message as an event, without sending it.
+// The integration key is redacted from the request body.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	payload, err := service.newEventPayload(message, types.Unknown, time.Time{}, params)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := util.RedactedJSONRequest(service.Config.apiURL(), payload, service.Config.IntegrationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
+// sendEvent posts the event payload to the PagerDuty Events API.
+func (service *Service) sendEvent(payload EventPayload) error {
+	response := eventResponse{}
+	client := jsonclient.NewClient()
+
+	if err := client.Post(service.Config.apiURL(), payload, &response); err != nil {
+		if client.ErrorResponse(err, &response) {
+			// eventResponse implements Error
+			return &response
+		}
+
+		return fmt.Errorf("failed to send notification to PagerDuty: %w", err)
+	}
+
+	service.Logf("PagerDuty event accepted with dedup key %q", response.DedupKey)
+
+	return nil
+}
+
+// newEventPayload creates a new event payload for PagerDuty based on the message and parameters.
+func (service *Service) newEventPayload(
+	message string,
+	level types.MessageLevel,
+	timestamp time.Time,
+	params *types.Params,
+) (EventPayload, error) {
+	configParams, customDetails := service.splitParams(params)
+
+	// Defensive copy
+	payloadFields := *service.Config
+
+	if err := service.pkr.UpdateConfigFromParams(&payloadFields, &configParams); err != nil {
+		return EventPayload{}, fmt.Errorf("updating payload fields from params: %w", err)
+	}
+
+	result := EventPayload{
+		RoutingKey:  payloadFields.IntegrationKey,
+		EventAction: payloadFields.Action.apiValue(),
+		DedupKey:    payloadFields.DedupKey,
+	}
+
+	if payloadFields.Action != ActionTrigger {
+		if result.DedupKey == "" {
+			return EventPayload{}, ErrDedupKeyRequired
+		}
+
+		return result, nil
+	}
+
+	eventSeverity := payloadFields.Severity
+	if _, explicit := configParams["severity"]; !explicit {
+		if mapped, ok := severityFromLevel(level); ok {
+			eventSeverity = mapped
+		}
+	}
+
+	// Use the title as the summary if available, or the message if it fits.
+	// The full message is added to the custom details in all other cases.
+	summary := payloadFields.Title
+	if summary == "" {
+		summary = message
+	}
+
+	// Truncate on a rune boundary, since PagerDuty rejects summaries that are not valid UTF-8
+	if runes := []rune(summary); len(runes) > MaxSummaryLength {
+		summary = string(runes[:MaxSummaryLength])
+	}
+
+	details := make(map[string]string, len(payloadFields.Details)+len(customDetails)+1)
+	for key, value := range payloadFields.Details {
+		details[key] = value
+	}
+
+	for key, value := range customDetails {
+		details[key] = value
+	}
+
+	if summary != message {
+		details[messageDetailKey] = message
+	}
+
+	eventDetails := &EventDetails{
+		Summary:   summary,
+		Source:    payloadFields.Source,
+		Severity:  eventSeverity.apiValue(),
+		Component: payloadFields.Component,
+		Group:     payloadFields.Group,
+		Class:     payloadFields.Class,
+	}
+
+	if len(details) > 0 {
+		eventDetails.CustomDetails = details
+	}
+
+	if !timestamp.IsZero() {
+		eventDetails.Timestamp = timestamp.UTC().Format(time.RFC3339)
+	}
+
+	result.Payload = eventDetails
+	result.Client = payloadFields.Client
+	result.ClientURL = payloadFields.ClientURL
+	result.Links = payloadFields.Links
+	result.Images = payloadFields.Images
+
+	return result, nil
+}
+
+// splitParams separates the params that correspond to config keys from the ones
+// that should be passed on as custom details of the event.
+func (service *Service) splitParams(params *types.Params) (types.Params, map[string]string) {
+	configParams := types.Params{}
+	customDetails := map[string]string{}
+
+	if params == nil {
+		return configParams, customDetails
+	}
+
+	keys := make(map[string]bool)
+	for _, key := range service.pkr.QueryFields() {
+		keys[key] = true
+	}
+
+	for key, value := range *params {
+		if keys[strings.ToLower(key)] {
+			configParams[strings.ToLower(key)] = value
+		} else {
+			customDetails[key] = value
+		}
+	}
+
+	return configParams, customDetails
+}
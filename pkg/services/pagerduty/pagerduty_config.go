@@ -0,0 +1,125 @@
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+const (
+	defaultHost = "events.pagerduty.com" // defaultHost is the default PagerDuty Events API host.
+	defaultPort = 443                    // defaultPort is the default port for PagerDuty API connections.
+	Scheme      = "pagerduty"            // Scheme is the identifying part of this service's configuration URL.
+)
+
+// ErrIntegrationKeyMissing indicates that the integration key is missing from the config URL path.
+var ErrIntegrationKeyMissing = errors.New("integration key missing from config URL path")
+
+// Config holds the configuration for the PagerDuty service.
+type Config struct {
+	IntegrationKey string            `desc:"The PagerDuty Events API v2 integration (routing) key"                          url:"path"`
+	Host           string            `desc:"The PagerDuty Events API host"                                                  url:"host" default:"events.pagerduty.com"`
+	Port           uint16            `desc:"The PagerDuty Events API port"                                                  url:"port" default:"443"`
+	Action         eventAction       `desc:"Event action, one of trigger, acknowledge or resolve"                                      default:"trigger"            key:"action"`
+	Severity       severity          `desc:"Severity of the event, used unless overridden by the message item levels"                  default:"error"              key:"severity"`
+	DedupKey       string            `desc:"Deduplication key, required to acknowledge or resolve an incident"                                                      key:"dedupkey,dedup" optional:"true"`
+	Source         string            `desc:"Unique location of the affected system, preferably a hostname or FQDN"                     default:"shoutrrr"           key:"source"`
+	Component      string            `desc:"Component of the source machine that is responsible for the event"                                                     key:"component"      optional:"true"`
+	Group          string            `desc:"Logical grouping of components of a service"                                                                           key:"group"          optional:"true"`
+	Class          string            `desc:"The class/type of the event"                                                                                           key:"class"          optional:"true"`
+	Details        map[string]string `desc:"Map of key-value pairs added to the custom details of the event"                                                        key:"details"        optional:"true"`
+	Links          []Link            `desc:"Links attached to the incident, in the format href or href|text"                                                       key:"links"          optional:"true"`
+	Images         []Image           `desc:"Images attached to the incident, in the format src or src|alt"                                                         key:"images"         optional:"true"`
+	Client         string            `desc:"Name of the monitoring client that is triggering the event"                                                            key:"client"         optional:"true"`
+	ClientURL      string            `desc:"URL of the monitoring client that is triggering the event"                                                             key:"clienturl"      optional:"true"`
+	Title          string            `desc:"Notification title, used as the event summary when set"                                    default:""                   key:"title"`
+}
+
+// Enums returns the fields that use an EnumFormatter for their values.
+func (config *Config) Enums() map[string]types.EnumFormatter {
+	return map[string]types.EnumFormatter{
+		"Action":   EventActions.Enum,
+		"Severity": Severities.Enum,
+	}
+}
+
+// GetURL returns a URL representation of the Config's current field values.
+func (config *Config) GetURL() *url.URL {
+	resolver := format.NewPropKeyResolver(config)
+
+	return config.getURL(&resolver)
+}
+
+// getURL constructs a URL from the Config's fields using the provided resolver.
+func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
+	host := config.Host
+	if config.Port > 0 && config.Port != defaultPort {
+		host = net.JoinHostPort(config.Host, strconv.FormatUint(uint64(config.Port), 10))
+	}
+
+	return &url.URL{
+		Host:     host,
+		Path:     "/" + config.IntegrationKey,
+		Scheme:   Scheme,
+		RawQuery: format.BuildQuery(resolver),
+	}
+}
+
+// SetURL updates the Config from a URL representation of its field values.
+func (config *Config) SetURL(url *url.URL) error {
+	resolver := format.NewPropKeyResolver(config)
+
+	return config.setURL(&resolver, url)
+}
+
+// setURL updates the Config from a URL using the provided resolver.
+func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) error {
+	config.Host = url.Hostname()
+	if config.Host == "" {
+		config.Host = defaultHost
+	}
+
+	if len(url.Path) > 1 {
+		config.IntegrationKey = url.Path[1:]
+	} else {
+		return ErrIntegrationKeyMissing
+	}
+
+	if url.Port() != "" {
+		port, err := strconv.ParseUint(url.Port(), 10, 16)
+		if err != nil {
+			return fmt.Errorf("parsing port %q: %w", url.Port(), err)
+		}
+
+		config.Port = uint16(port)
+	} else {
+		config.Port = defaultPort
+	}
+
+	for key, vals := range url.Query() {
+		if err := resolver.Set(key, vals[0]); err != nil {
+			return fmt.Errorf("setting query parameter %q to %q: %w", key, vals[0], err)
+		}
+	}
+
+	return nil
+}
+
+// apiURL returns the Events API v2 endpoint for the configured host and port.
+func (config *Config) apiURL() string {
+	host := config.Host
+	if config.Port > 0 && config.Port != defaultPort {
+		host = net.JoinHostPort(config.Host, strconv.FormatUint(uint64(config.Port), 10))
+	}
+
+	return (&url.URL{
+		Scheme: "https",
+		Host:   host,
+		Path:   enqueuePath,
+	}).String()
+}
@@ -0,0 +1,111 @@
+package pagerduty
+
+import (
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// Event actions as constants.
+const (
+	ActionTrigger     eventAction = 0
+	ActionAcknowledge eventAction = 1
+	ActionResolve     eventAction = 2
+)
+
+// Severity levels as constants.
+const (
+	SeverityCritical severity = 0
+	SeverityError    severity = 1
+	SeverityWarning  severity = 2
+	SeverityInfo     severity = 3
+)
+
+// EventActions is the enum helper for the PagerDuty event actions.
+var EventActions = &eventActionVals{
+	Trigger:     ActionTrigger,
+	Acknowledge: ActionAcknowledge,
+	Resolve:     ActionResolve,
+	Enum: format.CreateEnumFormatter(
+		[]string{
+			"Trigger",
+			"Acknowledge",
+			"Resolve",
+		}, map[string]int{
+			"ack": int(ActionAcknowledge),
+		}),
+}
+
+// Severities is the enum helper for the PagerDuty event severities.
+var Severities = &severityVals{
+	Critical: SeverityCritical,
+	Error:    SeverityError,
+	Warning:  SeverityWarning,
+	Info:     SeverityInfo,
+	Enum: format.CreateEnumFormatter(
+		[]string{
+			"Critical",
+			"Error",
+			"Warning",
+			"Info",
+		}, map[string]int{
+			"crit": int(SeverityCritical),
+			"err":  int(SeverityError),
+			"warn": int(SeverityWarning),
+		}),
+}
+
+type eventAction int
+
+type eventActionVals struct {
+	Trigger     eventAction
+	Acknowledge eventAction
+	Resolve     eventAction
+	Enum        types.EnumFormatter
+}
+
+func (a eventAction) String() string {
+	return EventActions.Enum.Print(int(a))
+}
+
+// apiValue returns the value used for the event_action field in the API payload.
+func (a eventAction) apiValue() string {
+	return strings.ToLower(a.String())
+}
+
+type severity int
+
+type severityVals struct {
+	Critical severity
+	Error    severity
+	Warning  severity
+	Info     severity
+	Enum     types.EnumFormatter
+}
+
+func (s severity) String() string {
+	return Severities.Enum.Print(int(s))
+}
+
+// apiValue returns the value used for the severity field in the API payload.
+func (s severity) apiValue() string {
+	return strings.ToLower(s.String())
+}
+
+// severityFromLevel maps a shoutrrr message level onto a PagerDuty severity.
+// The second return value is false if the level has no corresponding severity.
+func severityFromLevel(level types.MessageLevel) (severity, bool) {
+	switch level {
+	case types.Error:
+		return SeverityError, true
+	case types.Warning:
+		return SeverityWarning, true
+	case types.Info, types.Debug:
+		return SeverityInfo, true
+	case types.Unknown:
+		return 0, false
+	default:
+		return 0, false
+	}
+}
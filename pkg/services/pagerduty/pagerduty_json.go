@@ -0,0 +1,111 @@
+package pagerduty
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyLink indicates that a link or image prop did not contain a URL.
+var ErrEmptyLink = errors.New("link URL cannot be empty")
+
+// EventPayload is the request body sent to the PagerDuty Events API v2 enqueue endpoint.
+//
+// See: https://developer.pagerduty.com/docs/send-alert-event
+type EventPayload struct {
+	RoutingKey  string        `json:"routing_key"`
+	EventAction string        `json:"event_action"`
+	DedupKey    string        `json:"dedup_key,omitempty"`
+	Payload     *EventDetails `json:"payload,omitempty"`
+	Client      string        `json:"client,omitempty"`
+	ClientURL   string        `json:"client_url,omitempty"`
+	Links       []Link        `json:"links,omitempty"`
+	Images      []Image       `json:"images,omitempty"`
+}
+
+// EventDetails holds the incident information of a trigger event.
+type EventDetails struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// eventResponse is the body returned by the Events API, both on success and on failure.
+//
+//nolint:errname
+type eventResponse struct {
+	Status   string   `json:"status"`
+	Message  string   `json:"message"`
+	DedupKey string   `json:"dedup_key"`
+	Errors   []string `json:"errors"`
+}
+
+func (r *eventResponse) Error() string {
+	msg := fmt.Sprintf("server response: %v (%v)", r.Message, r.Status)
+	if len(r.Errors) > 0 {
+		return msg + ": " + strings.Join(r.Errors, ", ")
+	}
+
+	return msg
+}
+
+// Link is a link attached to an incident.
+type Link struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+// SetFromProp deserializes a link from a string in the format "href" or "href|text".
+func (l *Link) SetFromProp(propValue string) error {
+	href, text, _ := strings.Cut(propValue, "|")
+	if href == "" {
+		return fmt.Errorf("%w: %q", ErrEmptyLink, propValue)
+	}
+
+	l.Href = href
+	l.Text = text
+
+	return nil
+}
+
+// GetPropValue serializes a link back into a string in the format "href" or "href|text".
+func (l *Link) GetPropValue() (string, error) {
+	if l.Text == "" {
+		return l.Href, nil
+	}
+
+	return l.Href + "|" + l.Text, nil
+}
+
+// Image is an image attached to an incident.
+type Image struct {
+	Src string `json:"src"`
+	Alt string `json:"alt,omitempty"`
+}
+
+// SetFromProp deserializes an image from a string in the format "src" or "src|alt".
+func (i *Image) SetFromProp(propValue string) error {
+	src, alt, _ := strings.Cut(propValue, "|")
+	if src == "" {
+		return fmt.Errorf("%w: %q", ErrEmptyLink, propValue)
+	}
+
+	i.Src = src
+	i.Alt = alt
+
+	return nil
+}
+
+// GetPropValue serializes an image back into a string in the format "src" or "src|alt".
+func (i *Image) GetPropValue() (string, error) {
+	if i.Alt == "" {
+		return i.Src, nil
+	}
+
+	return i.Src + "|" + i.Alt, nil
+}
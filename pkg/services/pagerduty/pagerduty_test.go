@@ -0,0 +1,300 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+const (
+	mockIntegrationKey = "0123456789abcdef0123456789abcdef"
+	mockAPIURL         = "https://events.pagerduty.com/v2/enqueue"
+)
+
+func TestPagerDuty(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Shoutrrr PagerDuty Suite")
+}
+
+var logger = testutils.TestLogger()
+
+// respondCapturing registers a responder that stores the posted event payload.
+func respondCapturing(status int, response eventResponse, captured *EventPayload) {
+	jsonResponder := testutils.JSONRespondMust(status, response)
+	httpmock.RegisterResponder(http.MethodPost, mockAPIURL, func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(json.Unmarshal(body, captured)).To(gomega.Succeed())
+
+		return jsonResponder(req)
+	})
+}
+
+var _ = ginkgo.Describe("the PagerDuty service", func() {
+	var service *Service
+
+	ginkgo.BeforeEach(func() {
+		service = &Service{}
+		httpmock.Activate()
+	})
+
+	ginkgo.AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	ginkgo.It("should return the correct service ID", func() {
+		gomega.Expect(service.GetID()).To(gomega.Equal("pagerduty"))
+	})
+
+	ginkgo.When("sending a simple event", func() {
+		ginkgo.It("should trigger an incident with the default severity", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success", DedupKey: "abc"}, &payload)
+
+			gomega.Expect(service.Send("hello world", nil)).To(gomega.Succeed())
+			gomega.Expect(payload).To(gomega.Equal(EventPayload{
+				RoutingKey:  mockIntegrationKey,
+				EventAction: "trigger",
+				Payload: &EventDetails{
+					Summary:  "hello world",
+					Source:   "shoutrrr",
+					Severity: "error",
+				},
+			}))
+		})
+	})
+
+	ginkgo.When("sending a message longer than the summary", func() {
+		ginkgo.It("should truncate the summary without splitting characters", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			message := strings.Repeat("ü", MaxSummaryLength+1)
+			gomega.Expect(service.Send(message, nil)).To(gomega.Succeed())
+
+			gomega.Expect(utf8.ValidString(payload.Payload.Summary)).To(gomega.BeTrue())
+			gomega.Expect(payload.Payload.Summary).To(gomega.Equal(strings.Repeat("ü", MaxSummaryLength)))
+			gomega.Expect(payload.Payload.CustomDetails).To(gomega.HaveKeyWithValue("message", message))
+		})
+	})
+
+	ginkgo.When("sending an event with params", func() {
+		ginkgo.It("should map known params to fields and the rest to custom details", func() {
+			serviceURL := testutils.URLMust(
+				"pagerduty://events.pagerduty.com/" + mockIntegrationKey +
+					"?source=web01&component=nginx&details=env:prod&links=https://example.com|Runbook",
+			)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			gomega.Expect(service.Send("disk is full", &types.Params{
+				"title":    "Disk alert",
+				"severity": "warning",
+				"dedupkey": "disk-web01",
+				"images":   "https://example.com/graph.png|Graph",
+				"mount":    "/var",
+			})).To(gomega.Succeed())
+
+			gomega.Expect(payload.DedupKey).To(gomega.Equal("disk-web01"))
+			gomega.Expect(payload.Links).To(gomega.Equal([]Link{{Href: "https://example.com", Text: "Runbook"}}))
+			gomega.Expect(payload.Images).To(gomega.Equal([]Image{{Src: "https://example.com/graph.png", Alt: "Graph"}}))
+			gomega.Expect(*payload.Payload).To(gomega.Equal(EventDetails{
+				Summary:   "Disk alert",
+				Source:    "web01",
+				Severity:  "warning",
+				Component: "nginx",
+				CustomDetails: map[string]string{
+					"env":     "prod",
+					"mount":   "/var",
+					"message": "disk is full",
+				},
+			}))
+		})
+
+		ginkgo.It("should not keep params between sends", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			gomega.Expect(service.Send("1", &types.Params{"severity": "info"})).To(gomega.Succeed())
+			gomega.Expect(payload.Payload.Severity).To(gomega.Equal("info"))
+
+			gomega.Expect(service.Send("2", nil)).To(gomega.Succeed())
+			gomega.Expect(payload.Payload.Severity).To(gomega.Equal("error"))
+		})
+	})
+
+	ginkgo.When("sending message items", func() {
+		ginkgo.It("should map the highest item level to the severity", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			gomega.Expect(service.SendItems([]types.MessageItem{
+				{Text: "first", Level: types.Info},
+				{Text: "second", Level: types.Warning, Timestamp: timestamp},
+			}, nil)).To(gomega.Succeed())
+
+			gomega.Expect(payload.Payload.Summary).To(gomega.Equal("first\nsecond"))
+			gomega.Expect(payload.Payload.Severity).To(gomega.Equal("warning"))
+			gomega.Expect(payload.Payload.Timestamp).To(gomega.Equal("2024-01-02T03:04:05Z"))
+		})
+
+		ginkgo.It("should prefer an explicit severity param", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			gomega.Expect(service.SendItems([]types.MessageItem{
+				{Text: "boom", Level: types.Error},
+			}, &types.Params{"severity": "critical"})).To(gomega.Succeed())
+			gomega.Expect(payload.Payload.Severity).To(gomega.Equal("critical"))
+		})
+	})
+
+	ginkgo.When("acknowledging or resolving an incident", func() {
+		ginkgo.It("should send the action and dedup key without a payload", func() {
+			serviceURL := testutils.URLMust(
+				"pagerduty://events.pagerduty.com/" + mockIntegrationKey + "?action=resolve",
+			)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			payload := EventPayload{}
+			respondCapturing(http.StatusAccepted, eventResponse{Status: "success"}, &payload)
+
+			gomega.Expect(service.Send("resolved", &types.Params{"dedup": "disk-web01"})).
+				To(gomega.Succeed())
+			gomega.Expect(payload).To(gomega.Equal(EventPayload{
+				RoutingKey:  mockIntegrationKey,
+				EventAction: "resolve",
+				DedupKey:    "disk-web01",
+			}))
+		})
+
+		ginkgo.It("should return an error if the dedup key is missing", func() {
+			serviceURL := testutils.URLMust(
+				"pagerduty://events.pagerduty.com/" + mockIntegrationKey + "?action=ack",
+			)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+			gomega.Expect(service.Send("ack", nil)).To(gomega.MatchError(ErrDedupKeyRequired))
+		})
+	})
+
+	ginkgo.When("the server rejects the event", func() {
+		ginkgo.It("should return the error reported by the API", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			httpmock.RegisterResponder(http.MethodPost, mockAPIURL, testutils.JSONRespondMust(
+				http.StatusBadRequest,
+				eventResponse{
+					Status:  "invalid event",
+					Message: "Event object is invalid",
+					Errors:  []string{"Length of 'routing_key' is incorrect"},
+				},
+			))
+
+			err := service.Send("hello", nil)
+			gomega.Expect(err).To(gomega.HaveOccurred())
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("Length of 'routing_key' is incorrect"))
+		})
+	})
+
+	ginkgo.When("doing a dry run", func() {
+		ginkgo.It("should render the event request with the integration key redacted", func() {
+			serviceURL := testutils.URLMust("pagerduty://events.pagerduty.com/" + mockIntegrationKey)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("hello world", nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal(mockAPIURL))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"routing_key":"REDACTED"`))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"summary":"hello world"`))
+			gomega.Expect(requests[0].Body).NotTo(gomega.ContainSubstring(mockIntegrationKey))
+		})
+	})
+})
+
+var _ = ginkgo.Describe("the PagerDuty Config struct", func() {
+	ginkgo.When("parsing a URL without a host", func() {
+		ginkgo.It("should use the default host and port", func() {
+			config := &Config{}
+			gomega.Expect(config.SetURL(testutils.URLMust("pagerduty:///" + mockIntegrationKey))).
+				To(gomega.Succeed())
+			gomega.Expect(config.Host).To(gomega.Equal("events.pagerduty.com"))
+			gomega.Expect(config.Port).To(gomega.Equal(uint16(443)))
+			gomega.Expect(config.apiURL()).To(gomega.Equal(mockAPIURL))
+		})
+	})
+
+	ginkgo.When("parsing a URL without an integration key", func() {
+		ginkgo.It("should return an error", func() {
+			config := &Config{}
+			gomega.Expect(config.SetURL(testutils.URLMust("pagerduty://events.pagerduty.com"))).
+				To(gomega.MatchError(ErrIntegrationKeyMissing))
+		})
+	})
+
+	ginkgo.When("parsing a URL with a custom port", func() {
+		ginkgo.It("should include the port in the API URL", func() {
+			config := &Config{}
+			gomega.Expect(config.SetURL(testutils.URLMust("pagerduty://localhost:8443/" + mockIntegrationKey))).
+				To(gomega.Succeed())
+			gomega.Expect(config.apiURL()).To(gomega.Equal("https://localhost:8443/v2/enqueue"))
+		})
+	})
+
+	ginkgo.When("parsing the configuration URL", func() {
+		ginkgo.It("should be identical after de-/serialization", func() {
+			testURL := "pagerduty://events.pagerduty.com/" + mockIntegrationKey +
+				"?action=Trigger&class=disk&client=Monitor&clienturl=https%3A%2F%2Fmonitor.example.com" +
+				"&component=nginx&dedupkey=key&details=env%3Aprod&group=web" +
+				"&images=https%3A%2F%2Fexample.com%2Fa.png%7CGraph&links=https%3A%2F%2Fexample.com%7CRunbook" +
+				"&severity=Warning&source=web01&title=Alert"
+
+			config := &Config{}
+			pkr := format.NewPropKeyResolver(config)
+			gomega.Expect(config.setURL(&pkr, testutils.URLMust(testURL))).To(gomega.Succeed())
+			gomega.Expect(config.GetURL().String()).To(gomega.Equal(testURL))
+		})
+	})
+
+	ginkgo.Describe("the basic service API", func() {
+		ginkgo.It("should implement basic service config API methods correctly", func() {
+			testutils.TestConfigGetInvalidQueryValue(&Config{})
+			testutils.TestConfigSetInvalidQueryValue(&Config{}, "pagerduty://host/key?foo=bar")
+			testutils.TestConfigSetDefaultValues(&Config{})
+			testutils.TestConfigGetEnumsCount(&Config{}, 2)
+			testutils.TestConfigGetFieldsCount(&Config{}, 14)
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
@@ -72,6 +73,32 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for pushing the message to each target, without sending them.
+// The access token is redacted from the request headers.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	requests := make([]types.Request, 0, len(config.Targets))
+
+	for _, target := range config.Targets {
+		push := NewNotePush(message, config.Title)
+		push.SetTarget(target)
+
+		request, err := util.RedactedJSONRequest(pushesEndpoint, push, config.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		request.Headers.Set("Access-Token", util.RedactedValue)
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
 // doSend sends a push notification to a specific target and validates the response.
 func doSend(config *Config, target string, message string, client jsonclient.Client) error {
 	push := NewNotePush(message, config.Title)
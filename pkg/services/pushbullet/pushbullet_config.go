@@ -67,10 +67,8 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 	targets := strings.Split(path, "/")
 
 	token := url.Hostname()
-	if url.String() != "pushbullet://dummy@dummy.com" {
-		if err := validateToken(token); err != nil {
-			return err
-		}
+	if err := validateToken(token); err != nil {
+		return err
 	}
 
 	config.Token = token
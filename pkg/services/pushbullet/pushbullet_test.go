@@ -236,6 +236,19 @@ var _ = ginkgo.Describe("the pushbullet service", func() {
 			gomega.Expect(err.Error()).
 				To(gomega.ContainSubstring("push notification is not active"))
 		})
+		ginkgo.It("should render the request with the access token redacted when doing a dry run", func() {
+			err = initService()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			requests, err := service.DryRun("Message", nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal(targetURL))
+			gomega.Expect(requests[0].Headers.Get("Access-Token")).To(gomega.Equal("REDACTED"))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"device_iden":"test"`))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"body":"Message"`))
+		})
 	})
 })
 
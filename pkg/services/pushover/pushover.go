@@ -2,6 +2,7 @@ package pushover
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -13,17 +14,29 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // hookURL is the Pushover API endpoint for sending messages.
 const (
 	hookURL            = "https://api.pushover.net/1/messages.json"
+	validateURL        = "https://api.pushover.net/1/users/validate.json"
 	contentType        = "application/x-www-form-urlencoded"
 	defaultHTTPTimeout = 10 * time.Second // defaultHTTPTimeout is the default timeout for HTTP requests.
 )
 
 // ErrSendFailed indicates a failure in sending the notification to a Pushover device.
-var ErrSendFailed = errors.New("failed to send notification to pushover device")
+var (
+	ErrSendFailed     = errors.New("failed to send notification to pushover device")
+	ErrValidateFailed = errors.New("failed to validate pushover user")
+)
+
+// validateResponse is the response of the Pushover API for validating a user.
+type validateResponse struct {
+	Status  int      `json:"status"`
+	Devices []string `json:"devices"`
+	Errors  []string `json:"errors"`
+}
 
 // Service provides the Pushover notification service.
 type Service struct {
@@ -50,6 +63,34 @@ func (service *Service) Send(message string, params *types.Params) error {
 
 // sendToDevice sends a notification to a specific Pushover device.
 func (service *Service) sendToDevice(device string, message string, config *Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
+	defer cancel()
+
+	req, _, err := newDeviceRequest(ctx, device, message, config)
+	if err != nil {
+		return err
+	}
+
+	res, err := service.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request to Pushover API: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %q, response status %q", ErrSendFailed, device, res.Status)
+	}
+
+	return nil
+}
+
+// newDeviceRequest creates the HTTP request for sending the message to the device, together with its body.
+func newDeviceRequest(
+	ctx context.Context,
+	device string,
+	message string,
+	config *Config,
+) (*http.Request, string, error) {
 	data := url.Values{}
 	data.Set("device", device)
 	data.Set("user", config.User)
@@ -64,32 +105,81 @@ func (service *Service) sendToDevice(device string, message string, config *Conf
 		data.Set("priority", strconv.FormatInt(int64(config.Priority), 10))
 	}
 
+	body := data.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, strings.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	return req, body, nil
+}
+
+// DryRun renders the request used for sending the message to the devices, without sending it.
+// The application token and user key are redacted from the request body.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	device := strings.Join(config.Devices, ",")
+
+	req, body, err := newDeviceRequest(context.Background(), device, message, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{util.RedactedRequest(req, []byte(body), config.Token, config.User)}, nil
+}
+
+// Check validates the application token and user key using the Pushover API, without sending a message.
+func (service *Service) Check() (types.CheckResult, error) {
+	config := service.Config
+
+	data := url.Values{}
+	data.Set("token", config.Token)
+	data.Set("user", config.User)
+
+	if len(config.Devices) == 1 {
+		data.Set("device", config.Devices[0])
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(
-		ctx,
-		http.MethodPost,
-		hookURL,
-		strings.NewReader(data.Encode()),
-	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, validateURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return types.CheckResult{}, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", contentType)
 
 	res, err := service.Client.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending request to Pushover API: %w", err)
+		return types.CheckResult{}, fmt.Errorf("sending request to Pushover API: %w", err)
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %q, response status %q", ErrSendFailed, device, res.Status)
+	// The response only counts as reachable when it comes from the Pushover API
+	response := validateResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return types.CheckResult{}, fmt.Errorf("%w: response status %q", ErrValidateFailed, res.Status)
 	}
 
-	return nil
+	if response.Status != 1 {
+		err := fmt.Errorf("%w: %s", ErrValidateFailed, strings.Join(response.Errors, ", "))
+
+		return types.CheckResult{Reachable: true}, err
+	}
+
+	return types.CheckResult{
+		Reachable:     true,
+		Authenticated: true,
+		Identity:      "devices: " + strings.Join(response.Devices, ", "),
+	}, nil
 }
 
 // Initialize configures the service with a URL and logger.
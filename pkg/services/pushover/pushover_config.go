@@ -58,14 +58,12 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		}
 	}
 
-	if url.String() != "pushover://dummy@dummy.com" {
-		if len(config.User) < 1 {
-			return ErrUserMissing
-		}
+	if len(config.User) < 1 {
+		return ErrUserMissing
+	}
 
-		if len(config.Token) < 1 {
-			return ErrTokenMissing
-		}
+	if len(config.Token) < 1 {
+		return ErrTokenMissing
 	}
 
 	return nil
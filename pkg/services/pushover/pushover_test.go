@@ -16,7 +16,10 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/pushover"
 )
 
-const hookURL = "https://api.pushover.net/1/messages.json"
+const (
+	hookURL     = "https://api.pushover.net/1/messages.json"
+	validateURL = "https://api.pushover.net/1/users/validate.json"
+)
 
 func TestPushover(t *testing.T) {
 	gomega.RegisterFailHandler(ginkgo.Fail)
@@ -180,6 +183,49 @@ var _ = ginkgo.Describe("the pushover config", func() {
 			err = service.Send("Message", nil)
 			gomega.Expect(err).To(gomega.HaveOccurred())
 		})
+		ginkgo.It("should render the request with the token and user key redacted", func() {
+			serviceURL, err := url.Parse("pushover://:apptoken@usertoken/?devices=phone")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			err = service.Initialize(serviceURL, logger)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			requests, err := service.DryRun("Message", nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.Equal(hookURL))
+			gomega.Expect(requests[0].Body).
+				To(gomega.Equal("device=phone&message=Message&priority=0&token=REDACTED&user=REDACTED"))
+		})
+	})
+	ginkgo.Describe("checking the user", func() {
+		ginkgo.BeforeEach(func() {
+			httpmock.Activate()
+			gomega.Expect(service.Initialize(testutils.URLMust("pushover://:apptoken@usertoken"), logger)).
+				To(gomega.Succeed())
+		})
+		ginkgo.AfterEach(func() {
+			httpmock.DeactivateAndReset()
+		})
+		ginkgo.It("should report the devices of a valid user", func() {
+			httpmock.RegisterResponder("POST", validateURL, httpmock.NewStringResponder(200,
+				`{"status":1,"group":0,"devices":["phone","tablet"],"request":"1"}`))
+
+			result, err := service.Check()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(result.Authenticated).To(gomega.BeTrue())
+			gomega.Expect(result.Identity).To(gomega.Equal("devices: phone, tablet"))
+		})
+		ginkgo.It("should report the errors of an invalid user", func() {
+			httpmock.RegisterResponder("POST", validateURL, httpmock.NewStringResponder(400,
+				`{"user":"invalid","errors":["user key is invalid"],"status":0,"request":"1"}`))
+
+			result, err := service.Check()
+			gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("user key is invalid")))
+			gomega.Expect(result.Reachable).To(gomega.BeTrue())
+			gomega.Expect(result.Authenticated).To(gomega.BeFalse())
+		})
 	})
 })
 
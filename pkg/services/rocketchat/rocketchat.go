@@ -3,6 +3,7 @@ package rocketchat
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // defaultHTTPTimeout is the default timeout for HTTP requests.
@@ -92,6 +94,24 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return nil
 }
 
+// DryRun renders the request used for sending the message, without sending it.
+// The webhook tokens are redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := service.Config
+
+	payload, err := CreateJSONPayload(config, message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := util.RedactedJSONRequest(buildURL(config), json.RawMessage(payload), config.TokenA, config.TokenB)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
 // buildURL constructs the API URL for Rocket.Chat based on the Config.
 func buildURL(config *Config) string {
 	base := config.Host
@@ -42,14 +42,24 @@ func (config *Config) GetURL() *url.URL {
 		host = fmt.Sprintf("%s:%s", config.Host, config.Port)
 	}
 
-	url := &url.URL{
+	path := fmt.Sprintf("%s/%s", config.TokenA, config.TokenB)
+	if config.Channel != "" {
+		// Channels are written without their # prefix, which is added again when the URL is parsed
+		path += "/" + strings.TrimPrefix(config.Channel, "#")
+	}
+
+	serviceURL := &url.URL{
 		Host:       host,
-		Path:       fmt.Sprintf("%s/%s", config.TokenA, config.TokenB),
+		Path:       path,
 		Scheme:     Scheme,
 		ForceQuery: false,
 	}
 
-	return url
+	if config.UserName != "" {
+		serviceURL.User = url.User(config.UserName)
+	}
+
+	return serviceURL
 }
 
 // SetURL updates the Config from a URL representation of its field values.
@@ -58,10 +68,8 @@ func (config *Config) SetURL(serviceURL *url.URL) error {
 	host := serviceURL.Hostname()
 
 	path := strings.Split(serviceURL.Path, "/")
-	if serviceURL.String() != "rocketchat://dummy@dummy.com" {
-		if len(path) < MinPathParts {
-			return ErrNotEnoughArguments
-		}
+	if len(path) < MinPathParts {
+		return ErrNotEnoughArguments
 	}
 
 	config.Port = serviceURL.Port()
@@ -185,6 +185,22 @@ var _ = ginkgo.Describe("the rocketchat service", func() {
 				// but this ensures the params path is exercised.
 			})
 		})
+
+		ginkgo.When("doing a dry run", func() {
+			ginkgo.It("should render the request with the tokens redacted", func() {
+				service.Config.Host = "rocketchat.example.com"
+				service.Config.TokenA = testTokenA
+				service.Config.TokenB = testTokenB
+
+				requests, err := service.DryRun("test message", &types.Params{"channel": "#general"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).
+					To(gomega.Equal("https://rocketchat.example.com/hooks/REDACTED/REDACTED"))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"channel":"#general"`))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"text":"test message"`))
+			})
+		})
 	})
 
 	// Add tests for GetURL() and SetURL()
@@ -216,6 +232,19 @@ var _ = ginkgo.Describe("the rocketchat service", func() {
 			})
 		})
 
+		ginkgo.When("generating a URL from a config with a user and channel", func() {
+			ginkgo.It("should be identical after de-/serialization", func() {
+				for _, channel := range []string{"general", "@user"} {
+					testURL := "rocketchat://testUser@rocketchat.my-domain.com/" + testTokenA + "/" + testTokenB + "/" + channel
+					parsedURL, _ := url.Parse(testURL)
+
+					config := &Config{}
+					gomega.Expect(config.SetURL(parsedURL)).To(gomega.Succeed())
+					gomega.Expect(config.GetURL().String()).To(gomega.Equal(testURL))
+				}
+			})
+		})
+
 		ginkgo.When("setting URL with a channel starting with @", func() {
 			ginkgo.It("should set channel without adding #", func() {
 				config := &Config{}
@@ -29,6 +29,7 @@ var serviceURLs = map[string]string{
 	"logger":     "logger://",
 	"mattermost": "mattermost://user@example.com/token",
 	"opsgenie":   "opsgenie://example.com/token?responders=user:dummy",
+	"pagerduty":  "pagerduty://example.com/token",
 	"pushbullet": "pushbullet://tokentokentokentokentokentokentoke",
 	"pushover":   "pushover://:token@user/?devices=device",
 	"rocketchat": "rocketchat://example.com/token/channel",
@@ -50,6 +51,7 @@ var serviceResponses = map[string]string{
 	"logger":     "",
 	"mattermost": "",
 	"opsgenie":   "",
+	"pagerduty":  `{"status": "success"}`,
 	"pushbullet": `{"type": "note", "body": "test", "title": "test title", "active": true, "created": 0}`,
 	"pushover":   "",
 	"rocketchat": "",
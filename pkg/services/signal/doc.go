@@ -1,8 +1,8 @@
 // Package signal provides functionality to send notifications via Signal Messenger
 // through REST API servers that wrap the signal-cli command-line interface.
 //
-// This service supports sending text messages and base64-encoded attachments to
-// individual phone numbers and Signal groups. Authentication supports both HTTP
+// This service supports sending styled text messages, mentions and file attachments to
+// individual phone numbers and Signal groups, which can be specified by ID or by name. Authentication supports both HTTP
 // Basic Auth and Bearer tokens for compatibility with different API servers.
 //
 // It requires a Signal API server (such as signal-cli-rest-api or secured-signal-api)
@@ -10,10 +10,12 @@ import (
 	"net/url"
 	"strings"
 	"time"
+	"unicode/utf16"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // HTTP request timeout duration.
@@ -23,47 +25,117 @@ const (
 
 // ErrSendFailed indicates a failure to send a Signal message.
 var (
-	ErrSendFailed = errors.New("failed to send Signal message")
+	ErrSendFailed    = errors.New("failed to send Signal message")
+	ErrGroupNotFound = errors.New("group not found")
 )
 
+// mentionPlaceholder is prefixed to the message for each mention that is not present in the message text.
+const mentionPlaceholder = "@"
+
 // Service sends notifications to Signal recipients via signal-cli-rest-api.
 type Service struct {
 	standard.Standard
 	Config *Config
 	pkr    format.PropKeyResolver
+	groups map[string]string // groups caches the group IDs, keyed by the group name.
+}
+
+// RecipientResult is the delivery result of a message to a single recipient.
+type RecipientResult struct {
+	Recipient string
+	Timestamp int64 // Timestamp of the sent message, identifying it within Signal.
+	Err       error
 }
 
 // Send delivers a notification message to Signal recipients.
 func (service *Service) Send(message string, params *types.Params) error {
-	config := *service.Config
+	_, err := service.SendMessage(message, nil, params)
 
-	// Separate config params from message params (like attachments)
-	var (
-		configParams  *types.Params
-		messageParams *types.Params
-	)
+	return err
+}
 
-	if params != nil {
-		configParams = &types.Params{}
-		messageParams = &types.Params{}
-
-		for key, value := range *params {
-			// Check if this is a config parameter
-			if _, err := service.pkr.Get(key); err == nil {
-				// It's a valid config key
-				(*configParams)[key] = value
-			} else {
-				// It's a message parameter (like attachments)
-				(*messageParams)[key] = value
-			}
+// SendMessage delivers a notification message with the attachments to Signal recipients,
+// returning the delivery result for each recipient. The message is sent separately to each
+// recipient, so a failure for one recipient does not prevent delivery to the others.
+// Attachments that are closers are closed once the message has been sent, or has failed.
+func (service *Service) SendMessage(
+	message string,
+	attachments []Attachment,
+	params *types.Params,
+) ([]RecipientResult, error) {
+	defer closeAttachments(attachments)
+
+	config, messageParams, err := service.configFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.sendMessage(message, attachments, config, messageParams)
+}
+
+// DryRun renders the request used for sending the message to each recipient, without sending them.
+// The credentials are redacted from the Authorization headers. Groups are listed by their name,
+// as resolving their IDs requires a request to the API server.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config, messageParams, err := service.configFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := append(nonEmpty(config.Recipients), nonEmpty(config.Groups)...)
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	payload := service.createPayload(message, config, messageParams)
+	requests := make([]types.Request, 0, len(recipients))
+
+	for _, recipient := range recipients {
+		payload.Recipients = []string{recipient}
+
+		req, cancel, err := service.createRequest(config, payload)
+		if err != nil {
+			return nil, err
 		}
 
-		if err := service.pkr.UpdateConfigFromParams(&config, configParams); err != nil {
-			return fmt.Errorf("updating config from params: %w", err)
+		cancel()
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling payload to JSON: %w", err)
 		}
+
+		requests = append(requests, util.RedactedRequest(req, body, config.Token, config.Password))
 	}
 
-	return service.sendMessage(message, &config, messageParams)
+	return requests, nil
+}
+
+// configFromParams returns a copy of the config updated with the params matching a config key,
+// together with the remaining params, which are used for the message (like attachments).
+func (service *Service) configFromParams(params *types.Params) (*Config, *types.Params, error) {
+	config := *service.Config
+
+	if params == nil {
+		return &config, nil, nil
+	}
+
+	configParams := &types.Params{}
+	messageParams := &types.Params{}
+
+	for key, value := range *params {
+		if _, err := service.pkr.Get(key); err == nil {
+			(*configParams)[key] = value
+		} else {
+			(*messageParams)[key] = value
+		}
+	}
+
+	if err := service.pkr.UpdateConfigFromParams(&config, configParams); err != nil {
+		return nil, nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	return &config, messageParams, nil
 }
 
 // Initialize configures the service with a URL and logger.
@@ -84,23 +156,151 @@ func (service *Service) GetID() string {
 	return Scheme
 }
 
-// sendMessage sends a message to all configured recipients.
-func (service *Service) sendMessage(message string, config *Config, params *types.Params) error {
-	if len(config.Recipients) == 0 {
-		return ErrNoRecipients
+// sendMessage sends a message to all configured recipients and groups.
+func (service *Service) sendMessage(
+	message string,
+	attachments []Attachment,
+	config *Config,
+	params *types.Params,
+) ([]RecipientResult, error) {
+	recipients, err := service.resolveRecipients(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	encoded, err := encodeAttachments(attachments)
+	if err != nil {
+		return nil, err
 	}
 
 	payload := service.createPayload(message, config, params)
+	payload.Base64Attachments = append(payload.Base64Attachments, encoded...)
+
+	results := make([]RecipientResult, 0, len(recipients))
+
+	var failed []error
+
+	for _, recipient := range recipients {
+		payload.Recipients = []string{recipient}
 
+		result := RecipientResult{Recipient: recipient}
+		result.Timestamp, result.Err = service.sendPayload(config, payload)
+
+		if result.Err != nil {
+			service.Logf("Failed to send message to %s: %v", recipient, result.Err)
+			failed = append(failed, result.Err)
+		}
+
+		results = append(results, result)
+	}
+
+	if len(failed) > 0 {
+		return results, fmt.Errorf(
+			"sending to %d of %d recipients failed, with initial error: %w",
+			len(failed),
+			len(recipients),
+			failed[0],
+		)
+	}
+
+	return results, nil
+}
+
+// sendPayload sends the payload to the API server, returning the timestamp of the sent message.
+func (service *Service) sendPayload(config *Config, payload sendMessagePayload) (int64, error) {
 	req, cancel, err := service.createRequest(config, payload)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer cancel()
 
 	return service.sendRequest(req)
 }
 
+// resolveRecipients returns the configured recipients, followed by the IDs of the configured groups.
+func (service *Service) resolveRecipients(config *Config) ([]string, error) {
+	recipients := nonEmpty(config.Recipients)
+
+	for _, name := range nonEmpty(config.Groups) {
+		groupID, err := service.resolveGroup(config, name)
+		if err != nil {
+			return nil, err
+		}
+
+		recipients = append(recipients, groupID)
+	}
+
+	return recipients, nil
+}
+
+// resolveGroup returns the ID of the group with the given name. The groups of the source number
+// are fetched from the API server when the name is not cached, preferring an exact match of the name.
+func (service *Service) resolveGroup(config *Config, name string) (string, error) {
+	if groupID, found := service.groups[name]; found {
+		return groupID, nil
+	}
+
+	groups, err := service.getGroups(config)
+	if err != nil {
+		return "", err
+	}
+
+	service.groups = make(map[string]string, len(groups))
+	for _, group := range groups {
+		service.groups[group.Name] = group.ID
+	}
+
+	if groupID, found := service.groups[name]; found {
+		return groupID, nil
+	}
+
+	for _, group := range groups {
+		if strings.EqualFold(group.Name, name) {
+			return group.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrGroupNotFound, name)
+}
+
+// getGroups fetches the groups of the source number from the API server.
+func (service *Service) getGroups(config *Config) ([]groupEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
+	defer cancel()
+
+	apiURL := service.buildBaseURL(config) + "/v1/groups/" + url.PathEscape(config.Source)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+
+	service.setAuthentication(req, config)
+
+	client := &http.Client{}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching groups: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: fetching groups returned status %d", ErrSendFailed, resp.StatusCode)
+	}
+
+	var groups []groupEntry
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return nil, fmt.Errorf("parsing groups: %w", err)
+	}
+
+	return groups, nil
+}
+
 // createPayload builds the JSON payload for the Signal API request.
 func (service *Service) createPayload(
 	message string,
@@ -108,14 +308,18 @@ func (service *Service) createPayload(
 	params *types.Params,
 ) sendMessagePayload {
 	payload := sendMessagePayload{
-		Message:    message,
-		Number:     config.Source,
-		Recipients: config.Recipients,
+		Message: message,
+		Number:  config.Source,
+	}
+
+	if config.Styled {
+		payload.TextMode = "styled"
 	}
 
+	payload.Message, payload.Mentions = addMentions(message, nonEmpty(config.Mentions))
+
 	// Check for attachments in params (passed during Send call)
-	// Note: Shoutrrr doesn't have a standard attachment interface,
-	// so we check for "attachments" parameter with base64 data
+	// Raw attachments are passed as base64 data, or as data URIs including the content type and filename
 	if params != nil {
 		if attachments, ok := (*params)["attachments"]; ok && attachments != "" {
 			// Parse comma-separated base64 attachments
@@ -131,6 +335,54 @@ func (service *Service) createPayload(
 	return payload
 }
 
+// addMentions creates a mention for each user. Users that are referred to in the message as "@<user>"
+// are mentioned at that position, while a placeholder is prefixed to the message for all others.
+// Positions are counted in UTF-16 code units, as used by Signal.
+func addMentions(message string, users []string) (string, []mention) {
+	var prefixed []string
+
+	mentions := make([]mention, 0, len(users))
+
+	for _, user := range users {
+		if index := strings.Index(message, "@"+user); index >= 0 {
+			mentions = append(mentions, mention{
+				Author: user,
+				Start:  utf16Length(message[:index]),
+				Length: utf16Length("@" + user),
+			})
+
+			continue
+		}
+
+		prefixed = append(prefixed, user)
+	}
+
+	if len(prefixed) == 0 {
+		return message, mentions
+	}
+
+	// Placeholders are separated by spaces, and the positions of the existing mentions are shifted after them
+	prefix := strings.Repeat(mentionPlaceholder+" ", len(prefixed))
+	for i := range mentions {
+		mentions[i].Start += utf16Length(prefix)
+	}
+
+	for i, user := range prefixed {
+		mentions = append(mentions, mention{
+			Author: user,
+			Start:  i * utf16Length(mentionPlaceholder+" "),
+			Length: utf16Length(mentionPlaceholder),
+		})
+	}
+
+	return prefix + message, mentions
+}
+
+// utf16Length returns the length of the text in UTF-16 code units.
+func utf16Length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
 // createRequest builds the HTTP request for the Signal API.
 func (service *Service) createRequest(
 	config *Config,
@@ -160,12 +412,17 @@ func (service *Service) createRequest(
 
 // buildAPIURL constructs the Signal API endpoint URL.
 func (service *Service) buildAPIURL(config *Config) string {
+	return service.buildBaseURL(config) + "/v2/send"
+}
+
+// buildBaseURL constructs the base URL of the Signal API server.
+func (service *Service) buildBaseURL(config *Config) string {
 	scheme := "https"
 	if config.DisableTLS {
 		scheme = "http"
 	}
 
-	return fmt.Sprintf("%s://%s:%d/v2/send", scheme, config.Host, config.Port)
+	return fmt.Sprintf("%s://%s:%d", scheme, config.Host, config.Port)
 }
 
 // setAuthentication configures HTTP authentication headers.
@@ -178,33 +435,35 @@ func (service *Service) setAuthentication(req *http.Request, config *Config) {
 	}
 }
 
-// sendRequest executes the HTTP request and handles the response.
-func (service *Service) sendRequest(req *http.Request) error {
+// sendRequest executes the HTTP request and handles the response, returning the timestamp of the sent message.
+func (service *Service) sendRequest(req *http.Request) (int64, error) {
 	client := &http.Client{}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending HTTP request: %w", err)
+		return 0, fmt.Errorf("sending HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("%w: server returned status %d", ErrSendFailed, resp.StatusCode)
+		return 0, fmt.Errorf("%w: server returned status %d", ErrSendFailed, resp.StatusCode)
 	}
 
 	// Parse response (optional, for logging)
-	service.parseResponse(resp)
-
-	return nil
+	return service.parseResponse(resp), nil
 }
 
-// parseResponse extracts and logs response information.
-func (service *Service) parseResponse(resp *http.Response) {
+// parseResponse extracts and logs response information, returning the timestamp of the sent message.
+func (service *Service) parseResponse(resp *http.Response) int64 {
 	var response sendMessageResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		service.Logf("Warning: failed to parse response: %v", err)
-	} else {
-		service.Logf("Message sent successfully at timestamp %d", response.Timestamp)
+
+		return 0
 	}
+
+	service.Logf("Message sent successfully at timestamp %d", response.Timestamp)
+
+	return int64(response.Timestamp)
 }
@@ -0,0 +1,68 @@
+package signal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Attachment is a file that is sent together with the message.
+type Attachment struct {
+	Name   string
+	Reader io.Reader
+}
+
+// AttachmentFromPath creates an Attachment by opening the local file at the given path.
+// The file is read when the message is sent.
+func AttachmentFromPath(path string) (Attachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("opening attachment %q: %w", path, err)
+	}
+
+	return Attachment{Name: filepath.Base(path), Reader: file}, nil
+}
+
+// encodeAttachments reads the attachments and encodes them as data URIs.
+// Data URIs let the API server know the content type and the name of the file.
+func encodeAttachments(attachments []Attachment) ([]string, error) {
+	encoded := make([]string, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		data, err := io.ReadAll(attachment.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("reading attachment %q: %w", attachment.Name, err)
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(attachment.Name))
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+
+		// Parameters of the content type are dropped, since they would be mistaken for the filename
+		contentType, _, _ = strings.Cut(contentType, ";")
+
+		uri := "data:" + contentType
+		if attachment.Name != "" {
+			uri += ";filename=" + attachment.Name
+		}
+
+		encoded = append(encoded, uri+";base64,"+base64.StdEncoding.EncodeToString(data))
+	}
+
+	return encoded, nil
+}
+
+// closeAttachments closes any attachments that are closers, like the files opened by AttachmentFromPath.
+func closeAttachments(attachments []Attachment) {
+	for _, attachment := range attachments {
+		if closer, ok := attachment.Reader.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
+}
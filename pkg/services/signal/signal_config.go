@@ -15,11 +15,7 @@ import (
 )
 
 // Scheme identifies this service in configuration URLs.
-const (
-	Scheme = "signal"
-	// minPathParts is the minimum number of path parts required (source + at least one recipient).
-	minPathParts = 2
-)
+const Scheme = "signal"
 
 // phoneRegex validates phone number format (with or without + prefix).
 var phoneRegex = regexp.MustCompile(`^\+?[0-9\s)(+-]+$`)
@@ -38,14 +34,17 @@ var (
 // Config holds settings for the Signal notification service.
 type Config struct {
 	standard.EnumlessConfig
-	Host       string   `default:"localhost" desc:"Signal REST API server hostname or IP"      key:"host"`
-	Port       int      `default:"8080"      desc:"Signal REST API server port"                key:"port"`
-	User       string   `                    desc:"Username for HTTP Basic Auth"               key:"user"`
-	Password   string   `                    desc:"Password for HTTP Basic Auth"               key:"password"`
-	Token      string   `                    desc:"API token for Bearer authentication"        key:"token,apikey"`
-	Source     string   `                    desc:"Source phone number (with country code)"    key:"source"`
-	Recipients []string `                    desc:"Recipient phone numbers or group IDs"       key:"recipients,to"`
-	DisableTLS bool     `default:"No"        desc:"Disable TLS for Signal REST API connection" key:"disabletls"`
+	Host       string   `default:"localhost" desc:"Signal REST API server hostname or IP"                            key:"host"`
+	Port       int      `default:"8080"      desc:"Signal REST API server port"                                      key:"port"`
+	User       string   `                    desc:"Username for HTTP Basic Auth"                                     key:"user"`
+	Password   string   `                    desc:"Password for HTTP Basic Auth"                                     key:"password"`
+	Token      string   `                    desc:"API token for Bearer authentication"                              key:"token,apikey"`
+	Source     string   `                    desc:"Source phone number (with country code)"                          key:"source"`
+	Recipients []string `                    desc:"Recipient phone numbers or group IDs"                             key:"recipients,to"`
+	DisableTLS bool     `default:"No"        desc:"Disable TLS for Signal REST API connection"                       key:"disabletls"`
+	Styled     bool     `default:"No"        desc:"Format the message using text styles, like **bold** and *italic*" key:"styled"`
+	Mentions   []string `                    desc:"Phone numbers or UUIDs of users to mention"                       key:"mentions"`
+	Groups     []string `                    desc:"Names of groups to send to, which are resolved to group IDs"      key:"groups"`
 }
 
 // GetURL generates a URL from the current configuration values.
@@ -64,12 +63,10 @@ func (config *Config) SetURL(url *url.URL) error {
 
 // getURL constructs a URL from the Config's fields using the provided resolver.
 func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
-	recipients := strings.Join(config.Recipients, "/")
-
 	result := &url.URL{
 		Scheme:   Scheme,
 		Host:     fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Path:     fmt.Sprintf("/%s/%s", config.Source, recipients),
+		Path:     "/" + strings.Join(append([]string{config.Source}, config.Recipients...), "/"),
 		RawQuery: format.BuildQuery(resolver),
 	}
 
@@ -87,17 +84,6 @@ func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
 
 // setURL updates the Config from a URL using the provided resolver.
 func (config *Config) setURL(resolver types.ConfigQueryResolver, serviceURL *url.URL) error {
-	// Handle dummy URL used for documentation generation
-	if serviceURL.String() == "signal://dummy@dummy.com" {
-		config.Host = "localhost"
-		config.Port = 8080
-		config.Source = "+1234567890"
-		config.Recipients = []string{"+0987654321"}
-		config.DisableTLS = false
-
-		return nil
-	}
-
 	if err := config.parseAuth(serviceURL); err != nil {
 		return err
 	}
@@ -114,6 +100,11 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, serviceURL *url
 		return err
 	}
 
+	// Recipients can be omitted from the path when sending to groups by name
+	if len(config.Recipients) == 0 && len(nonEmpty(config.Groups)) == 0 {
+		return ErrNoRecipients
+	}
+
 	return nil
 }
 
@@ -152,7 +143,7 @@ func (config *Config) parseHostPort(serviceURL *url.URL) error {
 // parsePath extracts source phone number and recipients from the URL path.
 func (config *Config) parsePath(serviceURL *url.URL) error {
 	pathParts := strings.Split(strings.Trim(serviceURL.Path, "/"), "/")
-	if len(pathParts) < minPathParts {
+	if pathParts[0] == "" {
 		return ErrNoRecipients
 	}
 
@@ -195,3 +186,16 @@ func isValidPhoneNumber(phone string) bool {
 func isValidGroupID(groupID string) bool {
 	return groupRegex.MatchString(groupID)
 }
+
+// nonEmpty returns the trimmed values, skipping any empty entries.
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
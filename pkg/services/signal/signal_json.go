@@ -1,14 +1,51 @@
 package signal
 
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
 // sendMessagePayload represents the JSON payload for sending a Signal message.
 type sendMessagePayload struct {
-	Message           string   `json:"message"`
-	Number            string   `json:"number"`
-	Recipients        []string `json:"recipients"`
-	Base64Attachments []string `json:"base64_attachments,omitempty"`
+	Message           string    `json:"message"`
+	Number            string    `json:"number"`
+	Recipients        []string  `json:"recipients"`
+	Base64Attachments []string  `json:"base64_attachments,omitempty"`
+	TextMode          string    `json:"text_mode,omitempty"`
+	Mentions          []mention `json:"mentions,omitempty"`
+}
+
+// mention marks a range of the message text as a mention of a Signal user.
+type mention struct {
+	Author string `json:"author"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
 }
 
 // sendMessageResponse represents the response from the Signal REST API.
 type sendMessageResponse struct {
-	Timestamp int64 `json:"timestamp"`
+	Timestamp timestamp `json:"timestamp"`
+}
+
+// groupEntry represents a group returned by the groups endpoint of the Signal REST API.
+type groupEntry struct {
+	Name       string `json:"name"`
+	ID         string `json:"id"`
+	InternalID string `json:"internal_id"`
+}
+
+// timestamp is a message timestamp, which some API servers encode as a string.
+type timestamp int64
+
+// UnmarshalJSON decodes the timestamp from either a JSON number or a string containing a number.
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(string(bytes.Trim(data, `"`)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("parsing timestamp %s: %w", data, err)
+	}
+
+	*t = timestamp(value)
+
+	return nil
 }
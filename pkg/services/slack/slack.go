@@ -4,24 +4,33 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
 // apiPostMessage is the Slack API endpoint for sending messages.
 const (
 	apiPostMessage     = "https://slack.com/api/chat.postMessage"
+	apiUpdateMessage   = "https://slack.com/api/chat.update"
+	apiDeleteMessage   = "https://slack.com/api/chat.delete"
+	apiAuthTest        = "https://slack.com/api/auth.test"
 	defaultHTTPTimeout = 10 * time.Second // defaultHTTPTimeout is the default timeout for HTTP requests.
 )
 
+// FieldPrefixChar is the prefix for params that are added to the message as fields.
+const FieldPrefixChar = '$'
+
 // Service sends notifications to a pre-configured Slack channel or user.
 type Service struct {
 	standard.Standard
@@ -30,28 +39,162 @@ type Service struct {
 	client *http.Client
 }
 
+// MessageRef identifies a message that has been posted using the Slack API.
+type MessageRef struct {
+	Channel string // ID of the channel that the message was posted to
+	TS      string // ts value of the message, used for updates and thread replies
+}
+
 // Send delivers a notification message to Slack.
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendItems([]types.MessageItem{{Text: message}}, params)
+}
+
+// SendItems delivers the message items to Slack.
+// When blocks are enabled, each item is rendered with its fields, level and timestamp.
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	if _, err := service.post(items, params); err != nil {
+		return fmt.Errorf("failed to send slack notification: %w", err)
+	}
+
+	return nil
+}
+
+// PostMessage delivers a notification message using the Slack API, returning a reference to the posted message.
+// The reference can be used to update or delete the message, or passed as thread_ts to reply in its thread.
+func (service *Service) PostMessage(message string, params *types.Params) (MessageRef, error) {
+	return service.PostItems([]types.MessageItem{{Text: message}}, params)
+}
+
+// PostItems delivers the message items using the Slack API, returning a reference to the posted message.
+func (service *Service) PostItems(items []types.MessageItem, params *types.Params) (MessageRef, error) {
+	if !service.Config.Token.IsAPIToken() {
+		return MessageRef{}, ErrAPITokenRequired
+	}
+
+	ref, err := service.post(items, params)
+	if err != nil {
+		return MessageRef{}, fmt.Errorf("failed to post slack message: %w", err)
+	}
+
+	return ref, nil
+}
+
+// UpdateMessage replaces the content of a previously posted message using the Slack API.
+func (service *Service) UpdateMessage(ref MessageRef, message string, params *types.Params) error {
+	return service.UpdateItems(ref, []types.MessageItem{{Text: message}}, params)
+}
+
+// UpdateItems replaces the content of a previously posted message with the message items using the Slack API.
+func (service *Service) UpdateItems(ref MessageRef, items []types.MessageItem, params *types.Params) error {
+	if !service.Config.Token.IsAPIToken() {
+		return ErrAPITokenRequired
+	}
+
+	if ref.TS == "" {
+		return ErrMissingMessageTS
+	}
+
+	config, fields, err := service.configFromParams(params)
+	if err != nil {
+		return err
+	}
+
+	payload := createPayload(config, items, fields)
+	payload.TS = ref.TS
+	// Only the content of a message can be updated
+	payload.ThreadTS = ""
+	payload.BotName = ""
+	payload.SetIcon("")
+
+	if ref.Channel != "" {
+		payload.Channel = ref.Channel
+	}
+
+	if _, err := service.sendAPI(config, apiUpdateMessage, payload); err != nil {
+		return fmt.Errorf("failed to update slack message: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMessage removes a previously posted message using the Slack API.
+func (service *Service) DeleteMessage(ref MessageRef) error {
 	config := service.Config
+	if !config.Token.IsAPIToken() {
+		return ErrAPITokenRequired
+	}
+
+	if ref.TS == "" {
+		return ErrMissingMessageTS
+	}
+
+	payload := deletePayload{Channel: ref.Channel, TS: ref.TS}
+	if payload.Channel == "" {
+		payload.Channel = config.Channel
+	}
+
+	if _, err := service.sendAPI(config, apiDeleteMessage, payload); err != nil {
+		return fmt.Errorf("failed to delete slack message: %w", err)
+	}
+
+	return nil
+}
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
-		return fmt.Errorf("updating config from params: %w", err)
+// Check verifies the token using the auth.test endpoint of the Slack API, without posting a message.
+// Webhook tokens cannot be checked, since webhooks only accept posting messages.
+func (service *Service) Check() (types.CheckResult, error) {
+	if !service.Config.Token.IsAPIToken() {
+		return types.CheckResult{}, ErrAPITokenRequired
 	}
 
-	payload := CreateJSONPayload(config, message)
+	response, err := service.sendAPI(service.Config, apiAuthTest, struct{}{})
+	if err != nil {
+		reachable := errors.Is(err, ErrAPIResponseFailure) || errors.Is(err, ErrUnknownAPIError) ||
+			jsonclient.StatusCode(err) != 0
 
-	var err error
+		return types.CheckResult{Reachable: reachable}, fmt.Errorf("failed to check slack token: %w", err)
+	}
+
+	return types.CheckResult{
+		Reachable:     true,
+		Authenticated: true,
+		Identity:      fmt.Sprintf("%s (%s)", response.User, response.Team),
+	}, nil
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The token is redacted from the URL and headers of the request.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config, fields, err := service.configFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := json.Marshal(createPayload(config, []types.MessageItem{{Text: message}}, fields))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	endpoint := config.Token.WebhookURL()
 	if config.Token.IsAPIToken() {
-		err = service.sendAPI(config, payload)
-	} else {
-		err = service.sendWebhook(config, payload)
+		endpoint = apiPostMessage
 	}
 
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, endpoint, bytes.NewReader(payloadBytes))
 	if err != nil {
-		return fmt.Errorf("failed to send slack notification: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return nil
+	req.Header.Set("Content-Type", jsonclient.ContentType)
+
+	if config.Token.IsAPIToken() {
+		req.Header.Set("Authorization", config.Token.Authorization())
+	}
+
+	secrets := strings.Split(config.Token.String()[TypeIdentifierOffset:], "-")
+
+	return []types.Request{util.RedactedRequest(req, payloadBytes, secrets...)}, nil
 }
 
 // Initialize configures the service with a URL and logger.
@@ -71,29 +214,99 @@ func (service *Service) GetID() string {
 	return Scheme
 }
 
-// sendAPI sends a notification using the Slack API.
-func (service *Service) sendAPI(config *Config, payload any) error {
+// post sends the message items using either the Slack API or a webhook, depending on the token.
+// The returned reference is only populated when using the Slack API.
+func (service *Service) post(items []types.MessageItem, params *types.Params) (MessageRef, error) {
+	config, fields, err := service.configFromParams(params)
+	if err != nil {
+		return MessageRef{}, err
+	}
+
+	payload := createPayload(config, items, fields)
+
+	if !config.Token.IsAPIToken() {
+		return MessageRef{}, service.sendWebhook(config, payload)
+	}
+
+	response, err := service.sendAPI(config, apiPostMessage, payload)
+	if err != nil {
+		return MessageRef{}, err
+	}
+
+	return MessageRef{Channel: response.Channel, TS: response.TS}, nil
+}
+
+// configFromParams returns a copy of the config updated with the params, together with the params
+// prefixed by FieldPrefixChar, which are added to the message as fields.
+func (service *Service) configFromParams(params *types.Params) (*Config, map[string]string, error) {
+	configParams := types.Params{}
+	fields := map[string]string{}
+
+	if params != nil {
+		for key, value := range *params {
+			if name, isField := strings.CutPrefix(key, string(FieldPrefixChar)); isField {
+				fields[name] = value
+			} else {
+				configParams[key] = value
+			}
+		}
+	}
+
+	// Defensive copy
+	config := *service.Config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, &configParams); err != nil {
+		return nil, nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	return &config, fields, nil
+}
+
+// createPayload creates either a Block Kit or an attachments based message, depending on the config.
+func createPayload(config *Config, items []types.MessageItem, fields map[string]string) MessagePayload {
+	if config.Blocks {
+		return CreateBlocksPayload(config, items, fields)
+	}
+
+	payload := createAttachmentsPayload(config, strings.TrimSuffix(types.ItemsToPlain(items), "\n"))
+
+	if len(fields) > 0 {
+		if len(payload.Attachments) == 0 {
+			payload.Attachments = append(payload.Attachments, attachment{Color: config.Color})
+		}
+
+		last := &payload.Attachments[len(payload.Attachments)-1]
+		for _, field := range sortedFields(fields) {
+			last.Fields = append(last.Fields, legacyField{Title: field[0], Value: field[1], Short: true})
+		}
+	}
+
+	return payload
+}
+
+// sendAPI sends a request to the specified Slack API endpoint.
+func (service *Service) sendAPI(config *Config, endpoint string, payload any) (APIResponse, error) {
 	response := APIResponse{}
 	jsonClient := jsonclient.NewClient()
 	jsonClient.Headers().Set("Authorization", config.Token.Authorization())
 
-	if err := jsonClient.Post(apiPostMessage, payload, &response); err != nil {
-		return fmt.Errorf("posting to Slack API: %w", err)
+	if err := jsonClient.Post(endpoint, payload, &response); err != nil {
+		return response, fmt.Errorf("posting to Slack API: %w", err)
 	}
 
 	if !response.Ok {
 		if response.Error != "" {
-			return fmt.Errorf("%w: %v", ErrAPIResponseFailure, response.Error)
+			return response, fmt.Errorf("%w: %v", ErrAPIResponseFailure, response.Error)
 		}
 
-		return ErrUnknownAPIError
+		return response, ErrUnknownAPIError
 	}
 
 	if response.Warning != "" {
 		service.Logf("Slack API warning: %q", response.Warning)
 	}
 
-	return nil
+	return response, nil
 }
 
 // sendWebhook sends a notification using a Slack webhook.
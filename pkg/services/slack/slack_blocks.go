@@ -0,0 +1,264 @@
+package slack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// Constants for Slack Block Kit limits.
+const (
+	MaxBlocks        = 50   // Maximum number of blocks allowed in a single message
+	MaxSectionFields = 10   // Maximum number of fields allowed in a single section block
+	MaxActions       = 25   // Maximum number of elements allowed in a single actions block
+	maxHeaderLength  = 150  // Maximum length of the text in a header block
+	maxSectionLength = 3000 // Maximum length of the text in a section block
+	maxFieldLength   = 2000 // Maximum length of the text in a section field
+	maxButtonLength  = 75   // Maximum length of the text in a button element
+)
+
+// Block Kit text object types.
+const (
+	plainTextType = "plain_text"
+	markdownType  = "mrkdwn"
+)
+
+// LinkButton is a button that opens a URL when clicked.
+type LinkButton struct {
+	URL  string
+	Text string
+}
+
+// SetFromProp deserializes a link button from a string in the format "url" or "url|text".
+func (b *LinkButton) SetFromProp(propValue string) error {
+	target, text, _ := strings.Cut(propValue, "|")
+	if target == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidButtonFormat, propValue)
+	}
+
+	b.URL = target
+	b.Text = text
+
+	return nil
+}
+
+// GetPropValue serializes a link button back into a string in the format "url" or "url|text".
+func (b *LinkButton) GetPropValue() (string, error) {
+	if b.Text == "" {
+		return b.URL, nil
+	}
+
+	return b.URL + "|" + b.Text, nil
+}
+
+// CreateBlocksPayload creates a Block Kit message compatible with the slack post message API.
+// The title is rendered as a header, followed by a section for each message item with its fields
+// and a context line containing the level and timestamp. Any extra fields are added as a section
+// after the items, and the configured buttons are added as an actions block at the end.
+func CreateBlocksPayload(config *Config, items []types.MessageItem, fields map[string]string) MessagePayload {
+	blocks := make([]block, 0, len(items)+3)
+
+	if config.Title != "" {
+		blocks = append(blocks, block{
+			Type: "header",
+			Text: &blockText{Type: plainTextType, Text: truncate(config.Title, maxHeaderLength)},
+		})
+	}
+
+	for _, item := range items {
+		blocks = append(blocks, createItemBlocks(item)...)
+	}
+
+	if len(fields) > 0 {
+		blocks = append(blocks, createFieldSections(sortedFields(fields))...)
+	}
+
+	if len(config.Buttons) > 0 {
+		blocks = append(blocks, createActionsBlock(config.Buttons))
+	}
+
+	// When MaxBlocks have been reached, the remaining blocks are dropped
+	if len(blocks) > MaxBlocks {
+		blocks = blocks[:MaxBlocks]
+	}
+
+	// The text is used as the fallback in notifications when blocks are present
+	text := config.Title
+	if text == "" {
+		text = strings.TrimSuffix(types.ItemsToPlain(items), "\n")
+	}
+
+	payload := MessagePayload{
+		ThreadTS: config.ThreadTS,
+		Text:     text,
+		BotName:  config.BotName,
+		Blocks:   blocks,
+	}
+
+	payload.SetIcon(config.Icon)
+
+	if config.Channel != "webhook" {
+		payload.Channel = config.Channel
+	}
+
+	return payload
+}
+
+// createItemBlocks renders a single message item as one or more section blocks and a context block.
+func createItemBlocks(item types.MessageItem) []block {
+	blocks := make([]block, 0, 2)
+
+	for _, chunk := range splitText(item.Text, maxSectionLength) {
+		blocks = append(blocks, block{
+			Type: "section",
+			Text: &blockText{Type: markdownType, Text: chunk},
+		})
+	}
+
+	if len(item.Fields) > 0 {
+		fields := make([][2]string, 0, len(item.Fields))
+		for _, field := range item.Fields {
+			fields = append(fields, [2]string{field.Key, field.Value})
+		}
+
+		blocks = append(blocks, createFieldSections(fields)...)
+	}
+
+	var context []string
+	if item.Level != types.Unknown {
+		context = append(context, "*"+item.Level.String()+"*")
+	}
+
+	if !item.Timestamp.IsZero() {
+		context = append(context, formatDate(item.Timestamp))
+	}
+
+	if len(context) > 0 {
+		blocks = append(blocks, block{
+			Type:     "context",
+			Elements: []any{blockText{Type: markdownType, Text: strings.Join(context, " | ")}},
+		})
+	}
+
+	return blocks
+}
+
+// createFieldSections renders key-value pairs as section blocks, with at most MaxSectionFields in each.
+func createFieldSections(fields [][2]string) []block {
+	blocks := make([]block, 0, (len(fields)+MaxSectionFields-1)/MaxSectionFields)
+
+	for start := 0; start < len(fields); start += MaxSectionFields {
+		end := minInt(start+MaxSectionFields, len(fields))
+
+		texts := make([]blockText, 0, end-start)
+		for _, field := range fields[start:end] {
+			texts = append(texts, blockText{
+				Type: markdownType,
+				Text: truncate(fmt.Sprintf("*%s*\n%s", field[0], field[1]), maxFieldLength),
+			})
+		}
+
+		blocks = append(blocks, block{Type: "section", Fields: texts})
+	}
+
+	return blocks
+}
+
+// createActionsBlock renders the link buttons as an actions block.
+func createActionsBlock(buttons []LinkButton) block {
+	elements := make([]any, 0, minInt(len(buttons), MaxActions))
+
+	for i, button := range buttons {
+		if i >= MaxActions {
+			break
+		}
+
+		text := button.Text
+		if text == "" {
+			text = button.URL
+		}
+
+		elements = append(elements, blockButton{
+			Type:     "button",
+			Text:     blockText{Type: plainTextType, Text: truncate(text, maxButtonLength)},
+			URL:      button.URL,
+			ActionID: fmt.Sprintf("button-%d", i),
+		})
+	}
+
+	return block{Type: "actions", Elements: elements}
+}
+
+// sortedFields converts a map into a list of key-value pairs, sorted by key.
+func sortedFields(fields map[string]string) [][2]string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	result := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, [2]string{key, fields[key]})
+	}
+
+	return result
+}
+
+// formatDate returns a Slack date token that is displayed in the local time zone of the reader.
+func formatDate(timestamp time.Time) string {
+	return fmt.Sprintf(
+		"<!date^%d^{date_short_pretty} {time_secs}|%s>",
+		timestamp.Unix(),
+		timestamp.UTC().Format(time.RFC3339),
+	)
+}
+
+// splitText splits the text into chunks of at most limit characters, preferring to split on newlines.
+// Empty text results in no chunks.
+func splitText(text string, limit int) []string {
+	var chunks []string
+
+	for utf8.RuneCountInString(text) > limit {
+		chunk := truncateRunes(text, limit)
+		if i := strings.LastIndex(chunk, "\n"); i > 0 {
+			chunk = chunk[:i+1]
+		}
+
+		chunks = append(chunks, strings.TrimSuffix(chunk, "\n"))
+		text = text[len(chunk):]
+	}
+
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+
+	return chunks
+}
+
+// truncate shortens the text to at most limit characters, marking truncated text with an ellipsis.
+func truncate(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	return truncateRunes(text, limit-1) + "…"
+}
+
+// truncateRunes returns the first count runes of the text.
+func truncateRunes(text string, count int) string {
+	for i := range text {
+		if count == 0 {
+			return text[:i]
+		}
+
+		count--
+	}
+
+	return text
+}
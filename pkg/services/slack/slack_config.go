@@ -17,13 +17,15 @@ const (
 // Config for the slack service.
 type Config struct {
 	standard.EnumlessConfig
-	BotName  string `desc:"Bot name"                                                            key:"botname,username"         optional:"uses bot default"`
-	Icon     string `desc:"Use emoji or URL as icon (based on presence of http(s):// prefix)"   key:"icon,icon_emoji,icon_url" optional:""                     default:""`
-	Token    Token  `desc:"API Bot token"                                                                                                                                 url:"user,pass"`
-	Color    string `desc:"Message left-hand border color"                                      key:"color"                    optional:"default border color"`
-	Title    string `desc:"Prepended text above the message"                                    key:"title"                    optional:"omitted"`
-	Channel  string `desc:"Channel to send messages to in Cxxxxxxxxxx format"                                                                                             url:"host"`
-	ThreadTS string `desc:"ts value of the parent message (to send message as reply in thread)" key:"thread_ts"                optional:""`
+	BotName  string       `desc:"Bot name"                                                            key:"botname,username"         optional:"uses bot default"`
+	Icon     string       `desc:"Use emoji or URL as icon (based on presence of http(s):// prefix)"   key:"icon,icon_emoji,icon_url" optional:""                     default:""`
+	Token    Token        `desc:"API Bot token"                                                                                                                                 url:"user,pass"`
+	Color    string       `desc:"Message left-hand border color"                                      key:"color"                    optional:"default border color"`
+	Title    string       `desc:"Prepended text above the message"                                    key:"title"                    optional:"omitted"`
+	Channel  string       `desc:"Channel to send messages to in Cxxxxxxxxxx format"                                                                                             url:"host"`
+	ThreadTS string       `desc:"ts value of the parent message (to send message as reply in thread)" key:"thread_ts"                optional:""`
+	Blocks   bool         `desc:"Use Block Kit layout blocks instead of attachments"                  key:"blocks"                                                   default:"No"`
+	Buttons  []LinkButton `desc:"Link buttons below the message, in the format url|text"               key:"buttons"                  optional:""`
 }
 
 // GetURL returns a URL representation of it's current field values.
@@ -65,12 +67,8 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, serviceURL *url
 		config.Channel = serviceURL.Hostname()
 	}
 
-	if serviceURL.String() != "slack://dummy@dummy.com" {
-		if err = config.Token.SetFromProp(token); err != nil {
-			return err
-		}
-	} else {
-		config.Token.raw = token // Set raw token without validation
+	if err = config.Token.SetFromProp(token); err != nil {
+		return err
 	}
 
 	for key, vals := range serviceURL.Query() {
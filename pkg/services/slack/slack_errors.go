@@ -19,3 +19,12 @@ var ErrWebhookStatusFailure = errors.New("webhook status failure")
 
 // ErrWebhookResponseFailure indicates a failure in the webhook response content.
 var ErrWebhookResponseFailure = errors.New("webhook response failure")
+
+// ErrInvalidButtonFormat indicates that a button is missing its URL.
+var ErrInvalidButtonFormat = errors.New("invalid button format, expected url|text")
+
+// ErrAPITokenRequired indicates that the operation is only available when using an API token.
+var ErrAPITokenRequired = errors.New("an API token is required for this operation")
+
+// ErrMissingMessageTS indicates that no ts value was given for the message to update or delete.
+var ErrMissingMessageTS = errors.New("missing ts value of the message")
@@ -20,13 +20,16 @@ type MessagePayload struct {
 	Attachments []attachment `json:"attachments,omitempty"`
 	ThreadTS    string       `json:"thread_ts,omitempty"`
 	Channel     string       `json:"channel,omitempty"`
+	TS          string       `json:"ts,omitempty"`
 	IconEmoji   string       `json:"icon_emoji,omitempty"`
 	IconURL     string       `json:"icon_url,omitempty"`
 }
 
 type block struct {
-	Type string    `json:"type"`
-	Text blockText `json:"text"`
+	Type     string      `json:"type"`
+	Text     *blockText  `json:"text,omitempty"`
+	Fields   []blockText `json:"fields,omitempty"`
+	Elements []any       `json:"elements,omitempty"`
 }
 
 type blockText struct {
@@ -34,6 +37,13 @@ type blockText struct {
 	Text string `json:"text"`
 }
 
+type blockButton struct {
+	Type     string    `json:"type"`
+	Text     blockText `json:"text"`
+	URL      string    `json:"url"`
+	ActionID string    `json:"action_id"`
+}
+
 type attachment struct {
 	Title    string        `json:"title,omitempty"`
 	Fallback string        `json:"fallback,omitempty"`
@@ -55,13 +65,28 @@ type APIResponse struct {
 	Ok       bool   `json:"ok"`
 	Error    string `json:"error"`
 	Warning  string `json:"warning"`
+	Channel  string `json:"channel,omitempty"`
+	TS       string `json:"ts,omitempty"`
+	User     string `json:"user,omitempty"`
+	Team     string `json:"team,omitempty"`
 	MetaData struct {
 		Warnings []string `json:"warnings"`
 	} `json:"response_metadata"`
 }
 
+// deletePayload is the request body for deleting a message using the Slack API.
+type deletePayload struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
 // CreateJSONPayload compatible with the slack post message API.
 func CreateJSONPayload(config *Config, message string) any {
+	return createAttachmentsPayload(config, message)
+}
+
+// createAttachmentsPayload creates a message with an attachment for each line of the message.
+func createAttachmentsPayload(config *Config, message string) MessagePayload {
 	lines := strings.Split(message, "\n")
 	// Pre-allocate atts with a capacity of min(len(lines), MaxAttachments)
 	atts := make([]attachment, 0, minInt(len(lines), MaxAttachments))
@@ -1,24 +1,32 @@
 package smtp
 
 import (
+	"bytes"
 	"context"
-	"crypto/rand"
 	"crypto/tls"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"mime"
 	"net"
+	"net/mail"
 	"net/smtp"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/oauth2"
+
 	"github.com/nicholas-fedor/shoutrrr/internal/failures"
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/markdown"
 )
 
 const (
@@ -27,6 +35,7 @@ const (
 	contentMultipart = "multipart/alternative; boundary=%s"
 	DefaultSMTPPort  = 25 // DefaultSMTPPort is the standard port for SMTP communication.
 	boundaryByteLen  = 8  // boundaryByteLen is the number of bytes for the multipart boundary.
+	standardHeaders  = 8  // standardHeaders is the maximum number of headers set by the service itself.
 )
 
 // ErrNoAuth is a sentinel error indicating no authentication is required.
@@ -35,6 +44,7 @@ var ErrNoAuth = errors.New("no authentication required")
 // Static errors for SMTP operations.
 var (
 	ErrServerNoStartTLS = errors.New("server does not support StartTLS")
+	ErrDKIMIncomplete   = errors.New("DKIM signing requires a selector and a domain")
 )
 
 // Service sends notifications to given email addresses via SMTP.
@@ -44,6 +54,13 @@ type Service struct {
 	Config            *Config
 	multipartBoundary string
 	propKeyResolver   format.PropKeyResolver
+	session           *session
+	sessionMutex      sync.Mutex
+	dkim              *dkimSigner
+	smime             *smimeSigner
+	pgp               *pgpKey
+	token             *oauth2.Token
+	tokenMutex        sync.Mutex
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service.
@@ -66,15 +83,58 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 		return err
 	}
 
-	if service.Config.Auth == AuthTypes.Unknown {
-		if service.Config.Username != "" {
-			service.Config.Auth = AuthTypes.Plain
-		} else {
-			service.Config.Auth = AuthTypes.None
+	service.propKeyResolver = pkr
+
+	return service.loadSigners()
+}
+
+// loadSigners reads the keys for DKIM, S/MIME and PGP/MIME signing, if configured.
+func (service *Service) loadSigners() error {
+	config := service.Config
+	service.dkim = nil
+	service.smime = nil
+	service.pgp = nil
+
+	usesSMIME := config.SMIMECert != "" || config.SMIMEKey != "" || config.SMIMERecipients != ""
+	if usesSMIME && (config.PGPKey != "" || config.PGPRecipients != "") {
+		return ErrSMIMEAndPGPCombined
+	}
+
+	if config.DKIMKey != "" {
+		domain := config.DKIMDomain
+		if domain == "" {
+			_, domain, _ = strings.Cut(config.FromAddress, "@")
 		}
+
+		if config.DKIMSelector == "" || domain == "" {
+			return ErrDKIMIncomplete
+		}
+
+		signer, err := loadDKIMSigner(config.DKIMKey, config.DKIMSelector, domain)
+		if err != nil {
+			return fmt.Errorf("loading DKIM key: %w", err)
+		}
+
+		service.dkim = signer
 	}
 
-	service.propKeyResolver = pkr
+	if config.SMIMECert != "" || config.SMIMEKey != "" {
+		signer, err := loadSMIMESigner(config.SMIMECert, config.SMIMEKey)
+		if err != nil {
+			return fmt.Errorf("loading S/MIME certificate: %w", err)
+		}
+
+		service.smime = signer
+	}
+
+	if config.PGPKey != "" {
+		signer, err := loadPGPSigner(config.PGPKey)
+		if err != nil {
+			return fmt.Errorf("loading PGP key: %w", err)
+		}
+
+		service.pgp = signer
+	}
 
 	return nil
 }
@@ -85,23 +145,85 @@ func (service *Service) GetID() string {
 }
 
 // Send sends a notification message to email recipients.
+// When the Persistent option is enabled, the connection is kept open for subsequent sends until Close is called.
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.Config.Clone()
-	if err := service.propKeyResolver.UpdateConfigFromParams(&config, params); err != nil {
-		return fail(FailApplySendParams, err)
+	config, ferr := service.sessionConfig(params)
+	if ferr != nil {
+		return ferr
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 	defer cancel()
 
-	client, err := getClientConnection(ctx, service.Config)
-	if err != nil {
-		return fail(FailGetSMTPClient, err)
+	if config.Persistent {
+		if err := service.sendPersistent(ctx, message, &config); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	client, ferr := service.connect(ctx, &config)
+	if ferr != nil {
+		return ferr
 	}
 
 	return service.doSend(client, message, &config)
 }
 
+// Check connects to the SMTP server, performs the handshake and authenticates, without sending any mail.
+func (service *Service) Check() (types.CheckResult, error) {
+	config, ferr := service.sessionConfig(nil)
+	if ferr != nil {
+		return types.CheckResult{}, ferr
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	client, ferr := service.connect(ctx, &config)
+	if ferr != nil {
+		return types.CheckResult{Reachable: ferr.ID() != FailGetSMTPClient}, ferr
+	}
+
+	defer client.Close()
+
+	if err := client.Quit(); err != nil {
+		return types.CheckResult{Reachable: true, Authenticated: true}, fail(FailClosingSession, err)
+	}
+
+	return types.CheckResult{Reachable: true, Authenticated: true, Identity: config.Username}, nil
+}
+
+// sessionConfig returns a copy of the config updated with the params, as used for a session.
+func (service *Service) sessionConfig(params *types.Params) (Config, failure) {
+	config := service.Config.Clone()
+	if err := service.propKeyResolver.UpdateConfigFromParams(&config, params); err != nil {
+		return config, fail(FailApplySendParams, err)
+	}
+
+	config.FixEmailTags()
+
+	return config, nil
+}
+
+// connect establishes a connection to the SMTP server and starts a session on it.
+// The connection is closed if the session cannot be started.
+func (service *Service) connect(ctx context.Context, config *Config) (*smtp.Client, failure) {
+	client, err := getClientConnection(ctx, config)
+	if err != nil {
+		return nil, fail(FailGetSMTPClient, err)
+	}
+
+	if ferr := service.startSession(client, config); ferr != nil {
+		_ = client.Close()
+
+		return nil, ferr
+	}
+
+	return client, nil
+}
+
 // getClientConnection establishes a connection to the SMTP server using the provided configuration.
 func getClientConnection(ctx context.Context, config *Config) (*smtp.Client, error) {
 	var (
@@ -136,25 +258,26 @@ func getClientConnection(ctx context.Context, config *Config) (*smtp.Client, err
 	return client, nil
 }
 
-// doSend sends an email message using the provided SMTP client and configuration.
+// doSend sends an email message over the started session of the client, and closes the session.
 func (service *Service) doSend(client *smtp.Client, message string, config *Config) failure {
-	config.FixEmailTags()
+	errs := service.deliver(client, message, config)
 
+	// Send the QUIT command and close the connection.
+	if err := client.Quit(); err != nil {
+		errs = append(errs, fail(FailClosingSession, err))
+	}
+
+	return joinRecipientErrors(errs)
+}
+
+// startSession performs the handshake, enables StartTLS if configured and authenticates the client.
+func (service *Service) startSession(client *smtp.Client, config *Config) failure {
 	clientHost := service.resolveClientHost(config)
 
 	if err := client.Hello(clientHost); err != nil {
 		return fail(FailHandshake, err)
 	}
 
-	if config.UseHTML {
-		b := make([]byte, boundaryByteLen)
-		if _, err := rand.Read(b); err != nil {
-			return fail(FailUnknown, err) // Fallback error for rare case
-		}
-
-		service.multipartBoundary = hex.EncodeToString(b)
-	}
-
 	if config.UseStartTLS && !useImplicitTLS(config.Encryption, config.Port) {
 		if supported, _ := client.Extension("StartTLS"); !supported {
 			if config.RequireStartTLS {
@@ -183,24 +306,89 @@ func (service *Service) doSend(client *smtp.Client, message string, config *Conf
 		}
 	}
 
+	return nil
+}
+
+// deliver sends the message to the configured recipients, either in a single transaction when
+// Bulk is enabled, or in one transaction per recipient.
+func (service *Service) deliver(client *smtp.Client, message string, config *Config) []error {
+	if config.sendsHTML() {
+		boundary, err := randomBoundary()
+		if err != nil {
+			return []error{fail(FailUnknown, err)} // Fallback error for rare case
+		}
+
+		service.multipartBoundary = boundary
+	}
+
 	var errs []error
 
-	for _, toAddress := range config.ToAddresses {
-		if err := service.sendToRecipient(client, toAddress, config, message); err != nil {
-			errs = append(errs, fail(FailSendRecipient, err, toAddress))
-			service.Logf("Failed to send to %q: %v", toAddress, err)
+	for _, env := range getEnvelopes(config) {
+		toAddresses := strings.Join(env.to, ", ")
+		if err := service.sendToRecipients(client, env, config, message); err != nil {
+			errs = append(errs, fail(FailSendRecipient, err, toAddresses))
+			service.Logf("Failed to send to %q: %v", toAddresses, err)
 
 			continue
 		}
 
-		service.Logf("Mail successfully sent to %q!", toAddress)
+		service.Logf("Mail successfully sent to %q!", toAddresses)
 	}
 
-	// Send the QUIT command and close the connection.
-	if err := client.Quit(); err != nil {
-		errs = append(errs, fail(FailClosingSession, err))
+	return errs
+}
+
+// envelope is a single mail transaction, with the addresses shown in the To header
+// and the addresses the mail is actually delivered to.
+type envelope struct {
+	to         []string
+	recipients []string
+}
+
+// getEnvelopes returns the mail transactions needed to deliver the message to all recipients.
+// Without Bulk, every address in ToAddresses gets its own mail, and the Cc and Bcc addresses
+// share another mail, so that they only receive a single copy, even when there are no To addresses.
+// Encrypted mails can be decrypted by all of their recipients, so each Bcc address gets its own mail.
+func getEnvelopes(config *Config) []envelope {
+	toAddresses := nonEmpty(config.ToAddresses)
+	copies := nonEmpty(config.CcAddresses)
+	blindCopies := nonEmpty(config.BccAddresses)
+	envelopes := make([]envelope, 0, len(toAddresses)+len(blindCopies)+1)
+
+	if !config.encrypts() {
+		copies, blindCopies = slices.Concat(copies, blindCopies), nil
+	}
+
+	if config.Bulk {
+		if recipients := slices.Concat(toAddresses, copies); len(recipients) > 0 {
+			envelopes = append(envelopes, envelope{to: toAddresses, recipients: recipients})
+		}
+	} else {
+		for _, toAddress := range toAddresses {
+			envelopes = append(envelopes, envelope{to: []string{toAddress}, recipients: []string{toAddress}})
+		}
+
+		if len(copies) > 0 {
+			envelopes = append(envelopes, envelope{to: toAddresses, recipients: copies})
+		}
 	}
 
+	for _, blindCopy := range blindCopies {
+		envelopes = append(envelopes, envelope{to: toAddresses, recipients: []string{blindCopy}})
+	}
+
+	return envelopes
+}
+
+// nonEmpty returns the addresses, without any empty entries.
+func nonEmpty(addresses []string) []string {
+	return slices.DeleteFunc(slices.Clone(addresses), func(address string) bool {
+		return strings.TrimSpace(address) == ""
+	})
+}
+
+// joinRecipientErrors combines the errors from sending to the recipients into a single failure.
+func joinRecipientErrors(errs []error) failure {
 	if len(errs) > 0 {
 		return failures.Wrap(
 			"failed to send to some recipients",
@@ -240,7 +428,12 @@ func (service *Service) getAuth(config *Config) (smtp.Auth, failure) {
 	case AuthTypes.CRAMMD5:
 		return smtp.CRAMMD5Auth(config.Username, config.Password), nil
 	case AuthTypes.OAuth2:
-		return OAuth2Auth(config.Username, config.Password), nil
+		accessToken, err := service.getAccessToken(config)
+		if err != nil {
+			return nil, fail(FailOAuth2Token, err)
+		}
+
+		return OAuth2Auth(config.Username, accessToken), nil
 	case AuthTypes.Unknown:
 		return nil, fail(FailAuthType, nil, config.Auth.String())
 	default:
@@ -248,20 +441,28 @@ func (service *Service) getAuth(config *Config) (smtp.Auth, failure) {
 	}
 }
 
-// sendToRecipient sends an email to a single recipient using the provided SMTP client.
-func (service *Service) sendToRecipient(
+// sendToRecipients sends an email to the recipients of the envelope in a single transaction using the provided SMTP client.
+func (service *Service) sendToRecipients(
 	client *smtp.Client,
-	toAddress string,
+	env envelope,
 	config *Config,
 	message string,
 ) failure {
-	// Set the sender and recipient first
+	// The mail is composed first, so that signing failures do not leave an open transaction
+	data, ferr := service.composeMail(env, config, message)
+	if ferr != nil {
+		return ferr
+	}
+
+	// Set the sender and recipients first
 	if err := client.Mail(config.FromAddress); err != nil {
 		return fail(FailSetSender, err)
 	}
 
-	if err := client.Rcpt(toAddress); err != nil {
-		return fail(FailSetRecipient, err)
+	for _, recipient := range env.recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fail(FailSetRecipient, err)
+		}
 	}
 
 	// Send the email body.
@@ -270,94 +471,208 @@ func (service *Service) sendToRecipient(
 		return fail(FailOpenDataStream, err)
 	}
 
-	if err := writeHeaders(writeCloser, service.getHeaders(toAddress, config.Subject)); err != nil {
-		return err
+	if _, err := writeCloser.Write(data); err != nil {
+		return fail(FailMessageRaw, err)
 	}
 
+	if err = writeCloser.Close(); err != nil {
+		return fail(FailCloseDataStream, err)
+	}
+
+	return nil
+}
+
+// composeMail creates the complete mail for the envelope. The content is S/MIME signed and encrypted
+// when configured, after which the DKIM signature is calculated over the resulting mail.
+func (service *Service) composeMail(env envelope, config *Config, message string) ([]byte, failure) {
+	body := &bytes.Buffer{}
+	contentType := contentPlain
+
 	var ferr failure
-	if config.UseHTML {
-		ferr = service.writeMultipartMessage(writeCloser, message)
+	if config.sendsHTML() {
+		contentType = fmt.Sprintf(contentMultipart, service.multipartBoundary)
+		ferr = service.writeMultipartMessage(body, message, config)
 	} else {
-		ferr = service.writeMessagePart(writeCloser, message, "plain")
+		ferr = service.writeMessagePart(body, message, "plain", message)
 	}
 
 	if ferr != nil {
-		return ferr
+		return nil, ferr
 	}
 
-	if err = writeCloser.Close(); err != nil {
-		return fail(FailCloseDataStream, err)
+	entity, ferr := service.secureEntity(
+		mimeEntity{headers: map[string]string{"Content-Type": contentType}, body: body.Bytes()},
+		env,
+		config,
+	)
+	if ferr != nil {
+		return nil, ferr
 	}
 
-	return nil
+	headers := service.getHeaders(env, config)
+	maps.Copy(headers, entity.headers)
+
+	if service.dkim != nil {
+		signature, err := service.dkim.sign(headers, entity.body, time.Now())
+		if err != nil {
+			return nil, fail(FailSignMessage, err)
+		}
+
+		headers["DKIM-Signature"] = signature
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := writeHeaders(buffer, headers); err != nil {
+		return nil, err
+	}
+
+	buffer.Write(entity.body)
+
+	return buffer.Bytes(), nil
+}
+
+// secureEntity signs the entity with the S/MIME certificate or the PGP key,
+// and encrypts it for the recipients of the envelope.
+func (service *Service) secureEntity(entity mimeEntity, env envelope, config *Config) (mimeEntity, failure) {
+	if service.smime != nil || service.pgp != nil {
+		boundary, err := randomBoundary()
+		if err != nil {
+			return entity, fail(FailSignMessage, err)
+		}
+
+		if service.smime != nil {
+			entity, err = service.smime.sign(entity, boundary)
+		} else {
+			entity, err = service.pgp.sign(entity, boundary)
+		}
+
+		if err != nil {
+			return entity, fail(FailSignMessage, err)
+		}
+	}
+
+	var err error
+
+	switch {
+	case config.SMIMERecipients != "":
+		entity, err = encryptSMIME(entity, env, config)
+	case config.PGPRecipients != "":
+		entity, err = encryptPGP(entity, env, config)
+	}
+
+	if err != nil {
+		return entity, fail(FailEncryptMessage, err)
+	}
+
+	return entity, nil
+}
+
+// encryptSMIME encrypts the entity for the S/MIME certificates of the recipients of the envelope.
+func encryptSMIME(entity mimeEntity, env envelope, config *Config) (mimeEntity, error) {
+	certificates, err := loadRecipientCertificates(config.SMIMERecipients, env.recipients)
+	if err != nil {
+		return entity, err
+	}
+
+	return encryptEntity(entity, certificates)
+}
+
+// encryptPGP encrypts the entity for the OpenPGP keys of the recipients of the envelope.
+func encryptPGP(entity mimeEntity, env envelope, config *Config) (mimeEntity, error) {
+	keys, err := loadRecipientPGPKeys(config.PGPRecipients, env.recipients)
+	if err != nil {
+		return entity, err
+	}
+
+	boundary, err := randomBoundary()
+	if err != nil {
+		return entity, err
+	}
+
+	return encryptPGPEntity(entity, keys, boundary)
 }
 
 // getHeaders constructs email headers for the SMTP message.
-func (service *Service) getHeaders(toAddress string, subject string) map[string]string {
-	conf := service.Config
+// Custom headers are added first, so that they cannot replace the ones needed to deliver the message.
+func (service *Service) getHeaders(env envelope, config *Config) map[string]string {
+	headers := make(map[string]string, len(config.headers)+standardHeaders)
+	for key, value := range config.headers {
+		headers[key] = mime.QEncoding.Encode("UTF-8", value)
+	}
 
-	var contentType string
-	if conf.UseHTML {
-		contentType = fmt.Sprintf(contentMultipart, service.multipartBoundary)
-	} else {
-		contentType = contentPlain
+	headers["Subject"] = mime.QEncoding.Encode("UTF-8", config.Subject)
+	headers["Date"] = time.Now().Format(time.RFC1123Z)
+	headers["From"] = (&mail.Address{Name: config.FromName, Address: config.FromAddress}).String()
+	headers["MIME-version"] = "1.0"
+
+	if len(env.to) > 0 {
+		headers["To"] = strings.Join(env.to, ", ")
+	}
+
+	if ccAddresses := nonEmpty(config.CcAddresses); len(ccAddresses) > 0 {
+		headers["Cc"] = strings.Join(ccAddresses, ", ")
 	}
 
-	return map[string]string{
-		"Subject":      subject,
-		"Date":         time.Now().Format(time.RFC1123Z),
-		"To":           toAddress,
-		"From":         fmt.Sprintf("%s <%s>", conf.FromName, conf.FromAddress),
-		"MIME-version": "1.0",
-		"Content-Type": contentType,
+	if config.ReplyTo != "" {
+		headers["Reply-To"] = (&mail.Address{Address: config.ReplyTo}).String()
 	}
+
+	return headers
 }
 
 // writeMultipartMessage writes a multipart email message to the provided writer.
-func (service *Service) writeMultipartMessage(writeCloser io.WriteCloser, message string) failure {
-	if err := writeMultipartHeader(writeCloser, service.multipartBoundary, contentPlain); err != nil {
+// Unless templates are used, the plain text part is generated from the HTML message,
+// or when UseMarkdown is enabled, the HTML part is rendered from the markdown message.
+func (service *Service) writeMultipartMessage(
+	writer io.Writer,
+	message string,
+	config *Config,
+) failure {
+	plainText, htmlText := htmlToText(message), message
+	if config.UseMarkdown {
+		plainText, htmlText = message, markdown.ToHTML(message)
+	}
+
+	if err := writeMultipartHeader(writer, service.multipartBoundary, contentPlain); err != nil {
 		return fail(FailPlainHeader, err)
 	}
 
-	if err := service.writeMessagePart(writeCloser, message, "plain"); err != nil {
+	if err := service.writeMessagePart(writer, message, "plain", plainText); err != nil {
 		return err
 	}
 
-	if err := writeMultipartHeader(writeCloser, service.multipartBoundary, contentHTML); err != nil {
+	if err := writeMultipartHeader(writer, service.multipartBoundary, contentHTML); err != nil {
 		return fail(FailHTMLHeader, err)
 	}
 
-	if err := service.writeMessagePart(writeCloser, message, "HTML"); err != nil {
+	if err := service.writeMessagePart(writer, message, "HTML", htmlText); err != nil {
 		return err
 	}
 
-	if err := writeMultipartHeader(writeCloser, service.multipartBoundary, ""); err != nil {
+	if err := writeMultipartHeader(writer, service.multipartBoundary, ""); err != nil {
 		return fail(FailMultiEndHeader, err)
 	}
 
 	return nil
 }
 
-// writeMessagePart writes a single part of an email message using the specified template.
+// writeMessagePart writes a single part of an email message using the specified template,
+// or the given content if there is no such template.
 func (service *Service) writeMessagePart(
-	writeCloser io.WriteCloser,
+	writer io.Writer,
 	message string,
 	template string,
+	content string,
 ) failure {
 	if tpl, found := service.GetTemplate(template); found {
 		data := make(map[string]string)
 
 		data["message"] = message
-		if err := tpl.Execute(writeCloser, data); err != nil {
+		if err := tpl.Execute(writer, data); err != nil {
 			return fail(FailMessageTemplate, err)
 		}
 	} else {
-		content := message
-		if template == "HTML" {
-			content = fmt.Sprintf("<pre>%s</pre>", message)
-		}
-
-		if _, err := fmt.Fprint(writeCloser, content); err != nil {
+		if _, err := fmt.Fprint(writer, content); err != nil {
 			return fail(FailMessageRaw, err)
 		}
 	}
@@ -366,18 +681,18 @@ func (service *Service) writeMessagePart(
 }
 
 // writeMultipartHeader writes a multipart boundary header to the provided writer.
-func writeMultipartHeader(writeCloser io.WriteCloser, boundary string, contentType string) error {
+func writeMultipartHeader(writer io.Writer, boundary string, contentType string) error {
 	suffix := "\n"
 	if len(contentType) < 1 {
 		suffix = "--"
 	}
 
-	if _, err := fmt.Fprintf(writeCloser, "\n\n--%s%s", boundary, suffix); err != nil {
+	if _, err := fmt.Fprintf(writer, "\n\n--%s%s", boundary, suffix); err != nil {
 		return fmt.Errorf("writing multipart boundary: %w", err)
 	}
 
 	if len(contentType) > 0 {
-		if _, err := fmt.Fprintf(writeCloser, "Content-Type: %s\n\n", contentType); err != nil {
+		if _, err := fmt.Fprintf(writer, "Content-Type: %s\n\n", contentType); err != nil {
 			return fmt.Errorf("writing content type header: %w", err)
 		}
 	}
@@ -386,14 +701,14 @@ func writeMultipartHeader(writeCloser io.WriteCloser, boundary string, contentTy
 }
 
 // writeHeaders writes email headers to the provided writer.
-func writeHeaders(writeCloser io.WriteCloser, headers map[string]string) failure {
+func writeHeaders(writer io.Writer, headers map[string]string) failure {
 	for key, val := range headers {
-		if _, err := fmt.Fprintf(writeCloser, "%s: %s\n", key, val); err != nil {
+		if _, err := fmt.Fprintf(writer, "%s: %s\n", key, val); err != nil {
 			return fail(FailWriteHeaders, err)
 		}
 	}
 
-	_, err := fmt.Fprintln(writeCloser)
+	_, err := fmt.Fprintln(writer)
 	if err != nil {
 		return fail(FailWriteHeaders, err)
 	}
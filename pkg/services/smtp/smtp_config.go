@@ -3,10 +3,14 @@ package smtp
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"net/textproto"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
@@ -16,10 +20,14 @@ import (
 // Scheme is the identifying part of this service's configuration URL.
 const Scheme = "smtp"
 
+// headerPrefixChar marks query keys that are added as headers to the sent mail.
+const headerPrefixChar = '@'
+
 // Static errors for configuration validation.
 var (
 	ErrFromAddressMissing = errors.New("fromAddress missing from config URL")
 	ErrToAddressMissing   = errors.New("toAddress missing from config URL")
+	ErrInvalidHeaderName  = errors.New("invalid header name")
 )
 
 // Config is the configuration needed to send e-mail notifications over SMTP.
@@ -39,6 +47,28 @@ type Config struct {
 	ClientHost      string        `desc:"SMTP client hostname"                                              default:"localhost"             key:"clienthost"`
 	RequireStartTLS bool          `desc:"Fail if StartTLS is enabled but unsupported"                       default:"No"                    key:"requirestarttls"`
 	Timeout         time.Duration `desc:"Timeout for SMTP operations"                                       default:"10s"                   key:"timeout"`
+	Bulk            bool          `desc:"Send one mail to all recipients instead of one mail per recipient" default:"No"                    key:"bulk"`
+	Persistent      bool          `desc:"Reuse the connection between sends until the service is closed"    default:"No"                    key:"persistent"`
+	KeepAlive       time.Duration `desc:"Interval of NOOP commands keeping a persistent connection alive"   default:"0s"                    key:"keepalive"`
+	CcAddresses     []string      `desc:"List of e-mails that receive a carbon copy"                                                        key:"cc"                   optional:"yes"`
+	BccAddresses    []string      `desc:"List of e-mails that receive a blind carbon copy"                                                  key:"bcc"                  optional:"yes"`
+	ReplyTo         string        `desc:"E-mail address that replies should be sent to"                                                     key:"replyto"              optional:"yes"`
+	UseMarkdown     bool          `desc:"Whether the message is in markdown, which is rendered as HTML"     default:"No"                    key:"usemarkdown,markdown"`
+	DKIMKey         string        `desc:"Path to the private key used for DKIM signing"                                                     key:"dkimkey"              optional:"yes"`
+	DKIMSelector    string        `desc:"Selector of the DNS record with the DKIM public key"                                               key:"dkimselector"         optional:"yes"`
+	DKIMDomain      string        `desc:"Domain of the DKIM signature, defaults to the sender domain"                                       key:"dkimdomain"           optional:"yes"`
+	SMIMECert       string        `desc:"Path to the certificate used for S/MIME signing"                                                   key:"smimecert"            optional:"yes"`
+	SMIMEKey        string        `desc:"Path to the private key of the S/MIME certificate"                                                 key:"smimekey"             optional:"yes"`
+	SMIMERecipients string        `desc:"Directory with the recipient certificates for S/MIME encryption"                                   key:"smimerecipients"      optional:"yes"`
+	PGPKey          string        `desc:"Path to the unprotected OpenPGP secret key used for PGP/MIME signing"                              key:"pgpkey"               optional:"yes"`
+	PGPRecipients   string        `desc:"Directory with the recipient public keys for PGP/MIME encryption"                                  key:"pgprecipients"        optional:"yes"`
+	ClientID        string        `desc:"OAuth2 client ID used for refreshing access tokens"                                                key:"clientid"             optional:"yes"`
+	ClientSecret    string        `desc:"OAuth2 client secret used for refreshing access tokens"                                            key:"clientsecret"         optional:"yes"`
+	TokenURL        string        `desc:"OAuth2 token endpoint, enables refreshing access tokens"                                           key:"tokenurl"             optional:"yes"`
+	RefreshToken    string        `desc:"OAuth2 refresh token, without it client credentials are used"                                      key:"refreshtoken"         optional:"yes"`
+	TokenFile       string        `desc:"Path to a JSON file with the OAuth2 token, also used as cache"                                     key:"tokenfile"            optional:"yes"`
+	Scopes          []string      `desc:"OAuth2 scopes requested when refreshing access tokens"                                             key:"scopes"               optional:"yes"`
+	headers         map[string]string
 }
 
 // GetURL returns a URL representation of its current field values.
@@ -101,6 +131,45 @@ func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
 		queryParts = append(queryParts, "requirestarttls=Yes")
 	}
 
+	// The optional recipients and formatting options are likewise only included when set
+	for _, key := range []string{
+		"cc", "bcc", "replyto",
+		"dkimkey", "dkimselector", "dkimdomain",
+		"smimecert", "smimekey", "smimerecipients",
+		"pgpkey", "pgprecipients",
+		"clientid", "clientsecret", "tokenurl", "refreshtoken", "tokenfile", "scopes",
+	} {
+		if value, err := resolver.Get(key); err == nil && value != "" {
+			queryParts = append(queryParts, fmt.Sprintf("%s=%s", key, url.QueryEscape(value)))
+		}
+	}
+
+	if config.UseMarkdown {
+		queryParts = append(queryParts, "usemarkdown=Yes")
+	}
+
+	if config.Bulk {
+		queryParts = append(queryParts, "bulk=Yes")
+	}
+
+	if config.Persistent {
+		queryParts = append(queryParts, "persistent=Yes")
+	}
+
+	if config.KeepAlive > 0 {
+		queryParts = append(
+			queryParts,
+			"keepalive="+url.QueryEscape(config.KeepAlive.String()),
+		)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(config.headers)) {
+		queryParts = append(
+			queryParts,
+			fmt.Sprintf("%c%s=%s", headerPrefixChar, key, url.QueryEscape(config.headers[key])),
+		)
+	}
+
 	configURL.RawQuery = strings.Join(queryParts, "&")
 
 	return configURL
@@ -117,14 +186,28 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		config.Port = uint16(port)
 	}
 
+	config.headers = nil
+
 	for key, vals := range url.Query() {
-		if key == "timeout" {
+		if strings.HasPrefix(key, string(headerPrefixChar)) {
+			if err := config.SetHeader(key[1:], vals[0]); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if key == "timeout" || key == "keepalive" {
 			duration, err := time.ParseDuration(vals[0])
 			if err != nil {
-				return fmt.Errorf("parsing timeout parameter %q: %w", vals[0], err)
+				return fmt.Errorf("parsing %s parameter %q: %w", key, vals[0], err)
 			}
 
-			config.Timeout = duration
+			if key == "timeout" {
+				config.Timeout = duration
+			} else {
+				config.KeepAlive = duration
+			}
 
 			continue
 		}
@@ -134,13 +217,21 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		}
 	}
 
-	if url.String() != "smtp://dummy@dummy.com" {
-		if len(config.FromAddress) < 1 {
-			return ErrFromAddressMissing
-		}
+	if len(config.FromAddress) < 1 {
+		return ErrFromAddressMissing
+	}
+
+	// Mails can be sent to Cc or Bcc addresses only
+	if len(config.ToAddresses) < 1 && len(config.CcAddresses) < 1 && len(config.BccAddresses) < 1 {
+		return ErrToAddressMissing
+	}
 
-		if len(config.ToAddresses) < 1 {
-			return ErrToAddressMissing
+	// Use plain authentication when credentials are given, unless a method is set explicitly
+	if config.Auth == AuthTypes.Unknown {
+		if config.Username != "" {
+			config.Auth = AuthTypes.Plain
+		} else {
+			config.Auth = AuthTypes.None
 		}
 	}
 
@@ -150,18 +241,96 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 // Clone returns a copy of the config.
 func (config *Config) Clone() Config {
 	clone := *config
-	clone.ToAddresses = make([]string, len(config.ToAddresses))
-	copy(clone.ToAddresses, config.ToAddresses)
+	clone.ToAddresses = slices.Clone(config.ToAddresses)
+	clone.CcAddresses = slices.Clone(config.CcAddresses)
+	clone.BccAddresses = slices.Clone(config.BccAddresses)
+	clone.Scopes = slices.Clone(config.Scopes)
+	clone.headers = maps.Clone(config.headers)
 
 	return clone
 }
 
+// sendsHTML reports whether the message is sent as multipart, with both a plain text and an HTML part.
+func (config *Config) sendsHTML() bool {
+	return config.UseHTML || config.UseMarkdown
+}
+
+// Headers returns the custom headers that are added to the sent mail.
+func (config *Config) Headers() map[string]string {
+	return config.headers
+}
+
+// SetHeader adds a custom header to the sent mail, or removes it if the value is empty.
+// Keys in camel case are converted to dashed header names, e.g. listUnsubscribe becomes List-Unsubscribe.
+// Names that are not valid header field names, like ones containing a colon or line breaks, are rejected.
+func (config *Config) SetHeader(key string, value string) error {
+	key = headerKey(key)
+
+	if !validHeaderName(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidHeaderName, key)
+	}
+
+	if value == "" {
+		delete(config.headers, key)
+
+		return nil
+	}
+
+	if config.headers == nil {
+		config.headers = make(map[string]string)
+	}
+
+	config.headers[key] = value
+
+	return nil
+}
+
+// encrypts reports whether the mails are encrypted for their recipients.
+func (config *Config) encrypts() bool {
+	return config.SMIMERecipients != "" || config.PGPRecipients != ""
+}
+
 // FixEmailTags replaces parsed spaces (+) in e-mail addresses with '+'.
 func (config *Config) FixEmailTags() {
 	config.FromAddress = strings.ReplaceAll(config.FromAddress, " ", "+")
-	for i, adr := range config.ToAddresses {
-		config.ToAddresses[i] = strings.ReplaceAll(adr, " ", "+")
+	config.ReplyTo = strings.ReplaceAll(config.ReplyTo, " ", "+")
+
+	for _, addresses := range [][]string{config.ToAddresses, config.CcAddresses, config.BccAddresses} {
+		for i, adr := range addresses {
+			addresses[i] = strings.ReplaceAll(adr, " ", "+")
+		}
+	}
+}
+
+// headerKey converts a query key to a canonical header name, inserting dashes before upper case letters.
+func headerKey(key string) string {
+	builder := strings.Builder{}
+
+	for i, c := range key {
+		if i > 0 && unicode.IsUpper(c) && key[i-1] != '-' {
+			builder.WriteRune('-')
+		}
+
+		builder.WriteRune(c)
 	}
+
+	return textproto.CanonicalMIMEHeaderKey(builder.String())
+}
+
+// validHeaderName reports whether the name is a valid header field name, which RFC 5322 limits to
+// printable ASCII characters other than the colon.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := range len(name) {
+		if name[i] < '!' || name[i] > '~' || name[i] == ':' {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Enums returns the fields that should use a corresponding EnumFormatter to Print/Parse their values.
@@ -0,0 +1,113 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	dkimSpaces         = regexp.MustCompile(`[ \t]+`)
+	dkimTrailingSpaces = regexp.MustCompile(`[ \t]+\r\n`)
+)
+
+// dkimSigner creates DKIM signatures as described in RFC 6376, using relaxed canonicalization.
+type dkimSigner struct {
+	domain   string
+	selector string
+	key      crypto.Signer
+}
+
+// loadDKIMSigner reads the RSA or Ed25519 private key used for signing from the PEM file.
+func loadDKIMSigner(keyPath string, selector string, domain string) (*dkimSigner, error) {
+	key, err := loadPrivateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+	default:
+		return nil, fmt.Errorf("DKIM key %q: %w", keyPath, ErrUnsupportedKey)
+	}
+
+	return &dkimSigner{domain: domain, selector: selector, key: key}, nil
+}
+
+// sign returns the value of the DKIM-Signature header for a mail with the given headers and body.
+// All of the headers are included in the signature.
+func (signer *dkimSigner) sign(headers map[string]string, body []byte, now time.Time) (string, error) {
+	bodyHash := sha256.Sum256(dkimCanonicalBody(body))
+
+	algorithm := "rsa-sha256"
+	if _, isEd25519 := signer.key.(ed25519.PrivateKey); isEd25519 {
+		algorithm = "ed25519-sha256"
+	}
+
+	names := slices.Sorted(maps.Keys(headers))
+
+	value := fmt.Sprintf(
+		"v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%s; h=%s; bh=%s; b=",
+		algorithm,
+		signer.domain,
+		signer.selector,
+		strconv.FormatInt(now.Unix(), 10),
+		strings.ToLower(strings.Join(names, ":")),
+		base64.StdEncoding.EncodeToString(bodyHash[:]),
+	)
+
+	signed := &bytes.Buffer{}
+	for _, name := range names {
+		signed.WriteString(dkimCanonicalHeader(name, headers[name]) + "\r\n")
+	}
+
+	// The signature header itself is included last, with an empty signature and without the line break
+	signed.WriteString(dkimCanonicalHeader("DKIM-Signature", value))
+
+	digest := sha256.Sum256(signed.Bytes())
+
+	// Ed25519 signs the digest as its message, while RSA signs the digest with PKCS #1 v1.5
+	var opts crypto.SignerOpts = crypto.SHA256
+	if algorithm == "ed25519-sha256" {
+		opts = crypto.Hash(0)
+	}
+
+	signature, err := signer.key.Sign(rand.Reader, digest[:], opts)
+	if err != nil {
+		return "", fmt.Errorf("creating DKIM signature: %w", err)
+	}
+
+	return value + base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// dkimCanonicalHeader returns the header in the relaxed canonical form.
+func dkimCanonicalHeader(name string, value string) string {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "\r\n", ""), "\n", "")
+
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(dkimSpaces.ReplaceAllString(value, " "))
+}
+
+// dkimCanonicalBody returns the body in the relaxed canonical form.
+func dkimCanonicalBody(body []byte) []byte {
+	canonical := dkimSpaces.ReplaceAll(toCRLF(body), []byte(" "))
+	canonical = dkimTrailingSpaces.ReplaceAll(canonical, []byte("\r\n"))
+
+	// Trailing empty lines are ignored, and an empty body stays empty
+	canonical = bytes.TrimRight(canonical, " \t\r\n")
+	if len(canonical) == 0 {
+		return canonical
+	}
+
+	return append(canonical, '\r', '\n')
+}
@@ -47,6 +47,12 @@ const (
 	FailApplySendParams
 	// FailHandshake is returned when the initial HELLO handshake returned an error.
 	FailHandshake
+	// FailSignMessage is returned when the message could not be signed with DKIM, S/MIME or PGP/MIME.
+	FailSignMessage
+	// FailEncryptMessage is returned when the message could not be encrypted with S/MIME or PGP/MIME.
+	FailEncryptMessage
+	// FailOAuth2Token is returned when no valid OAuth2 access token could be obtained.
+	FailOAuth2Token
 )
 
 // failure is an interface for SMTP-specific errors, implementing failures.Failure
@@ -101,6 +107,12 @@ func fail(failureID failures.FailureID, err error, args ...any) failure {
 		msg = "error applying params to send config"
 	case FailHandshake:
 		msg = "server did not accept the handshake"
+	case FailSignMessage:
+		msg = "error signing message"
+	case FailEncryptMessage:
+		msg = "error encrypting message"
+	case FailOAuth2Token:
+		msg = "error getting OAuth2 access token"
 	// case FailUnknown:
 	default:
 		msg = "an unknown error occurred"
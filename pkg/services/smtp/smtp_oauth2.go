@@ -1,7 +1,33 @@
 package smtp
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	tokenCacheFileMode = 0o600
+	tokenCacheDirMode  = 0o700
+	tokenHashLength    = 8 // tokenHashLength is the number of hash bytes used in the token cache file name.
+)
+
+// Static errors for OAuth2 token handling.
+var (
+	ErrTokenExpired     = errors.New("the OAuth2 access token has expired and cannot be refreshed without a token URL")
+	ErrNoTokenGrant     = errors.New("a refresh token or client ID is needed to obtain an OAuth2 access token")
+	ErrInvalidTokenFile = errors.New("invalid OAuth2 token file")
 )
 
 type oauth2Auth struct {
@@ -11,7 +37,7 @@ type oauth2Auth struct {
 // OAuth2Auth returns an Auth that implements the SASL XOAUTH2 authentication
 // as per https://developers.google.com/gmail/imap/xoauth2-protocol.
 // It assumes the provided password is a valid OAuth2 access token.
-// Token refresh or complex challenge-response flows are not supported.
+// Tokens are refreshed by the service before the authentication is created.
 func OAuth2Auth(username, accessToken string) smtp.Auth {
 	return &oauth2Auth{username, accessToken}
 }
@@ -25,3 +51,157 @@ func (a *oauth2Auth) Start(_ *smtp.ServerInfo) (string, []byte, error) {
 func (a *oauth2Auth) Next(_ []byte, _ bool) ([]byte, error) {
 	return nil, nil
 }
+
+// getAccessToken returns the access token used for the OAuth2 authentication.
+// Unless the password is the access token itself, a token is obtained from the token URL whenever
+// the current one has expired, and cached on disk to be reused by later runs.
+func (service *Service) getAccessToken(config *Config) (string, error) {
+	if config.TokenURL == "" && config.TokenFile == "" {
+		return config.Password, nil
+	}
+
+	service.tokenMutex.Lock()
+	defer service.tokenMutex.Unlock()
+
+	cachePath, err := config.tokenCachePath()
+	if err != nil {
+		return "", err
+	}
+
+	if service.token == nil {
+		if service.token, err = loadToken(cachePath); err != nil {
+			return "", err
+		}
+	}
+
+	if service.token != nil && service.token.Valid() {
+		return service.token.AccessToken, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	source, err := config.tokenSource(ctx, service.token)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		return "", fmt.Errorf("refreshing OAuth2 access token: %w", err)
+	}
+
+	service.token = token
+
+	// A failure to cache the token only means that it has to be refreshed again on the next run
+	if err := saveToken(cachePath, token); err != nil {
+		service.Logf("Failed to cache the OAuth2 access token: %v", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// tokenSource returns the source for a new access token. Refresh tokens in the cached token take
+// precedence over the configured one, since providers may replace them when refreshing.
+// Without any refresh token, the client credentials are used to request a token instead.
+func (config *Config) tokenSource(ctx context.Context, cached *oauth2.Token) (oauth2.TokenSource, error) {
+	if config.TokenURL == "" {
+		return nil, ErrTokenExpired
+	}
+
+	scopes := nonEmpty(config.Scopes)
+
+	refreshToken := config.RefreshToken
+	if cached != nil && cached.RefreshToken != "" {
+		refreshToken = cached.RefreshToken
+	}
+
+	if refreshToken != "" {
+		oauthConfig := &oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				TokenURL:  config.TokenURL,
+				AuthStyle: oauth2.AuthStyleAutoDetect,
+			},
+			Scopes: scopes,
+		}
+
+		return oauthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), nil
+	}
+
+	if config.ClientID == "" {
+		return nil, ErrNoTokenGrant
+	}
+
+	credentials := &clientcredentials.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		TokenURL:     config.TokenURL,
+		Scopes:       scopes,
+	}
+
+	return credentials.TokenSource(ctx), nil
+}
+
+// tokenCachePath returns the path of the file that the access token is cached in. Unless a token file
+// is specified, the token is kept in the user cache directory, in a file named after a hash of the credentials.
+func (config *Config) tokenCachePath() (string, error) {
+	if config.TokenFile != "" {
+		return config.TokenFile, nil
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("getting cache directory: %w", err)
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(
+		[]string{config.TokenURL, config.ClientID, config.Username, config.RefreshToken},
+		"\x00",
+	)))
+
+	return filepath.Join(cacheDir, "shoutrrr", "smtp", hex.EncodeToString(hash[:tokenHashLength])+".json"), nil
+}
+
+// loadToken reads the token from the file, returning nil if it does not exist.
+func loadToken(path string) (*oauth2.Token, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil //nolint:nilnil // A missing file means that there is no cached token yet
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading OAuth2 token file: %w", err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalidTokenFile, path, err)
+	}
+
+	return token, nil
+}
+
+// saveToken writes the token to the file, replacing the previous file atomically.
+func saveToken(path string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("marshaling OAuth2 token: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), tokenCacheDirMode); err != nil {
+		return fmt.Errorf("creating OAuth2 token directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, tokenCacheFileMode); err != nil {
+		return fmt.Errorf("writing OAuth2 token file: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("replacing OAuth2 token file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,868 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // OpenPGP v4 fingerprints and the modification detection code are defined using SHA-1
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OpenPGP packet tags, as defined in RFC 4880.
+const (
+	pgpTagEncryptedSessionKey = 1
+	pgpTagSignature           = 2
+	pgpTagSecretKey           = 5
+	pgpTagPublicKey           = 6
+	pgpTagLiteralData         = 11
+	pgpTagPublicSubkey        = 14
+	pgpTagEncryptedData       = 18 // pgpTagEncryptedData is the tag of integrity protected encrypted data.
+	pgpTagModificationCode    = 19
+)
+
+// OpenPGP algorithm identifiers.
+const (
+	pgpAlgoRSA        = 1
+	pgpAlgoRSAEncrypt = 2
+	pgpAlgoRSASign    = 3
+	pgpAlgoECDH       = 18
+	pgpAlgoEdDSA      = 22
+	pgpHashSHA256     = 8
+	pgpHashSHA384     = 9
+	pgpHashSHA512     = 10
+	pgpCipherAES128   = 7
+	pgpCipherAES192   = 8
+	pgpCipherAES256   = 9
+)
+
+const (
+	pgpKeyVersion       = 4
+	pgpSessionKeyLength = 32 // pgpSessionKeyLength is the length of the AES-256 session key.
+	pgpPointPrefix      = 0x40
+	pgpCurve25519Length = 32
+	pgpKeyWrapBlock     = 8
+	pgpSignatureTime    = 2  // pgpSignatureTime is the signature creation time subpacket.
+	pgpSignatureIssuer  = 16 // pgpSignatureIssuer is the issuer key ID subpacket.
+	pgpIssuerPrint      = 33 // pgpIssuerPrint is the issuer fingerprint subpacket.
+	crc24Init           = 0xB704CE
+	crc24Poly           = 0x1864CFB
+)
+
+const pgpSignedContentType = `multipart/signed; micalg=pgp-sha256; protocol="application/pgp-signature"; boundary=%s`
+
+const pgpEncryptedContentType = `multipart/encrypted; protocol="application/pgp-encrypted"; boundary=%s`
+
+// Object identifiers of the curves of OpenPGP ECC keys, without the DER tag and length.
+var (
+	oidPGPEd25519    = []byte{0x2B, 0x06, 0x01, 0x04, 0x01, 0xDA, 0x47, 0x0F, 0x01}
+	oidPGPCurve25519 = []byte{0x2B, 0x06, 0x01, 0x04, 0x01, 0x97, 0x55, 0x01, 0x05, 0x01}
+)
+
+// Static errors for PGP/MIME operations.
+var (
+	ErrInvalidPGPData      = errors.New("invalid OpenPGP data")
+	ErrPGPKeyProtected     = errors.New("passphrase protected OpenPGP keys are not supported")
+	ErrNoPGPEncryptionKey  = errors.New("no supported OpenPGP encryption key found")
+	ErrNoRecipientPGPKey   = errors.New("no OpenPGP key found for recipients")
+	ErrSMIMEAndPGPCombined = errors.New("S/MIME and PGP/MIME cannot be used together")
+)
+
+// pgpPacket is an OpenPGP packet, consisting of its tag and body.
+type pgpPacket struct {
+	tag  byte
+	body []byte
+}
+
+// pgpKey is an OpenPGP v4 key, including the private key of secret keys.
+type pgpKey struct {
+	algorithm byte
+	publicKey []byte // publicKey is the body of the public key packet, which identifies the key.
+	rsa       *rsa.PublicKey
+	curve     []byte
+	point     []byte
+	kdfHash   byte
+	kdfCipher byte
+	signer    crypto.Signer
+}
+
+// loadPGPSigner reads the unprotected RSA or Ed25519 secret key used for signing from the, optionally armored, file.
+func loadPGPSigner(path string) (*pgpKey, error) {
+	packets, err := readPGPFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, packet := range packets {
+		if packet.tag != pgpTagSecretKey {
+			continue
+		}
+
+		key, err := parsePGPSecretKey(packet.body)
+		if err != nil {
+			return nil, fmt.Errorf("PGP key %q: %w", path, err)
+		}
+
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: no secret key found in %q", ErrInvalidPGPData, path)
+}
+
+// loadRecipientPGPKeys reads the public keys of the recipients from the directory, where they are
+// stored as <address>.asc. It fails with ErrNoRecipientPGPKey, naming the recipients without a key,
+// so that the mail is never sent unencrypted.
+func loadRecipientPGPKeys(dir string, recipients []string) ([]*pgpKey, error) {
+	keys := make([]*pgpKey, 0, len(recipients))
+
+	var missing []string
+
+	for _, recipient := range recipients {
+		path := filepath.Join(dir, strings.ToLower(recipient)+".asc")
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, recipient)
+
+			continue
+		}
+
+		key, err := loadPGPEncryptionKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecipientPGPKey, strings.Join(missing, ", "))
+	}
+
+	return keys, nil
+}
+
+// loadPGPEncryptionKey reads the public key from the file, returning its first RSA or Curve25519 encryption subkey.
+// RSA primary keys are used when there is no such subkey.
+func loadPGPEncryptionKey(path string) (*pgpKey, error) {
+	packets, err := readPGPFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var primary *pgpKey
+
+	for _, packet := range packets {
+		if packet.tag != pgpTagPublicKey && packet.tag != pgpTagPublicSubkey {
+			continue
+		}
+
+		key, _, err := parsePGPPublicKey(packet.body)
+		if err != nil {
+			return nil, fmt.Errorf("PGP key %q: %w", path, err)
+		}
+
+		if packet.tag == pgpTagPublicKey {
+			primary = key
+
+			continue
+		}
+
+		if key.canEncrypt() {
+			return key, nil
+		}
+	}
+
+	if primary != nil && primary.rsa != nil && primary.algorithm != pgpAlgoRSASign {
+		return primary, nil
+	}
+
+	return nil, fmt.Errorf("%w in %q", ErrNoPGPEncryptionKey, path)
+}
+
+// readPGPFile reads the packets from the binary or armored OpenPGP file.
+func readPGPFile(path string) ([]pgpPacket, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading PGP key file: %w", err)
+	}
+
+	data, err = dearmorPGP(data)
+	if err != nil {
+		return nil, fmt.Errorf("PGP key %q: %w", path, err)
+	}
+
+	packets, err := readPGPPackets(data)
+	if err != nil {
+		return nil, fmt.Errorf("PGP key %q: %w", path, err)
+	}
+
+	return packets, nil
+}
+
+// dearmorPGP returns the data of the first armored block, or the data itself if it is not armored.
+func dearmorPGP(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN PGP ")) {
+		return data, nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	encoded := strings.Builder{}
+	inHeaders, inBody := false, false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "-----BEGIN PGP "):
+			inHeaders = true
+		case inHeaders:
+			// The armor headers end with an empty line
+			inHeaders, inBody = line != "", line == ""
+		case !inBody:
+			continue
+		case strings.HasPrefix(line, "=") || strings.HasPrefix(line, "-----END PGP "):
+			return decodeArmorBody(encoded.String())
+		default:
+			encoded.WriteString(line)
+		}
+	}
+
+	return decodeArmorBody(encoded.String())
+}
+
+// decodeArmorBody decodes the base64 encoded body of an armored block.
+func decodeArmorBody(encoded string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding armor: %w", ErrInvalidPGPData, err)
+	}
+
+	return data, nil
+}
+
+// readPGPPackets splits the data into OpenPGP packets, supporting both the old and the new packet format.
+func readPGPPackets(data []byte) ([]pgpPacket, error) {
+	var packets []pgpPacket
+
+	for len(data) > 0 {
+		header := data[0]
+		if header&0x80 == 0 {
+			return nil, fmt.Errorf("%w: invalid packet header", ErrInvalidPGPData)
+		}
+
+		var (
+			tag    byte
+			length int
+			offset int
+		)
+
+		if header&0x40 != 0 {
+			tag = header & 0x3F
+			length, offset = newPGPPacketLength(data[1:])
+		} else {
+			tag = (header >> 2) & 0x0F
+			length, offset = oldPGPPacketLength(data[1:], header&0x03)
+		}
+
+		start := 1 + offset
+		if offset < 0 || length < 0 || start+length > len(data) {
+			return nil, fmt.Errorf("%w: truncated packet", ErrInvalidPGPData)
+		}
+
+		packets = append(packets, pgpPacket{tag: tag, body: data[start : start+length]})
+		data = data[start+length:]
+	}
+
+	return packets, nil
+}
+
+// newPGPPacketLength returns the body length of a new format packet and the size of its length field.
+// Partial body lengths are not used for keys, and are reported as invalid.
+func newPGPPacketLength(data []byte) (int, int) {
+	switch {
+	case len(data) < 1:
+		return -1, -1
+	case data[0] < 192:
+		return int(data[0]), 1
+	case data[0] < 224 && len(data) >= 2:
+		return (int(data[0])-192)<<8 + int(data[1]) + 192, 2
+	case data[0] == 255 && len(data) >= 5:
+		return int(binary.BigEndian.Uint32(data[1:5])), 5
+	default:
+		return -1, -1
+	}
+}
+
+// oldPGPPacketLength returns the body length of an old format packet and the size of its length field.
+func oldPGPPacketLength(data []byte, lengthType byte) (int, int) {
+	switch {
+	case lengthType == 0 && len(data) >= 1:
+		return int(data[0]), 1
+	case lengthType == 1 && len(data) >= 2:
+		return int(binary.BigEndian.Uint16(data)), 2
+	case lengthType == 2 && len(data) >= 4:
+		return int(binary.BigEndian.Uint32(data)), 4
+	case lengthType == 3:
+		return len(data), 0
+	default:
+		return -1, -1
+	}
+}
+
+// parsePGPPublicKey parses the body of a v4 public key packet, returning the key and the length of the public part.
+// Keys with unsupported algorithms are returned without their key material.
+func parsePGPPublicKey(body []byte) (*pgpKey, int, error) {
+	if len(body) < 6 || body[0] != pgpKeyVersion {
+		return nil, 0, fmt.Errorf("%w: only version 4 keys are supported", ErrInvalidPGPData)
+	}
+
+	key := &pgpKey{algorithm: body[5]}
+	offset := 6
+
+	var err error
+
+	switch key.algorithm {
+	case pgpAlgoRSA, pgpAlgoRSAEncrypt, pgpAlgoRSASign:
+		var modulus, exponent []byte
+		if modulus, offset, err = readPGPMPI(body, offset); err != nil {
+			return nil, 0, err
+		}
+
+		if exponent, offset, err = readPGPMPI(body, offset); err != nil {
+			return nil, 0, err
+		}
+
+		key.rsa = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+	case pgpAlgoEdDSA, pgpAlgoECDH:
+		if offset, err = key.parseCurvePoint(body, offset); err != nil {
+			return nil, 0, err
+		}
+
+		if key.algorithm == pgpAlgoECDH {
+			// The KDF parameters consist of their length, a reserved octet, and the hash and cipher algorithms
+			if offset+4 > len(body) || body[offset] != 3 {
+				return nil, 0, fmt.Errorf("%w: invalid KDF parameters", ErrInvalidPGPData)
+			}
+
+			key.kdfHash, key.kdfCipher = body[offset+2], body[offset+3]
+			offset += 4
+		}
+	default:
+		key.publicKey = body
+
+		return key, len(body), nil
+	}
+
+	key.publicKey = body[:offset]
+
+	return key, offset, nil
+}
+
+// parseCurvePoint reads the curve OID and the public point of an ECC key, returning the offset after the point.
+func (key *pgpKey) parseCurvePoint(body []byte, offset int) (int, error) {
+	if offset >= len(body) || offset+1+int(body[offset]) > len(body) {
+		return 0, fmt.Errorf("%w: truncated curve", ErrInvalidPGPData)
+	}
+
+	key.curve = body[offset+1 : offset+1+int(body[offset])]
+
+	point, offset, err := readPGPMPI(body, offset+1+len(key.curve))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(point) != pgpCurve25519Length+1 || point[0] != pgpPointPrefix {
+		key.curve = nil // Only the 25519 curves are supported, which use prefixed native points
+
+		return offset, nil
+	}
+
+	key.point = point[1:]
+
+	return offset, nil
+}
+
+// parsePGPSecretKey parses the body of an unprotected v4 secret key packet with an RSA or Ed25519 key.
+func parsePGPSecretKey(body []byte) (*pgpKey, error) {
+	key, offset, err := parsePGPPublicKey(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= len(body) {
+		return nil, fmt.Errorf("%w: truncated secret key", ErrInvalidPGPData)
+	}
+
+	if body[offset] != 0 {
+		return nil, ErrPGPKeyProtected
+	}
+
+	offset++
+
+	switch {
+	case key.rsa != nil && key.algorithm != pgpAlgoRSAEncrypt:
+		key.signer, err = parsePGPRSAKey(key.rsa, body, offset)
+	case key.algorithm == pgpAlgoEdDSA && bytes.Equal(key.curve, oidPGPEd25519):
+		var seed []byte
+		if seed, _, err = readPGPMPI(body, offset); err == nil && len(seed) <= ed25519.SeedSize {
+			// Leading zeros are dropped from MPIs, so the seed is padded to its full length
+			seed = append(make([]byte, ed25519.SeedSize-len(seed)), seed...)
+			key.signer = ed25519.NewKeyFromSeed(seed)
+		}
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if key.signer == nil {
+		return nil, fmt.Errorf("%w: invalid secret key", ErrInvalidPGPData)
+	}
+
+	return key, nil
+}
+
+// parsePGPRSAKey reads the secret exponent and primes of an RSA key.
+func parsePGPRSAKey(publicKey *rsa.PublicKey, body []byte, offset int) (*rsa.PrivateKey, error) {
+	values := make([]*big.Int, 0, 3)
+
+	for range 3 {
+		var (
+			value []byte
+			err   error
+		)
+
+		if value, offset, err = readPGPMPI(body, offset); err != nil {
+			return nil, err
+		}
+
+		values = append(values, new(big.Int).SetBytes(value))
+	}
+
+	key := &rsa.PrivateKey{PublicKey: *publicKey, D: values[0], Primes: values[1:]}
+	if err := key.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: invalid RSA key: %w", ErrInvalidPGPData, err)
+	}
+
+	key.Precompute()
+
+	return key, nil
+}
+
+// canEncrypt reports whether the key can be used for encrypting the session key.
+func (key *pgpKey) canEncrypt() bool {
+	switch key.algorithm {
+	case pgpAlgoRSA, pgpAlgoRSAEncrypt:
+		return key.rsa != nil
+	case pgpAlgoECDH:
+		return bytes.Equal(key.curve, oidPGPCurve25519) && key.point != nil
+	default:
+		return false
+	}
+}
+
+// fingerprint returns the v4 fingerprint of the key.
+func (key *pgpKey) fingerprint() []byte {
+	digest := sha1.New() //nolint:gosec // The v4 fingerprint is defined using SHA-1
+	digest.Write([]byte{0x99})
+	_ = binary.Write(digest, binary.BigEndian, uint16(len(key.publicKey)))
+	digest.Write(key.publicKey)
+
+	return digest.Sum(nil)
+}
+
+// keyID returns the key ID, consisting of the last eight octets of the fingerprint.
+func (key *pgpKey) keyID() []byte {
+	fingerprint := key.fingerprint()
+
+	return fingerprint[len(fingerprint)-8:]
+}
+
+// sign wraps the entity in a multipart/signed entity, with a detached OpenPGP signature of its content.
+func (key *pgpKey) sign(entity mimeEntity, boundary string) (mimeEntity, error) {
+	content := entity.bytes()
+
+	signature, err := key.signature(content, time.Now())
+	if err != nil {
+		return mimeEntity{}, err
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "This is an OpenPGP/MIME signed message (RFC 4880 and 3156)\r\n\r\n--%s\r\n", boundary)
+	body.Write(content)
+	fmt.Fprintf(body, "\r\n--%s\r\n", boundary)
+	body.WriteString("Content-Type: application/pgp-signature; name=\"signature.asc\"\r\n")
+	body.WriteString("Content-Description: OpenPGP digital signature\r\n")
+	body.WriteString("Content-Disposition: attachment; filename=\"signature.asc\"\r\n\r\n")
+	body.WriteString(armorPGP("SIGNATURE", signature))
+	fmt.Fprintf(body, "\r\n--%s--\r\n", boundary)
+
+	return mimeEntity{
+		headers: map[string]string{"Content-Type": fmt.Sprintf(pgpSignedContentType, boundary)},
+		body:    body.Bytes(),
+	}, nil
+}
+
+// signature creates a v4 signature packet of the binary content, using SHA-256.
+func (key *pgpKey) signature(content []byte, signingTime time.Time) ([]byte, error) {
+	subpackets := slices.Concat(
+		pgpSubpacket(pgpSignatureTime, binary.BigEndian.AppendUint32(nil, uint32(signingTime.Unix()))),
+		pgpSubpacket(pgpIssuerPrint, append([]byte{pgpKeyVersion}, key.fingerprint()...)),
+	)
+
+	// The hashed part starts with the version, the binary document signature type and the algorithms
+	hashed := append([]byte{pgpKeyVersion, 0x00, key.algorithm, pgpHashSHA256}, pgpLength16(subpackets)...)
+	hashed = append(hashed, subpackets...)
+
+	digest := sha256.New()
+	digest.Write(content)
+	digest.Write(hashed)
+	digest.Write([]byte{pgpKeyVersion, 0xFF})
+	_ = binary.Write(digest, binary.BigEndian, uint32(len(hashed)))
+	hashValue := digest.Sum(nil)
+
+	var values []byte
+
+	if key.algorithm == pgpAlgoEdDSA {
+		signature, err := key.signer.Sign(rand.Reader, hashValue, crypto.Hash(0))
+		if err != nil {
+			return nil, fmt.Errorf("creating PGP signature: %w", err)
+		}
+
+		values = append(pgpMPI(signature[:32]), pgpMPI(signature[32:])...)
+	} else {
+		signature, err := key.signer.Sign(rand.Reader, hashValue, crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("creating PGP signature: %w", err)
+		}
+
+		values = pgpMPI(signature)
+	}
+
+	unhashed := pgpSubpacket(pgpSignatureIssuer, key.keyID())
+
+	return pgpPacketBytes(pgpTagSignature, slices.Concat(
+		hashed,
+		pgpLength16(unhashed),
+		unhashed,
+		hashValue[:2],
+		values,
+	)), nil
+}
+
+// encryptPGPEntity encrypts the entity for the keys, returning a multipart/encrypted entity.
+// The content is encrypted with AES-256, as integrity protected data.
+func encryptPGPEntity(entity mimeEntity, keys []*pgpKey, boundary string) (mimeEntity, error) {
+	sessionKey := make([]byte, pgpSessionKeyLength)
+	prefix := make([]byte, aes.BlockSize)
+
+	if _, err := rand.Read(sessionKey); err != nil {
+		return mimeEntity{}, fmt.Errorf("generating session key: %w", err)
+	}
+
+	if _, err := rand.Read(prefix); err != nil {
+		return mimeEntity{}, fmt.Errorf("generating encryption prefix: %w", err)
+	}
+
+	message := &bytes.Buffer{}
+
+	for _, key := range keys {
+		packet, err := key.encryptSessionKey(sessionKey)
+		if err != nil {
+			return mimeEntity{}, err
+		}
+
+		message.Write(packet)
+	}
+
+	// The literal data packet has the binary format, and no file name or date
+	literal := pgpPacketBytes(pgpTagLiteralData, append([]byte{'b', 0, 0, 0, 0, 0}, entity.bytes()...))
+
+	// The prefix repeats its last two octets, and the plaintext ends with the modification detection code
+	plaintext := slices.Concat(prefix, prefix[aes.BlockSize-2:], literal, []byte{0xC0 | pgpTagModificationCode, sha1.Size})
+	checksum := sha1.Sum(plaintext) //nolint:gosec // The modification detection code is defined using SHA-1
+	plaintext = append(plaintext, checksum[:]...)
+
+	ciphertext, err := encryptCFB(sessionKey, plaintext)
+	if err != nil {
+		return mimeEntity{}, err
+	}
+
+	message.Write(pgpPacketBytes(pgpTagEncryptedData, append([]byte{1}, ciphertext...)))
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "This is an OpenPGP/MIME encrypted message (RFC 4880 and 3156)\r\n\r\n--%s\r\n", boundary)
+	body.WriteString("Content-Type: application/pgp-encrypted\r\n")
+	body.WriteString("Content-Description: PGP/MIME version identification\r\n\r\n")
+	fmt.Fprintf(body, "Version: 1\r\n\r\n--%s\r\n", boundary)
+	body.WriteString("Content-Type: application/octet-stream; name=\"encrypted.asc\"\r\n")
+	body.WriteString("Content-Description: OpenPGP encrypted message\r\n")
+	body.WriteString("Content-Disposition: inline; filename=\"encrypted.asc\"\r\n\r\n")
+	body.WriteString(armorPGP("MESSAGE", message.Bytes()))
+	fmt.Fprintf(body, "\r\n--%s--\r\n", boundary)
+
+	return mimeEntity{
+		headers: map[string]string{"Content-Type": fmt.Sprintf(pgpEncryptedContentType, boundary)},
+		body:    body.Bytes(),
+	}, nil
+}
+
+// encryptSessionKey creates a public key encrypted session key packet for the key.
+func (key *pgpKey) encryptSessionKey(sessionKey []byte) ([]byte, error) {
+	var checksum uint16
+	for _, b := range sessionKey {
+		checksum += uint16(b)
+	}
+
+	keyData := binary.BigEndian.AppendUint16(append([]byte{pgpCipherAES256}, sessionKey...), checksum)
+	header := slices.Concat([]byte{3}, key.keyID(), []byte{key.algorithm})
+
+	if key.algorithm == pgpAlgoECDH {
+		values, err := key.encryptECDH(keyData)
+		if err != nil {
+			return nil, err
+		}
+
+		return pgpPacketBytes(pgpTagEncryptedSessionKey, append(header, values...)), nil
+	}
+
+	encrypted, err := rsa.EncryptPKCS1v15(rand.Reader, key.rsa, keyData)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting session key: %w", err)
+	}
+
+	return pgpPacketBytes(pgpTagEncryptedSessionKey, append(header, pgpMPI(encrypted)...)), nil
+}
+
+// encryptECDH encrypts the session key data for a Curve25519 key as described in RFC 6637,
+// returning the ephemeral public point followed by the wrapped key.
+func (key *pgpKey) encryptECDH(keyData []byte) ([]byte, error) {
+	recipient, err := ecdh.X25519().NewPublicKey(key.point)
+	if err != nil {
+		return nil, fmt.Errorf("parsing recipient key: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating ephemeral key: %w", err)
+	}
+
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("deriving shared secret: %w", err)
+	}
+
+	kek, err := key.ecdhKEK(shared)
+	if err != nil {
+		return nil, err
+	}
+
+	// The key data is padded as described in PKCS #5, to a multiple of the key wrap block size
+	padding := pgpKeyWrapBlock - len(keyData)%pgpKeyWrapBlock
+	keyData = append(keyData, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	wrapped, err := aesKeyWrap(kek, keyData)
+	if err != nil {
+		return nil, err
+	}
+
+	point := append([]byte{pgpPointPrefix}, ephemeral.PublicKey().Bytes()...)
+
+	return slices.Concat(pgpMPI(point), []byte{byte(len(wrapped))}, wrapped), nil
+}
+
+// ecdhKEK derives the key encryption key from the shared secret, using the KDF of RFC 6637.
+func (key *pgpKey) ecdhKEK(shared []byte) ([]byte, error) {
+	newHash, keyLength := pgpKDFHash(key.kdfHash), pgpKeyWrapLength(key.kdfCipher)
+	if newHash == nil || keyLength == 0 {
+		return nil, fmt.Errorf("%w: unsupported KDF parameters", ErrNoPGPEncryptionKey)
+	}
+
+	params := slices.Concat(
+		[]byte{byte(len(key.curve))}, key.curve,
+		[]byte{pgpAlgoECDH, 3, 1, key.kdfHash, key.kdfCipher},
+		[]byte("Anonymous Sender    "),
+		key.fingerprint(),
+	)
+
+	digest := newHash()
+	digest.Write([]byte{0, 0, 0, 1})
+	digest.Write(shared)
+	digest.Write(params)
+
+	return digest.Sum(nil)[:keyLength], nil
+}
+
+// pgpKDFHash returns the hash function of the KDF hash algorithm, or nil if it is not supported.
+func pgpKDFHash(algorithm byte) func() hash.Hash {
+	switch algorithm {
+	case pgpHashSHA256:
+		return sha256.New
+	case pgpHashSHA384:
+		return sha512.New384
+	case pgpHashSHA512:
+		return sha512.New
+	default:
+		return nil
+	}
+}
+
+// pgpKeyWrapLength returns the key length of the key wrap algorithm, or 0 if it is not supported.
+func pgpKeyWrapLength(algorithm byte) int {
+	switch algorithm {
+	case pgpCipherAES128:
+		return 16
+	case pgpCipherAES192:
+		return 24
+	case pgpCipherAES256:
+		return 32
+	default:
+		return 0
+	}
+}
+
+// aesKeyWrap wraps the key data with the key encryption key, as described in RFC 3394.
+func aesKeyWrap(kek []byte, keyData []byte) ([]byte, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("creating key wrap cipher: %w", err)
+	}
+
+	blocks := len(keyData) / pgpKeyWrapBlock
+	result := slices.Concat(bytes.Repeat([]byte{0xA6}, pgpKeyWrapBlock), keyData)
+	buffer := make([]byte, aes.BlockSize)
+
+	for step := range 6 {
+		for i := 1; i <= blocks; i++ {
+			copy(buffer, result[:pgpKeyWrapBlock])
+			copy(buffer[pgpKeyWrapBlock:], result[i*pgpKeyWrapBlock:(i+1)*pgpKeyWrapBlock])
+			block.Encrypt(buffer, buffer)
+
+			counter := binary.BigEndian.Uint64(buffer[:pgpKeyWrapBlock]) ^ uint64(blocks*step+i)
+			binary.BigEndian.PutUint64(result[:pgpKeyWrapBlock], counter)
+			copy(result[i*pgpKeyWrapBlock:], buffer[pgpKeyWrapBlock:])
+		}
+	}
+
+	return result, nil
+}
+
+// encryptCFB encrypts the plaintext using AES in CFB mode with a zero IV, as used for integrity protected data.
+func encryptCFB(key []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating content cipher: %w", err)
+	}
+
+	ciphertext := make([]byte, len(plaintext))
+	feedback := make([]byte, aes.BlockSize)
+	keyStream := make([]byte, aes.BlockSize)
+
+	for start := 0; start < len(plaintext); start += aes.BlockSize {
+		block.Encrypt(keyStream, feedback)
+
+		end := min(start+aes.BlockSize, len(plaintext))
+		for i := start; i < end; i++ {
+			ciphertext[i] = plaintext[i] ^ keyStream[i-start]
+		}
+
+		copy(feedback, ciphertext[start:end])
+	}
+
+	return ciphertext, nil
+}
+
+// readPGPMPI reads the multiprecision integer at the offset, returning its value and the offset after it.
+func readPGPMPI(data []byte, offset int) ([]byte, int, error) {
+	if offset+2 > len(data) {
+		return nil, 0, fmt.Errorf("%w: truncated MPI", ErrInvalidPGPData)
+	}
+
+	length := (int(binary.BigEndian.Uint16(data[offset:])) + 7) / 8
+	offset += 2
+
+	if offset+length > len(data) {
+		return nil, 0, fmt.Errorf("%w: truncated MPI", ErrInvalidPGPData)
+	}
+
+	return data[offset : offset+length], offset + length, nil
+}
+
+// pgpMPI encodes the big-endian value as a multiprecision integer, without any leading zeros.
+func pgpMPI(value []byte) []byte {
+	value = bytes.TrimLeft(value, "\x00")
+
+	bits := 0
+	if len(value) > 0 {
+		bits = (len(value)-1)*8 + big.NewInt(int64(value[0])).BitLen()
+	}
+
+	return append(binary.BigEndian.AppendUint16(nil, uint16(bits)), value...)
+}
+
+// pgpSubpacket encodes a signature subpacket, which are always shorter than 192 octets here.
+func pgpSubpacket(subpacketType byte, data []byte) []byte {
+	return append([]byte{byte(len(data) + 1), subpacketType}, data...)
+}
+
+// pgpLength16 encodes the length of the data as two octets.
+func pgpLength16(data []byte) []byte {
+	return binary.BigEndian.AppendUint16(nil, uint16(len(data)))
+}
+
+// pgpPacketBytes encodes a packet in the new format, using a five octet length for long bodies.
+func pgpPacketBytes(tag byte, body []byte) []byte {
+	header := []byte{0xC0 | tag}
+
+	switch length := len(body); {
+	case length < 192:
+		header = append(header, byte(length))
+	case length < 8384:
+		length -= 192
+		header = append(header, byte(length>>8)+192, byte(length))
+	default:
+		header = binary.BigEndian.AppendUint32(append(header, 255), uint32(length))
+	}
+
+	return append(header, body...)
+}
+
+// armorPGP encodes the data as an armored block of the given type, including its CRC-24 checksum.
+func armorPGP(blockType string, data []byte) string {
+	crc := uint32(crc24Init)
+
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+
+		for range 8 {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= crc24Poly
+			}
+		}
+	}
+
+	checksum := []byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}
+
+	return fmt.Sprintf("-----BEGIN PGP %s-----\r\n\r\n%s=%s\r\n-----END PGP %s-----",
+		blockType, encodeBase64Lines(data), base64.StdEncoding.EncodeToString(checksum), blockType)
+}
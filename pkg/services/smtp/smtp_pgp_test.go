@@ -0,0 +1,234 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
+)
+
+// writeTestPGPSecretKey writes the Ed25519 key as an unprotected, armored OpenPGP secret key, returning its path.
+func writeTestPGPSecretKey(dir string, key ed25519.PrivateKey, s2kUsage byte) string {
+	point := append([]byte{pgpPointPrefix}, key.Public().(ed25519.PublicKey)...)
+	public := slices.Concat(
+		[]byte{pgpKeyVersion, 0, 0, 0, 0, pgpAlgoEdDSA, byte(len(oidPGPEd25519))}, oidPGPEd25519, pgpMPI(point),
+	)
+	secret := slices.Concat(public, []byte{s2kUsage}, pgpMPI(key.Seed()), []byte{0, 0})
+
+	path := filepath.Join(dir, "sender.key")
+	armored := armorPGP("PRIVATE KEY BLOCK", pgpPacketBytes(pgpTagSecretKey, secret))
+	gomega.Expect(os.WriteFile(path, []byte(armored), 0o600)).To(gomega.Succeed())
+
+	return path
+}
+
+// writeTestPGPPublicKey writes the RSA key as a binary OpenPGP public key for the address.
+func writeTestPGPPublicKey(dir string, address string, key *rsa.PublicKey) {
+	public := slices.Concat(
+		[]byte{pgpKeyVersion, 0, 0, 0, 0, pgpAlgoRSA}, pgpMPI(key.N.Bytes()), pgpMPI(big.NewInt(int64(key.E)).Bytes()),
+	)
+	packet := pgpPacketBytes(pgpTagPublicKey, public)
+	gomega.Expect(os.WriteFile(filepath.Join(dir, address+".asc"), packet, 0o600)).To(gomega.Succeed())
+}
+
+// readTestParts returns the content type parameters and the parts of the multipart mail.
+func readTestParts(message *mail.Message) (map[string]string, [][]byte) {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	gomega.Expect(mediaType).To(gomega.HavePrefix("multipart/"))
+
+	var parts [][]byte
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for part, err := reader.NextPart(); err == nil; part, err = reader.NextPart() {
+		data, err := io.ReadAll(part)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		parts = append(parts, data)
+	}
+
+	return params, parts
+}
+
+// readTestPackets returns the packets of the armored part.
+func readTestPackets(armored []byte) []pgpPacket {
+	data, err := dearmorPGP(armored)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	packets, err := readPGPPackets(data)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	return packets
+}
+
+// decryptTestCFB decrypts the ciphertext using AES in CFB mode with a zero IV.
+func decryptTestCFB(key []byte, ciphertext []byte) []byte {
+	block, err := aes.NewCipher(key)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	plaintext := make([]byte, len(ciphertext))
+	feedback := make([]byte, aes.BlockSize)
+
+	for start := 0; start < len(ciphertext); start += aes.BlockSize {
+		block.Encrypt(feedback, feedback)
+
+		end := min(start+aes.BlockSize, len(ciphertext))
+		for i := start; i < end; i++ {
+			plaintext[i] = ciphertext[i] ^ feedback[i-start]
+		}
+
+		copy(feedback, ciphertext[start:end])
+	}
+
+	return plaintext
+}
+
+var _ = ginkgo.Describe("the SMTP service using PGP/MIME", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		dir = ginkgo.GinkgoT().TempDir()
+		service = &Service{}
+	})
+
+	ginkgo.It("should sign the message with a detached OpenPGP signature", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		message := composeTestMail("pgpkey=" + writeTestPGPSecretKey(dir, key, 0))
+
+		params, parts := readTestParts(message)
+		gomega.Expect(params).To(gomega.HaveKeyWithValue("protocol", "application/pgp-signature"))
+		gomega.Expect(params).To(gomega.HaveKeyWithValue("micalg", "pgp-sha256"))
+		gomega.Expect(parts).To(gomega.HaveLen(2))
+
+		packets := readTestPackets(parts[1])
+		gomega.Expect(packets).To(gomega.HaveLen(1))
+		gomega.Expect(packets[0].tag).To(gomega.Equal(byte(pgpTagSignature)))
+
+		// The signature covers the signed part including its headers, followed by the hashed subpackets
+		body := packets[0].body
+		hashedLength := 6 + int(binary.BigEndian.Uint16(body[4:6]))
+		hashed := body[:hashedLength]
+		unhashedLength := int(binary.BigEndian.Uint16(body[hashedLength:]))
+		values := body[hashedLength+2+unhashedLength+2:]
+
+		content := []byte("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\nmessage body")
+		digest := sha256.New()
+		digest.Write(content)
+		digest.Write(hashed)
+		digest.Write([]byte{pgpKeyVersion, 0xFF})
+		gomega.Expect(binary.Write(digest, binary.BigEndian, uint32(len(hashed)))).To(gomega.Succeed())
+
+		r, offset, err := readPGPMPI(values, 0)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		s, _, err := readPGPMPI(values, offset)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		signature := slices.Concat(make([]byte, 32-len(r)), r, make([]byte, 32-len(s)), s)
+		gomega.Expect(ed25519.Verify(key.Public().(ed25519.PublicKey), digest.Sum(nil), signature)).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("should encrypt the message for recipients with keys", func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		writeTestPGPPublicKey(dir, "rec1@example.com", &key.PublicKey)
+
+		message := composeTestMail("pgprecipients=" + dir)
+
+		params, parts := readTestParts(message)
+		gomega.Expect(params).To(gomega.HaveKeyWithValue("protocol", "application/pgp-encrypted"))
+		gomega.Expect(parts).To(gomega.HaveLen(2))
+		gomega.Expect(string(parts[0])).To(gomega.ContainSubstring("Version: 1"))
+
+		packets := readTestPackets(parts[1])
+		gomega.Expect(packets).To(gomega.HaveLen(2))
+		gomega.Expect(packets[0].tag).To(gomega.Equal(byte(pgpTagEncryptedSessionKey)))
+		gomega.Expect(packets[1].tag).To(gomega.Equal(byte(pgpTagEncryptedData)))
+
+		encryptedKey, _, err := readPGPMPI(packets[0].body, 10)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		keyData, err := rsa.DecryptPKCS1v15(rand.Reader, key, encryptedKey)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(keyData[0]).To(gomega.Equal(byte(pgpCipherAES256)))
+
+		plaintext := decryptTestCFB(keyData[1:1+pgpSessionKeyLength], packets[1].body[1:])
+		gomega.Expect(plaintext[14:16]).To(gomega.Equal(plaintext[16:18]))
+
+		checksum := sha1.Sum(plaintext[:len(plaintext)-sha1.Size])
+		gomega.Expect(plaintext[len(plaintext)-sha1.Size:]).To(gomega.Equal(checksum[:]))
+
+		literal, err := readPGPPackets(plaintext[18 : len(plaintext)-sha1.Size-2])
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(literal[0].tag).To(gomega.Equal(byte(pgpTagLiteralData)))
+		gomega.Expect(string(literal[0].body[6:])).
+			To(gomega.Equal("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\nmessage body"))
+	})
+
+	ginkgo.It("should fail when a recipient has no key", func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		writeTestPGPPublicKey(dir, "rec1@example.com", &key.PublicKey)
+
+		testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com&pgprecipients=" + dir
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.Succeed())
+
+		recipients := []string{"rec1@example.com", "rec2@example.com"}
+		_, ferr := service.composeMail(envelope{to: recipients, recipients: recipients}, service.Config, "message body")
+		gomega.Expect(ferr).To(gomega.MatchError(ErrNoRecipientPGPKey))
+		gomega.Expect(ferr.Error()).To(gomega.HaveSuffix(": rec2@example.com"))
+	})
+
+	ginkgo.It("should reject passphrase protected keys", func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com&pgpkey=" +
+			writeTestPGPSecretKey(dir, key, 254)
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.MatchError(ErrPGPKeyProtected))
+	})
+
+	ginkgo.It("should not combine S/MIME and PGP/MIME", func() {
+		testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com" +
+			"&smimerecipients=" + dir + "&pgprecipients=" + dir
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).
+			To(gomega.MatchError(ErrSMIMEAndPGPCombined))
+	})
+
+	ginkgo.It("should wrap keys as in the RFC 3394 test vectors", func() {
+		kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+		keyData, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+
+		wrapped, err := aesKeyWrap(kek, keyData)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(hex.EncodeToString(wrapped)).To(gomega.Equal("1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5"))
+	})
+
+	ginkgo.It("should read armored data with headers", func() {
+		data, err := dearmorPGP([]byte(
+			"-----BEGIN PGP PUBLIC KEY BLOCK-----\nComment: test\n\naGVsbG8=\n=AAAA\n-----END PGP PUBLIC KEY BLOCK-----\n",
+		))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(data).To(gomega.Equal([]byte("hello")))
+		gomega.Expect(bytes.HasPrefix([]byte(armorPGP("MESSAGE", data)), []byte("-----BEGIN PGP MESSAGE-----"))).
+			To(gomega.BeTrue())
+	})
+})
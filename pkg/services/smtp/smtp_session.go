@@ -0,0 +1,160 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"syscall"
+	"time"
+)
+
+// serviceNotAvailable is the reply code of a server that is about to close the connection.
+const serviceNotAvailable = 421
+
+// session is a persistent connection to the SMTP server that is reused between sends.
+type session struct {
+	client *smtp.Client
+	stop   chan struct{}
+}
+
+// sendPersistent sends the message over the persistent session, establishing it if needed.
+func (service *Service) sendPersistent(ctx context.Context, message string, config *Config) failure {
+	service.sessionMutex.Lock()
+	defer service.sessionMutex.Unlock()
+
+	client, ferr := service.getSession(ctx, config)
+	if ferr != nil {
+		return ferr
+	}
+
+	errs := service.deliver(client, message, config)
+	if isConnectionLost(errs) {
+		service.Logf("Lost connection to the SMTP server, it will be re-established on the next send")
+		service.closeSession(false)
+	}
+
+	return joinRecipientErrors(errs)
+}
+
+// getSession returns the client of the current session if the server still responds to it,
+// otherwise a new session is established.
+func (service *Service) getSession(ctx context.Context, config *Config) (*smtp.Client, failure) {
+	if service.session != nil {
+		// RSET both verifies that the connection is alive and clears any aborted transaction
+		if err := service.session.client.Reset(); err == nil {
+			return service.session.client, nil
+		}
+
+		service.Logf("SMTP session is no longer usable, reconnecting")
+		service.closeSession(false)
+	}
+
+	client, ferr := service.connect(ctx, config)
+	if ferr != nil {
+		return nil, ferr
+	}
+
+	service.session = &session{client: client, stop: make(chan struct{})}
+
+	if config.KeepAlive > 0 {
+		go service.keepAlive(service.session, config.KeepAlive)
+	}
+
+	return client, nil
+}
+
+// keepAlive periodically sends NOOP commands to prevent the server from closing an idle session.
+func (service *Service) keepAlive(current *session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-current.stop:
+			return
+		case <-ticker.C:
+		}
+
+		service.sessionMutex.Lock()
+
+		if service.session != current {
+			service.sessionMutex.Unlock()
+
+			return
+		}
+
+		if err := current.client.Noop(); err != nil {
+			service.Logf("SMTP keepalive failed, closing session: %v", err)
+			service.closeSession(false)
+		}
+
+		service.sessionMutex.Unlock()
+	}
+}
+
+// closeSession tears down the current session, if any. The caller must hold the session mutex.
+func (service *Service) closeSession(quit bool) error {
+	current := service.session
+	if current == nil {
+		return nil
+	}
+
+	service.session = nil
+
+	close(current.stop)
+
+	if quit {
+		if err := current.client.Quit(); err != nil {
+			_ = current.client.Close()
+
+			return fail(FailClosingSession, err)
+		}
+
+		return nil
+	}
+
+	_ = current.client.Close()
+
+	return nil
+}
+
+// Close ends the persistent session by sending QUIT to the server.
+// It is safe to call when no session has been established.
+func (service *Service) Close() error {
+	service.sessionMutex.Lock()
+	defer service.sessionMutex.Unlock()
+
+	return service.closeSession(true)
+}
+
+// isConnectionLost reports whether any of the errors was caused by the connection itself,
+// rather than by the server rejecting a command or by the message.
+func isConnectionLost(errs []error) bool {
+	for _, err := range errs {
+		if isConnectionError(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isConnectionError reports whether the error means that the connection can no longer be used.
+func isConnectionError(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code == serviceNotAvailable
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,216 @@
+package smtp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
+)
+
+// fakeServer is a minimal SMTP server that accepts everything and records the received commands.
+type fakeServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	commands []string
+	conns    int
+	// dropAfterData makes the server close the connection after accepting a message.
+	dropAfterData bool
+}
+
+func newFakeServer() *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	server := &fakeServer{listener: listener}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			server.mutex.Lock()
+			server.conns++
+			server.mutex.Unlock()
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (server *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 fake ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimRight(line, "\r\n")
+
+		server.mutex.Lock()
+		server.commands = append(server.commands, command)
+		server.mutex.Unlock()
+
+		switch verb, _, _ := strings.Cut(command, " "); strings.ToUpper(verb) {
+		case "EHLO":
+			reply("250 fake")
+		case "DATA":
+			reply("354 Go ahead")
+
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+			}
+
+			reply("250 Data OK")
+
+			if server.dropAfterData {
+				return
+			}
+		case "QUIT":
+			reply("221 Bye")
+
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (server *fakeServer) url(query string) string {
+	return fmt.Sprintf(
+		"smtp://%s/?auth=None&useStartTLS=No&fromAddress=sender@example.com&toAddresses=rec1@example.com&timeout=5s&%s",
+		server.listener.Addr().String(),
+		query,
+	)
+}
+
+func (server *fakeServer) stats() (int, []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.conns, append([]string{}, server.commands...)
+}
+
+var _ = ginkgo.Describe("the SMTP service with a persistent connection", func() {
+	var server *fakeServer
+
+	ginkgo.BeforeEach(func() {
+		server = newFakeServer()
+		service = &Service{}
+	})
+	ginkgo.AfterEach(func() {
+		_ = service.Close()
+		server.listener.Close()
+	})
+
+	ginkgo.It("should reuse the connection between sends", func() {
+		gomega.Expect(service.Initialize(testutils.URLMust(server.url("persistent=Yes")), logger)).
+			To(gomega.Succeed())
+		gomega.Expect(service.Send("first", nil)).To(gomega.Succeed())
+		gomega.Expect(service.Send("second", nil)).To(gomega.Succeed())
+
+		conns, commands := server.stats()
+		gomega.Expect(conns).To(gomega.Equal(1))
+		gomega.Expect(commands).To(gomega.ContainElement("RSET"))
+		gomega.Expect(commands).NotTo(gomega.ContainElement("QUIT"))
+
+		gomega.Expect(service.Close()).To(gomega.Succeed())
+		gomega.Eventually(func() []string {
+			_, commands := server.stats()
+
+			return commands
+		}).Should(gomega.ContainElement("QUIT"))
+	})
+
+	ginkgo.It("should reconnect when the connection has been closed", func() {
+		server.dropAfterData = true
+		gomega.Expect(service.Initialize(testutils.URLMust(server.url("persistent=Yes")), logger)).
+			To(gomega.Succeed())
+		gomega.Expect(service.Send("first", nil)).To(gomega.Succeed())
+		gomega.Expect(service.Send("second", nil)).To(gomega.Succeed())
+
+		conns, _ := server.stats()
+		gomega.Expect(conns).To(gomega.Equal(2))
+	})
+
+	ginkgo.It("should send NOOP commands to keep the connection alive", func() {
+		testURL := server.url("persistent=Yes&keepalive=10ms")
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.Succeed())
+		gomega.Expect(service.Send("message", nil)).To(gomega.Succeed())
+
+		gomega.Eventually(func() []string {
+			_, commands := server.stats()
+
+			return commands
+		}, time.Second).Should(gomega.ContainElement("NOOP"))
+	})
+
+	ginkgo.It("should close the connection after sending when not persistent", func() {
+		gomega.Expect(service.Initialize(testutils.URLMust(server.url("")), logger)).
+			To(gomega.Succeed())
+		gomega.Expect(service.Send("first", nil)).To(gomega.Succeed())
+		gomega.Expect(service.Send("second", nil)).To(gomega.Succeed())
+
+		conns, _ := server.stats()
+		gomega.Expect(conns).To(gomega.Equal(2))
+	})
+
+	ginkgo.It("should check the server using the session setup of sends", func() {
+		gomega.Expect(service.Initialize(testutils.URLMust(server.url("clienthost=checker.local")), logger)).
+			To(gomega.Succeed())
+
+		result, err := service.Check()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(result.Reachable).To(gomega.BeTrue())
+		gomega.Expect(result.Authenticated).To(gomega.BeTrue())
+
+		_, commands := server.stats()
+		gomega.Expect(commands).To(gomega.Equal([]string{"EHLO checker.local", "QUIT"}))
+	})
+
+	ginkgo.It("should report an unreachable server", func() {
+		gomega.Expect(service.Initialize(testutils.URLMust(server.url("")), logger)).To(gomega.Succeed())
+		server.listener.Close()
+
+		result, err := service.Check()
+		gomega.Expect(err).To(matchFailure(FailGetSMTPClient))
+		gomega.Expect(result.Reachable).To(gomega.BeFalse())
+	})
+})
+
+var _ = ginkgo.DescribeTable("classifying lost connections",
+	func(err error, lost bool) {
+		gomega.Expect(isConnectionLost([]error{fail(FailSendRecipient, err, "to@example.com")})).To(gomega.Equal(lost))
+	},
+	ginkgo.Entry("rejected recipient", &textproto.Error{Code: 550, Msg: "no such user"}, false),
+	ginkgo.Entry("server closing the connection", &textproto.Error{Code: 421, Msg: "closing"}, true),
+	ginkgo.Entry("closed by the server", io.EOF, true),
+	ginkgo.Entry("connection reset", &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.ECONNRESET)}, true),
+	ginkgo.Entry("broken pipe", fmt.Errorf("writing data: %w", syscall.EPIPE), true),
+	ginkgo.Entry("message error", errors.New("failed to sign message"), false),
+)
@@ -0,0 +1,284 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/internal/testutils"
+)
+
+type testContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type testSignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo asn1.RawValue
+	Certificates     asn1.RawValue    `asn1:"optional,tag:0"`
+	SignerInfos      []testSignerInfo `asn1:"set"`
+}
+
+type testSignerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    asn1.RawValue
+	SignedAttrs        asn1.RawValue `asn1:"tag:0"`
+	SignatureAlgorithm asn1.RawValue
+	Signature          []byte
+}
+
+type testEnvelopedData struct {
+	Version              int
+	RecipientInfos       []testRecipientInfo `asn1:"set"`
+	EncryptedContentInfo struct {
+		ContentType asn1.ObjectIdentifier
+		Algorithm   struct {
+			Algorithm asn1.ObjectIdentifier
+			IV        []byte
+		}
+		EncryptedContent []byte `asn1:"tag:0"`
+	}
+}
+
+type testRecipientInfo struct {
+	Version                int
+	RID                    asn1.RawValue
+	KeyEncryptionAlgorithm asn1.RawValue
+	EncryptedKey           []byte
+}
+
+// writeTestCertificate creates a self-signed certificate for the address, writing it and its key to the directory.
+func writeTestCertificate(dir string, address string) (*rsa.PrivateKey, string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		Subject:        pkix.Name{CommonName: address},
+		EmailAddresses: []string{address},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	certPath := filepath.Join(dir, address+".pem")
+	keyPath := filepath.Join(dir, address+".key")
+	gomega.Expect(os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), 0o600)).
+		To(gomega.Succeed())
+	gomega.Expect(os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600)).To(gomega.Succeed())
+
+	return key, certPath, keyPath
+}
+
+// composeTestMail initializes the service with the query and returns the parsed mail for rec1@example.com.
+func composeTestMail(query string) *mail.Message {
+	testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com&" + query
+	gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.Succeed())
+
+	recipients := []string{"rec1@example.com"}
+	data, ferr := service.composeMail(envelope{to: recipients, recipients: recipients}, service.Config, "message body")
+	gomega.Expect(ferr).NotTo(gomega.HaveOccurred())
+
+	message, err := mail.ReadMessage(bytes.NewReader(toCRLF(data)))
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	return message
+}
+
+var _ = ginkgo.Describe("the SMTP service signing messages", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		dir = ginkgo.GinkgoT().TempDir()
+		service = &Service{}
+	})
+
+	ginkgo.It("should canonicalize headers and bodies as in the DKIM examples", func() {
+		gomega.Expect(dkimCanonicalHeader("B ", "Y\t\r\n\tZ  ")).To(gomega.Equal("b:Y Z"))
+		gomega.Expect(string(dkimCanonicalBody([]byte(" C \r\nD \t E\r\n\r\n\r\n")))).To(gomega.Equal(" C\r\nD E\r\n"))
+		gomega.Expect(dkimCanonicalBody([]byte("\r\n"))).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should add a verifiable DKIM signature", func() {
+		key, _, keyPath := writeTestCertificate(dir, "sender@example.com")
+		message := composeTestMail("dkimkey=" + keyPath + "&dkimselector=mail")
+
+		tags := map[string]string{}
+		for _, tag := range strings.Split(message.Header.Get("DKIM-Signature"), ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(tag), "=")
+			tags[name] = value
+		}
+
+		gomega.Expect(tags).To(gomega.HaveKeyWithValue("d", "example.com"))
+		gomega.Expect(tags).To(gomega.HaveKeyWithValue("s", "mail"))
+		gomega.Expect(tags["h"]).To(gomega.ContainSubstring("from"))
+
+		body, err := io.ReadAll(message.Body)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		bodyHash := sha256.Sum256(dkimCanonicalBody(body))
+		gomega.Expect(tags["bh"]).To(gomega.Equal(base64.StdEncoding.EncodeToString(bodyHash[:])))
+
+		signed := ""
+		for _, name := range strings.Split(tags["h"], ":") {
+			signed += dkimCanonicalHeader(name, message.Header.Get(name)) + "\r\n"
+		}
+
+		value := message.Header.Get("DKIM-Signature")
+		signed += dkimCanonicalHeader("DKIM-Signature", strings.TrimSuffix(value, tags["b"]))
+		digest := sha256.Sum256([]byte(signed))
+		signature, err := base64.StdEncoding.DecodeString(tags["b"])
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("should require a DKIM selector", func() {
+		_, _, keyPath := writeTestCertificate(dir, "sender@example.com")
+		testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com&dkimkey=" + keyPath
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.MatchError(ErrDKIMIncomplete))
+	})
+
+	ginkgo.It("should sign the message with S/MIME", func() {
+		key, certPath, keyPath := writeTestCertificate(dir, "sender@example.com")
+		message := composeTestMail("smimecert=" + certPath + "&smimekey=" + keyPath)
+
+		mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(mediaType).To(gomega.Equal("multipart/signed"))
+
+		body, err := io.ReadAll(message.Body)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		// The signed content is the first part, including its headers, as it appears in the mail
+		delimiter := "\r\n--" + params["boundary"]
+		start := bytes.Index(body, []byte("--"+params["boundary"]+"\r\n")) + len(params["boundary"]) + 4
+		content := body[start : start+bytes.Index(body[start:], []byte(delimiter))]
+		gomega.Expect(string(content)).To(gomega.HavePrefix("Content-Type: text/plain"))
+
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		_, err = reader.NextPart()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		signaturePart, err := reader.NextPart()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		encoded, err := io.ReadAll(signaturePart)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		der, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		var contentInfo testContentInfo
+		_, err = asn1.Unmarshal(der, &contentInfo)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		var signedData testSignedData
+		_, err = asn1.Unmarshal(contentInfo.Content.Bytes, &signedData)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(signedData.SignerInfos).To(gomega.HaveLen(1))
+
+		signerInfo := signedData.SignerInfos[0]
+		digest := sha256.Sum256(content)
+		gomega.Expect(signerInfo.SignedAttrs.Bytes).To(gomega.ContainSubstring(string(digest[:])))
+
+		// The attributes are signed with the SET tag instead of the implicit one
+		attributes := append([]byte{tagSet}, signerInfo.SignedAttrs.FullBytes[1:]...)
+		attributesDigest := sha256.Sum256(attributes)
+		gomega.Expect(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, attributesDigest[:], signerInfo.Signature)).
+			To(gomega.Succeed())
+	})
+
+	ginkgo.It("should encrypt the message for recipients with certificates", func() {
+		key, _, _ := writeTestCertificate(dir, "rec1@example.com")
+		message := composeTestMail("smimerecipients=" + dir)
+		gomega.Expect(message.Header.Get("Content-Type")).To(gomega.HavePrefix("application/pkcs7-mime"))
+
+		encoded, err := io.ReadAll(message.Body)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		der, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		var contentInfo testContentInfo
+		_, err = asn1.Unmarshal(der, &contentInfo)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		var envelopedData testEnvelopedData
+		_, err = asn1.Unmarshal(contentInfo.Content.Bytes, &envelopedData)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(envelopedData.RecipientInfos).To(gomega.HaveLen(1))
+
+		contentKey, err := rsa.DecryptOAEP(
+			sha256.New(), nil, key, envelopedData.RecipientInfos[0].EncryptedKey, nil,
+		)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		block, err := aes.NewCipher(contentKey)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		encrypted := envelopedData.EncryptedContentInfo
+		content := make([]byte, len(encrypted.EncryptedContent))
+		cipher.NewCBCDecrypter(block, encrypted.Algorithm.IV).CryptBlocks(content, encrypted.EncryptedContent)
+		content = content[:len(content)-int(content[len(content)-1])]
+		gomega.Expect(string(content)).To(gomega.Equal("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\nmessage body"))
+	})
+
+	ginkgo.It("should fail when a recipient has no certificate", func() {
+		writeTestCertificate(dir, "rec1@example.com")
+		testURL := "smtp://example.com/?fromAddress=sender@example.com&toAddresses=rec1@example.com&smimerecipients=" + dir
+		gomega.Expect(service.Initialize(testutils.URLMust(testURL), logger)).To(gomega.Succeed())
+
+		recipients := []string{"rec1@example.com", "rec2@example.com"}
+		_, ferr := service.composeMail(envelope{to: recipients, recipients: recipients}, service.Config, "message body")
+		gomega.Expect(ferr).To(gomega.MatchError(ErrNoRecipientCertificate))
+		gomega.Expect(ferr.Error()).To(gomega.HaveSuffix(": rec2@example.com"))
+	})
+
+	ginkgo.It("should send each blind copy of an encrypted mail separately", func() {
+		config := &Config{
+			ToAddresses:     []string{"rec1@example.com", "rec2@example.com"},
+			CcAddresses:     []string{"cc@example.com"},
+			BccAddresses:    []string{"bcc1@example.com", "bcc2@example.com"},
+			SMIMERecipients: dir,
+			Bulk:            true,
+		}
+		to := config.ToAddresses
+		gomega.Expect(getEnvelopes(config)).To(gomega.Equal([]envelope{
+			{to: to, recipients: []string{"rec1@example.com", "rec2@example.com", "cc@example.com"}},
+			{to: to, recipients: []string{"bcc1@example.com"}},
+			{to: to, recipients: []string{"bcc2@example.com"}},
+		}))
+
+		config.Bulk = false
+		gomega.Expect(getEnvelopes(config)).To(gomega.Equal([]envelope{
+			{to: []string{"rec1@example.com"}, recipients: []string{"rec1@example.com"}},
+			{to: []string{"rec2@example.com"}, recipients: []string{"rec2@example.com"}},
+			{to: to, recipients: []string{"cc@example.com"}},
+			{to: to, recipients: []string{"bcc1@example.com"}},
+			{to: to, recipients: []string{"bcc2@example.com"}},
+		}))
+	})
+})
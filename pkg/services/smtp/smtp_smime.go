@@ -0,0 +1,462 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	smimeKeyLength   = 32 // smimeKeyLength is the length of the AES-256 content encryption key.
+	base64LineLength = 76 // base64LineLength is the maximum line length of base64 encoded MIME bodies.
+)
+
+// DER tags of the ASN.1 types used in CMS structures.
+const (
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagUTCTime     = 0x17
+	tagSequence    = 0x30
+	tagSet         = 0x31
+	tagContext     = 0x80 // tagContext is the primitive context specific tag [0].
+	tagContextSet  = 0xA0 // tagContextSet is the constructed context specific tag [0].
+)
+
+const smimeSignedContentType = `multipart/signed; protocol="application/pkcs7-signature"; micalg=sha-256; boundary=%s`
+
+// DER encoded object identifiers used in the CMS structures of S/MIME messages.
+var (
+	oidData              = mustMarshalOID(1, 2, 840, 113549, 1, 7, 1)
+	oidSignedData        = mustMarshalOID(1, 2, 840, 113549, 1, 7, 2)
+	oidEnvelopedData     = mustMarshalOID(1, 2, 840, 113549, 1, 7, 3)
+	oidAttrContentType   = mustMarshalOID(1, 2, 840, 113549, 1, 9, 3)
+	oidAttrMessageDigest = mustMarshalOID(1, 2, 840, 113549, 1, 9, 4)
+	oidAttrSigningTime   = mustMarshalOID(1, 2, 840, 113549, 1, 9, 5)
+	oidSHA256            = mustMarshalOID(2, 16, 840, 1, 101, 3, 4, 2, 1)
+	oidRSAEncryption     = mustMarshalOID(1, 2, 840, 113549, 1, 1, 1)
+	oidRSAESOAEP         = mustMarshalOID(1, 2, 840, 113549, 1, 1, 7)
+	oidMGF1              = mustMarshalOID(1, 2, 840, 113549, 1, 1, 8)
+	oidECDSAWithSHA256   = mustMarshalOID(1, 2, 840, 10045, 4, 3, 2)
+	oidAES256CBC         = mustMarshalOID(2, 16, 840, 1, 101, 3, 4, 1, 42)
+	derNull              = []byte{0x05, 0x00}
+)
+
+// Static errors for S/MIME operations.
+var (
+	ErrUnsupportedKey         = errors.New("unsupported private key type")
+	ErrNoCertificate          = errors.New("no certificate found")
+	ErrUnsupportedCipher      = errors.New("only RSA certificates are supported for encryption")
+	ErrNoRecipientCertificate = errors.New("no S/MIME certificate found for recipients")
+)
+
+// mimeEntity is the content of a mail, consisting of its content headers and body.
+type mimeEntity struct {
+	headers map[string]string
+	body    []byte
+}
+
+// bytes returns the canonical representation of the entity, with CRLF line endings.
+func (entity mimeEntity) bytes() []byte {
+	buffer := &bytes.Buffer{}
+
+	for _, key := range slices.Sorted(maps.Keys(entity.headers)) {
+		fmt.Fprintf(buffer, "%s: %s\r\n", key, entity.headers[key])
+	}
+
+	buffer.WriteString("\r\n")
+	buffer.Write(toCRLF(entity.body))
+
+	return buffer.Bytes()
+}
+
+// toCRLF converts all line endings to CRLF.
+func toCRLF(data []byte) []byte {
+	return bytes.ReplaceAll(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+}
+
+// smimeSigner signs mail entities with a certificate and its private key.
+type smimeSigner struct {
+	certificate *x509.Certificate
+	chain       []*x509.Certificate
+	key         crypto.Signer
+}
+
+// loadSMIMESigner reads the certificate, including any intermediate certificates, and the private key from PEM files.
+func loadSMIMESigner(certPath string, keyPath string) (*smimeSigner, error) {
+	certificates, err := loadCertificates(certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadPrivateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey:
+	default:
+		return nil, fmt.Errorf("S/MIME key %q: %w", keyPath, ErrUnsupportedKey)
+	}
+
+	return &smimeSigner{certificate: certificates[0], chain: certificates, key: key}, nil
+}
+
+// sign wraps the entity in a multipart/signed entity, with a detached CMS signature of its content.
+func (signer *smimeSigner) sign(entity mimeEntity, boundary string) (mimeEntity, error) {
+	content := entity.bytes()
+
+	signature, err := signer.signedData(content, time.Now())
+	if err != nil {
+		return mimeEntity{}, err
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "This is an S/MIME signed message\r\n\r\n--%s\r\n", boundary)
+	body.Write(content)
+	fmt.Fprintf(body, "\r\n--%s\r\n", boundary)
+	body.WriteString("Content-Type: application/pkcs7-signature; name=\"smime.p7s\"\r\n")
+	body.WriteString("Content-Transfer-Encoding: base64\r\n")
+	body.WriteString("Content-Disposition: attachment; filename=\"smime.p7s\"\r\n\r\n")
+	body.WriteString(encodeBase64Lines(signature))
+	fmt.Fprintf(body, "--%s--\r\n", boundary)
+
+	return mimeEntity{
+		headers: map[string]string{"Content-Type": fmt.Sprintf(smimeSignedContentType, boundary)},
+		body:    body.Bytes(),
+	}, nil
+}
+
+// signedData creates a DER encoded CMS SignedData structure with a detached signature of the content.
+func (signer *smimeSigner) signedData(content []byte, signingTime time.Time) ([]byte, error) {
+	digest := sha256.Sum256(content)
+
+	attributes := [][]byte{
+		derSequence(oidAttrContentType, derSet(oidData)),
+		derSequence(
+			oidAttrSigningTime,
+			derSet(derElement(tagUTCTime, []byte(signingTime.UTC().Format("060102150405Z")))),
+		),
+		derSequence(oidAttrMessageDigest, derSet(derElement(tagOctetString, digest[:]))),
+	}
+
+	// The signature is calculated over the attributes encoded as a SET, which in DER has to be sorted
+	slices.SortFunc(attributes, bytes.Compare)
+	attributesDigest := sha256.Sum256(derSet(attributes...))
+
+	signature, err := signer.key.Sign(rand.Reader, attributesDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("signing S/MIME attributes: %w", err)
+	}
+
+	signatureAlgorithm := derSequence(oidRSAEncryption, derNull)
+	if _, isECDSA := signer.key.(*ecdsa.PrivateKey); isECDSA {
+		signatureAlgorithm = derSequence(oidECDSAWithSHA256)
+	}
+
+	digestAlgorithm := derSequence(oidSHA256, derNull)
+
+	certificates := make([][]byte, 0, len(signer.chain))
+	for _, certificate := range signer.chain {
+		certificates = append(certificates, certificate.Raw)
+	}
+
+	signerInfo := derSequence(
+		derInteger(big.NewInt(1)),
+		issuerAndSerialNumber(signer.certificate),
+		digestAlgorithm,
+		derTagged(0, attributes...),
+		signatureAlgorithm,
+		derElement(tagOctetString, signature),
+	)
+
+	signedData := derSequence(
+		derInteger(big.NewInt(1)),
+		derSet(digestAlgorithm),
+		derSequence(oidData),
+		derTagged(0, certificates...),
+		derSet(signerInfo),
+	)
+
+	return derSequence(oidSignedData, derTagged(0, signedData)), nil
+}
+
+// encryptEntity encrypts the entity for the certificates, returning an application/pkcs7-mime entity.
+func encryptEntity(entity mimeEntity, certificates []*x509.Certificate) (mimeEntity, error) {
+	key := make([]byte, smimeKeyLength)
+	iv := make([]byte, aes.BlockSize)
+
+	if _, err := rand.Read(key); err != nil {
+		return mimeEntity{}, fmt.Errorf("generating content encryption key: %w", err)
+	}
+
+	if _, err := rand.Read(iv); err != nil {
+		return mimeEntity{}, fmt.Errorf("generating initialization vector: %w", err)
+	}
+
+	// The content is padded as described in PKCS #7
+	content := entity.bytes()
+	padding := aes.BlockSize - len(content)%aes.BlockSize
+	content = append(content, bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return mimeEntity{}, fmt.Errorf("creating content cipher: %w", err)
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(content, content)
+
+	recipientInfos := make([][]byte, 0, len(certificates))
+
+	for _, certificate := range certificates {
+		publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return mimeEntity{}, fmt.Errorf("certificate %q: %w", certificate.Subject, ErrUnsupportedCipher)
+		}
+
+		encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, key, nil)
+		if err != nil {
+			return mimeEntity{}, fmt.Errorf("encrypting content key for %q: %w", certificate.Subject, err)
+		}
+
+		recipientInfos = append(recipientInfos, derSequence(
+			derInteger(big.NewInt(0)),
+			issuerAndSerialNumber(certificate),
+			oaepAlgorithm(),
+			derElement(tagOctetString, encryptedKey),
+		))
+	}
+
+	slices.SortFunc(recipientInfos, bytes.Compare)
+
+	envelopedData := derSequence(
+		derInteger(big.NewInt(0)),
+		derSet(recipientInfos...),
+		derSequence(
+			oidData,
+			derSequence(oidAES256CBC, derElement(tagOctetString, iv)),
+			derElement(tagContext, content),
+		),
+	)
+
+	data := derSequence(oidEnvelopedData, derTagged(0, envelopedData))
+
+	return mimeEntity{
+		headers: map[string]string{
+			"Content-Type":              `application/pkcs7-mime; smime-type=enveloped-data; name="smime.p7m"`,
+			"Content-Transfer-Encoding": "base64",
+			"Content-Disposition":       `attachment; filename="smime.p7m"`,
+		},
+		body: []byte(encodeBase64Lines(data)),
+	}, nil
+}
+
+// loadRecipientCertificates reads the certificates of the recipients from the directory, where they are
+// stored as <address>.pem. It fails with ErrNoRecipientCertificate, naming the recipients without a certificate,
+// so that the mail is never sent unencrypted.
+func loadRecipientCertificates(dir string, recipients []string) ([]*x509.Certificate, error) {
+	certificates := make([]*x509.Certificate, 0, len(recipients))
+
+	var missing []string
+
+	for _, recipient := range recipients {
+		path := filepath.Join(dir, strings.ToLower(recipient)+".pem")
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, recipient)
+
+			continue
+		}
+
+		chain, err := loadCertificates(path)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, chain[0])
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecipientCertificate, strings.Join(missing, ", "))
+	}
+
+	return certificates, nil
+}
+
+// loadCertificates reads all certificates from the PEM file.
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate file: %w", err)
+	}
+
+	var certificates []*x509.Certificate
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate in %q: %w", path, err)
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("%w in %q", ErrNoCertificate, path)
+	}
+
+	return certificates, nil
+}
+
+// loadPrivateKey reads a PKCS #1, PKCS #8 or SEC 1 encoded private key from the PEM file.
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading private key file: %w", err)
+	}
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		var key any
+
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parsing private key in %q: %w", path, err)
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("private key in %q: %w", path, ErrUnsupportedKey)
+		}
+
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("%w: no private key found in %q", ErrUnsupportedKey, path)
+}
+
+// issuerAndSerialNumber identifies the certificate in CMS structures.
+func issuerAndSerialNumber(certificate *x509.Certificate) []byte {
+	return derSequence(certificate.RawIssuer, derInteger(certificate.SerialNumber))
+}
+
+// oaepAlgorithm returns the RSAES-OAEP algorithm identifier, using SHA-256 for both the hash and MGF1.
+func oaepAlgorithm() []byte {
+	sha256Algorithm := derSequence(oidSHA256, derNull)
+
+	return derSequence(
+		oidRSAESOAEP,
+		derSequence(
+			derTagged(0, sha256Algorithm),
+			derTagged(1, derSequence(oidMGF1, sha256Algorithm)),
+		),
+	)
+}
+
+// encodeBase64Lines encodes the data as base64, split into lines of the maximum MIME line length.
+func encodeBase64Lines(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	builder := strings.Builder{}
+
+	for len(encoded) > base64LineLength {
+		builder.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+
+	builder.WriteString(encoded + "\r\n")
+
+	return builder.String()
+}
+
+// randomBoundary returns a random MIME boundary.
+func randomBoundary() (string, error) {
+	b := make([]byte, boundaryByteLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating boundary: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// derElement encodes the concatenated contents as a DER element with the given tag.
+func derElement(tag byte, contents ...[]byte) []byte {
+	content := bytes.Join(contents, nil)
+
+	if len(content) < 0x80 {
+		return append([]byte{tag, byte(len(content))}, content...)
+	}
+
+	var length []byte
+	for remaining := len(content); remaining > 0; remaining >>= 8 {
+		length = append([]byte{byte(remaining)}, length...)
+	}
+
+	return append(append([]byte{tag, 0x80 | byte(len(length))}, length...), content...)
+}
+
+// derSequence encodes the DER encoded elements as a SEQUENCE.
+func derSequence(elements ...[]byte) []byte {
+	return derElement(tagSequence, elements...)
+}
+
+// derSet encodes the DER encoded elements as a SET. The elements are expected to be sorted already.
+func derSet(elements ...[]byte) []byte {
+	return derElement(tagSet, elements...)
+}
+
+// derTagged encodes the DER encoded elements with a constructed context specific tag.
+func derTagged(tag byte, elements ...[]byte) []byte {
+	return derElement(tagContextSet|tag, elements...)
+}
+
+// derInteger encodes the non-negative value as an INTEGER.
+func derInteger(value *big.Int) []byte {
+	content := value.Bytes()
+	if len(content) == 0 || content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+
+	return derElement(tagInteger, content)
+}
+
+// mustMarshalOID returns the DER encoding of the object identifier.
+// It panics for invalid identifiers, and is only used for initializing the package variables.
+func mustMarshalOID(components ...int) []byte {
+	encoded, err := asn1.Marshal(asn1.ObjectIdentifier(components))
+	if err != nil {
+		panic(fmt.Sprintf("encoding object identifier %v: %v", components, err))
+	}
+
+	return encoded
+}
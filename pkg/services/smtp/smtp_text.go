@@ -0,0 +1,71 @@
+package smtp
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	htmlIgnored     = regexp.MustCompile(`(?is)<(script|style|head)\b.*?</(script|style|head)\s*>|<!--.*?-->`)
+	htmlPre         = regexp.MustCompile(`(?is)<pre\b[^>]*>(.*?)</pre\s*>`)
+	htmlLink        = regexp.MustCompile(`(?is)<a\b[^>]*?\bhref\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a\s*>`)
+	htmlListItem    = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlLineBreak   = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlBlockEnd    = regexp.MustCompile(`(?i)</?(p|div|h[1-6]|ul|ol|table|tr|blockquote|hr)\b[^>]*>`)
+	htmlTag         = regexp.MustCompile(`(?s)<[^>]*>`)
+	textSpaces      = regexp.MustCompile(`[ \t\r\n]+`)
+	textBlankLines  = regexp.MustCompile(`\n{3,}`)
+	textPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// htmlToText creates a plain text version of the HTML message, for mail clients that do not display HTML.
+// Block elements and line breaks become new lines, list items are prefixed with a dash, and the
+// targets of links are added after their text. The content of pre elements is kept as is.
+func htmlToText(message string) string {
+	text := htmlIgnored.ReplaceAllString(message, "")
+
+	// Preformatted blocks are replaced by placeholders, to keep their whitespace
+	var preformatted []string
+
+	text = htmlPre.ReplaceAllStringFunc(text, func(match string) string {
+		content := htmlTag.ReplaceAllString(htmlPre.FindStringSubmatch(match)[1], "")
+		preformatted = append(preformatted, html.UnescapeString(content))
+
+		return "\n\x00" + strconv.Itoa(len(preformatted)-1) + "\x00\n"
+	})
+
+	text = htmlLink.ReplaceAllStringFunc(text, func(match string) string {
+		parts := htmlLink.FindStringSubmatch(match)
+		label := strings.TrimSpace(htmlTag.ReplaceAllString(parts[2], ""))
+
+		if label == "" || label == parts[1] || strings.HasPrefix(parts[1], "#") {
+			return label
+		}
+
+		return label + " (" + parts[1] + ")"
+	})
+
+	// Whitespace in the HTML source is insignificant, while the elements below define the lines
+	text = textSpaces.ReplaceAllString(text, " ")
+	text = htmlListItem.ReplaceAllString(text, "\n- ")
+	text = htmlLineBreak.ReplaceAllString(text, "\n")
+	text = htmlBlockEnd.ReplaceAllString(text, "\n\n")
+	text = html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	text = textBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+
+	text = textPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		index, _ := strconv.Atoi(textPlaceholder.FindStringSubmatch(match)[1])
+
+		return strings.Trim(preformatted[index], "\n")
+	})
+
+	return strings.TrimSpace(text)
+}
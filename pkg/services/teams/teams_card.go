@@ -0,0 +1,239 @@
+package teams
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// Adaptive Card constants.
+const (
+	AdaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	AdaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	AdaptiveCardVersion     = "1.4"
+)
+
+// levelStyles maps message levels to Adaptive Card container styles.
+var levelStyles = [types.MessageLevelCount]string{
+	types.Unknown: "default",
+	types.Debug:   "emphasis",
+	types.Info:    "accent",
+	types.Warning: "warning",
+	types.Error:   "attention",
+}
+
+// ActionButton is an action button that opens a URL when clicked.
+type ActionButton struct {
+	URL   string
+	Title string
+}
+
+// SetFromProp deserializes an action button from a string in the format "url" or "url|title".
+func (a *ActionButton) SetFromProp(propValue string) error {
+	target, title, _ := strings.Cut(propValue, "|")
+	if target == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidActionFormat, propValue)
+	}
+
+	a.URL = target
+	a.Title = title
+
+	return nil
+}
+
+// GetPropValue serializes an action button back into a string in the format "url" or "url|title".
+func (a *ActionButton) GetPropValue() (string, error) {
+	if a.Title == "" {
+		return a.URL, nil
+	}
+
+	return a.URL + "|" + a.Title, nil
+}
+
+// workflowPayload is the message envelope expected by the Teams workflow webhook trigger.
+type workflowPayload struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+// attachment wraps an Adaptive Card in a workflow message.
+type attachment struct {
+	ContentType string       `json:"contentType"`
+	ContentURL  *string      `json:"contentUrl"`
+	Content     adaptiveCard `json:"content"`
+}
+
+// adaptiveCard is the root element of an Adaptive Card.
+type adaptiveCard struct {
+	Schema  string       `json:"$schema"`
+	Type    string       `json:"type"`
+	Version string       `json:"version"`
+	Body    []cardItem   `json:"body"`
+	Actions []cardAction `json:"actions,omitempty"`
+	MSTeams *msTeams     `json:"msteams,omitempty"`
+}
+
+// msTeams holds the Teams specific card properties.
+type msTeams struct {
+	Width string `json:"width"`
+}
+
+// cardItem is an element in the body of an Adaptive Card, such as a TextBlock, Container or FactSet.
+type cardItem struct {
+	Type     string     `json:"type"`
+	Text     string     `json:"text,omitempty"`
+	Size     string     `json:"size,omitempty"`
+	Weight   string     `json:"weight,omitempty"`
+	IsSubtle bool       `json:"isSubtle,omitempty"`
+	Wrap     bool       `json:"wrap,omitempty"`
+	Style    string     `json:"style,omitempty"`
+	Bleed    bool       `json:"bleed,omitempty"`
+	Items    []cardItem `json:"items,omitempty"`
+	Facts    []cardFact `json:"facts,omitempty"`
+}
+
+// cardFact is a key-value pair in an Adaptive Card FactSet.
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// cardAction is an action button of an Adaptive Card.
+type cardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// createWorkflowPayload builds an Adaptive Card message from the message items.
+// Each item is rendered in its own container, styled according to the item level.
+func createWorkflowPayload(
+	title string,
+	items []types.MessageItem,
+	facts map[string]string,
+	actions []ActionButton,
+) workflowPayload {
+	body := make([]cardItem, 0, len(items)+2)
+
+	if title != "" {
+		body = append(body, cardItem{
+			Type:   "TextBlock",
+			Text:   title,
+			Size:   "Large",
+			Weight: "Bolder",
+			Wrap:   true,
+		})
+	}
+
+	for _, item := range items {
+		body = append(body, createItemContainer(item))
+	}
+
+	if len(facts) > 0 {
+		body = append(body, cardItem{
+			Type:  "FactSet",
+			Facts: createFacts(facts),
+		})
+	}
+
+	cardActions := make([]cardAction, 0, len(actions))
+	for _, button := range actions {
+		buttonTitle := button.Title
+		if buttonTitle == "" {
+			buttonTitle = button.URL
+		}
+
+		cardActions = append(cardActions, cardAction{
+			Type:  "Action.OpenUrl",
+			Title: buttonTitle,
+			URL:   button.URL,
+		})
+	}
+
+	return workflowPayload{
+		Type: "message",
+		Attachments: []attachment{{
+			ContentType: AdaptiveCardContentType,
+			Content: adaptiveCard{
+				Schema:  AdaptiveCardSchema,
+				Type:    "AdaptiveCard",
+				Version: AdaptiveCardVersion,
+				Body:    body,
+				Actions: cardActions,
+				MSTeams: &msTeams{Width: "Full"},
+			},
+		}},
+	}
+}
+
+// createItemContainer renders a single message item as a styled Adaptive Card container.
+func createItemContainer(item types.MessageItem) cardItem {
+	style := levelStyles[types.Unknown]
+	if int(item.Level) < len(levelStyles) {
+		style = levelStyles[item.Level]
+	}
+
+	container := cardItem{
+		Type:  "Container",
+		Style: style,
+		Bleed: true,
+		Items: []cardItem{{
+			Type: "TextBlock",
+			Text: item.Text,
+			Wrap: true,
+		}},
+	}
+
+	if len(item.Fields) > 0 {
+		fieldFacts := make([]cardFact, 0, len(item.Fields))
+		for _, field := range item.Fields {
+			fieldFacts = append(fieldFacts, cardFact{Title: field.Key, Value: field.Value})
+		}
+
+		container.Items = append(container.Items, cardItem{
+			Type:  "FactSet",
+			Facts: fieldFacts,
+		})
+	}
+
+	var subtitle []string
+	if item.Level != types.Unknown {
+		subtitle = append(subtitle, item.Level.String())
+	}
+
+	if !item.Timestamp.IsZero() {
+		subtitle = append(subtitle, item.Timestamp.UTC().Format(time.RFC3339))
+	}
+
+	if len(subtitle) > 0 {
+		container.Items = append(container.Items, cardItem{
+			Type:     "TextBlock",
+			Text:     strings.Join(subtitle, " | "),
+			Size:     "Small",
+			IsSubtle: true,
+			Wrap:     true,
+		})
+	}
+
+	return container
+}
+
+// createFacts converts a map into a list of facts, sorted by title.
+func createFacts(facts map[string]string) []cardFact {
+	titles := make([]string, 0, len(facts))
+	for title := range facts {
+		titles = append(titles, title)
+	}
+
+	sort.Strings(titles)
+
+	result := make([]cardFact, 0, len(titles))
+	for _, title := range titles {
+		result = append(result, cardFact{Title: title, Value: facts[title]})
+	}
+
+	return result
+}
@@ -16,9 +16,8 @@ const Scheme = "teams"
 
 // Config constants.
 const (
-	DummyURL           = "teams://dummy@dummy.com" // Default placeholder URL
-	ExpectedOrgMatches = 2                         // Full match plus organization domain capture group
-	MinPathComponents  = 3                         // Minimum required path components: AltID, GroupOwner, ExtraID
+	ExpectedOrgMatches = 2 // Full match plus organization domain capture group
+	MinPathComponents  = 3 // Minimum required path components: AltID, GroupOwner, ExtraID
 )
 
 // Config represents the configuration for the Teams service.
@@ -30,9 +29,13 @@ type Config struct {
 	GroupOwner string `optional:"" url:"path2"`
 	ExtraID    string `optional:"" url:"path3"`
 
-	Title string `key:"title" optional:""`
-	Color string `key:"color" optional:""`
-	Host  string `key:"host"  optional:""` // Required, no default
+	WorkflowID string `optional:"" desc:"Power Automate workflow ID, used instead of the webhook components"`
+	Signature  string `optional:"" desc:"Power Automate workflow signature (the sig query value)"`
+
+	Title   string         `key:"title"   optional:""`
+	Color   string         `key:"color"   optional:""`
+	Host    string         `key:"host"    optional:""` // Required, no default
+	Actions []ActionButton `key:"actions" optional:"" desc:"Action buttons for workflow cards, in the format url or url|title"`
 }
 
 // WebhookParts returns the webhook components as an array.
@@ -88,6 +91,15 @@ func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
 		return nil
 	}
 
+	if config.IsWorkflow() {
+		return &url.URL{
+			Host:     WorkflowMarker,
+			Path:     "/" + config.WorkflowID + "/" + config.Signature,
+			Scheme:   Scheme,
+			RawQuery: format.BuildQuery(resolver),
+		}
+	}
+
 	return &url.URL{
 		User:     url.User(config.Group),
 		Host:     config.Tenant,
@@ -108,6 +120,13 @@ func (config *Config) SetURL(url *url.URL) error {
 // It parses the URL parts, sets query parameters, and ensures the host is specified.
 // Returns an error if the URL is invalid or the host is missing.
 func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) error {
+	if url.Hostname() == WorkflowMarker {
+		return config.setWorkflowURL(resolver, url)
+	}
+
+	config.WorkflowID = ""
+	config.Signature = ""
+
 	parts, err := parseURLParts(url)
 	if err != nil {
 		return err
@@ -119,10 +138,7 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		return err
 	}
 
-	// Allow dummy URL during documentation generation
-	if config.Host == "" && (url.User != nil && url.User.Username() == "dummy") {
-		config.Host = "dummy.webhook.office.com"
-	} else if config.Host == "" {
+	if config.Host == "" {
 		return ErrMissingHostParameter
 	}
 
@@ -132,9 +148,6 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 // parseURLParts extracts and validates webhook components from a URL.
 func parseURLParts(url *url.URL) ([5]string, error) {
 	var parts [5]string
-	if url.String() == DummyURL {
-		return parts, nil
-	}
 
 	pathParts := strings.Split(url.Path, "/")
 	if pathParts[0] == "" {
@@ -160,12 +173,13 @@ func parseURLParts(url *url.URL) ([5]string, error) {
 }
 
 // setQueryParams applies query parameters to the Config using the resolver.
-// It resets Color, Host, and Title, then updates them based on query values.
+// It resets Color, Host, Title and Actions, then updates them based on query values.
 // Returns an error if the resolver fails to set any parameter.
 func (config *Config) setQueryParams(resolver types.ConfigQueryResolver, query url.Values) error {
 	config.Color = ""
 	config.Host = ""
 	config.Title = ""
+	config.Actions = nil
 
 	for key, vals := range query {
 		if len(vals) > 0 && vals[0] != "" {
@@ -47,4 +47,18 @@ var (
 
 	// ErrMissingExtraID indicates the extraID is missing.
 	ErrMissingExtraID = errors.New("extraID is required")
+
+	// ErrMissingWorkflowComponents indicates a workflow URL is missing the workflow ID or signature.
+	ErrMissingWorkflowComponents = errors.New(
+		"invalid workflow URL format: expected workflow ID and signature components",
+	)
+
+	// ErrInvalidWorkflowURL indicates the workflow webhook URL format is invalid.
+	ErrInvalidWorkflowURL = errors.New("invalid workflow webhook URL format")
+
+	// ErrMissingWorkflowSignature indicates the workflow webhook URL is missing the sig query parameter.
+	ErrMissingWorkflowSignature = errors.New("workflow webhook URL is missing the sig parameter")
+
+	// ErrInvalidActionFormat indicates an action button is missing its URL.
+	ErrInvalidActionFormat = errors.New("invalid action, should be in the format url or url|title")
 )
@@ -11,6 +11,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // MaxSummaryLength defines the maximum length for a notification summary.
@@ -28,6 +29,10 @@ type Service struct {
 
 // Send delivers a notification message to Microsoft Teams.
 func (service *Service) Send(message string, params *types.Params) error {
+	if service.Config.IsWorkflow() {
+		return service.sendWorkflow(itemsFromLines(message), params)
+	}
+
 	config := service.Config
 	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
 		service.Logf("Failed to update params: %v", err)
@@ -36,6 +41,50 @@ func (service *Service) Send(message string, params *types.Params) error {
 	return service.doSend(config, message)
 }
 
+// SendItems delivers message items to Microsoft Teams.
+// Workflow webhooks render each item in its own container, styled by the item level,
+// while legacy webhooks receive the items as plain text sections.
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	if service.Config.IsWorkflow() {
+		return service.sendWorkflow(items, params)
+	}
+
+	return service.Send(strings.TrimSuffix(types.ItemsToPlain(items), "\n"), params)
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The webhook signature is redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config := *service.Config
+
+	var (
+		postURL string
+		payload []byte
+		err     error
+	)
+
+	if config.IsWorkflow() {
+		postURL, payload, err = service.createWorkflowRequest(&config, itemsFromLines(message), params)
+	} else {
+		if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+			service.Logf("Failed to update params: %v", err)
+		}
+
+		postURL, payload, err = createMessageCard(&config, message)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := util.RedactedJSONRequest(postURL, json.RawMessage(payload), config.ExtraID, config.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
 // Initialize configures the service with a URL and logger.
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.SetLogger(logger)
@@ -59,6 +108,10 @@ func (service *Service) GetConfigURLFromCustom(customURL *url.URL) (*url.URL, er
 		return nil, fmt.Errorf("parsing custom URL %q: %w", webhookURLStr, err)
 	}
 
+	if isWorkflowWebhookURL(tempURL) {
+		return getWorkflowConfigURLFromCustom(tempURL)
+	}
+
 	webhookURL := &url.URL{
 		Scheme: tempURL.Scheme,
 		Host:   tempURL.Host,
@@ -90,8 +143,134 @@ func (service *Service) GetConfigURLFromCustom(customURL *url.URL) (*url.URL, er
 	return config.GetURL(), nil
 }
 
+// getWorkflowConfigURLFromCustom converts a workflow webhook URL to a service URL.
+// Query values other than the ones used by the workflow trigger are treated as service props.
+func getWorkflowConfigURLFromCustom(webhookURL *url.URL) (*url.URL, error) {
+	config, err := ConfigFromWorkflowURL(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := format.NewPropKeyResolver(config)
+
+	for key, vals := range webhookURL.Query() {
+		switch key {
+		case "api-version", "sp", "sv", "sig":
+			continue
+		}
+
+		if vals[0] == "" {
+			continue
+		}
+
+		if err := resolver.Set(key, vals[0]); err != nil {
+			return nil, fmt.Errorf("%w: key=%q, value=%q: %w", ErrSetParameterFailed, key, vals[0], err)
+		}
+	}
+
+	return config.GetURL(), nil
+}
+
+// sendWorkflow sends the message items as an Adaptive Card to the configured workflow webhook.
+func (service *Service) sendWorkflow(items []types.MessageItem, params *types.Params) error {
+	config := *service.Config
+
+	postURL, payload, err := service.createWorkflowRequest(&config, items, params)
+	if err != nil {
+		return err
+	}
+
+	res, err := safePost(postURL, payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSendFailed, err.Error())
+	}
+	defer res.Body.Close()
+
+	// Workflow triggers respond with 202 Accepted, as the flow is run asynchronously
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("%w: %s", ErrSendFailedStatus, res.Status)
+	}
+
+	return nil
+}
+
+// createWorkflowRequest updates the config with the params, returning the workflow URL and the Adaptive Card
+// payload for the message items. Params that do not correspond to a config key are added to the card as facts.
+func (service *Service) createWorkflowRequest(
+	config *Config,
+	items []types.MessageItem,
+	params *types.Params,
+) (string, []byte, error) {
+	facts := map[string]string{}
+
+	if params != nil {
+		configParams := types.Params{}
+		keys := make(map[string]bool)
+
+		for _, key := range service.pkr.QueryFields() {
+			keys[key] = true
+		}
+
+		for key, value := range *params {
+			if keys[strings.ToLower(key)] {
+				configParams[key] = value
+			} else {
+				facts[key] = value
+			}
+		}
+
+		if err := service.pkr.UpdateConfigFromParams(config, &configParams); err != nil {
+			service.Logf("Failed to update params: %v", err)
+		}
+	}
+
+	payload, err := json.Marshal(createWorkflowPayload(config.Title, items, facts, config.Actions))
+	if err != nil {
+		return "", nil, fmt.Errorf("marshaling payload to JSON: %w", err)
+	}
+
+	postURL := BuildWorkflowURL(config.Host, config.WorkflowID, config.Signature)
+	if postURL == "" {
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidHostFormat, config.Host)
+	}
+
+	return postURL, payload, nil
+}
+
+// itemsFromLines creates a message item for each line of the message.
+func itemsFromLines(message string) []types.MessageItem {
+	lines := strings.Split(message, "\n")
+	items := make([]types.MessageItem, 0, len(lines))
+
+	for _, line := range lines {
+		items = append(items, types.MessageItem{Text: line})
+	}
+
+	return items
+}
+
 // doSend sends the notification to Teams using the configured webhook URL.
 func (service *Service) doSend(config *Config, message string) error {
+	postURL, payload, err := createMessageCard(config, message)
+	if err != nil {
+		return err
+	}
+
+	res, err := safePost(postURL, payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSendFailed, err.Error())
+	}
+	defer res.Body.Close() // Move defer after error check
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrSendFailedStatus, res.Status)
+	}
+
+	return nil
+}
+
+// createMessageCard returns the webhook URL and the MessageCard payload used for sending the message.
+func createMessageCard(config *Config, message string) (string, []byte, error) {
 	lines := strings.Split(message, "\n")
 	sections := make([]section, 0, len(lines))
 
@@ -117,11 +296,11 @@ func (service *Service) doSend(config *Config, message string) error {
 		Sections:   sections,
 	})
 	if err != nil {
-		return fmt.Errorf("marshaling payload to JSON: %w", err)
+		return "", nil, fmt.Errorf("marshaling payload to JSON: %w", err)
 	}
 
 	if config.Host == "" {
-		return ErrMissingHost
+		return "", nil, ErrMissingHost
 	}
 
 	postURL := BuildWebhookURL(
@@ -135,20 +314,10 @@ func (service *Service) doSend(config *Config, message string) error {
 
 	// Validate URL before sending
 	if err := ValidateWebhookURL(postURL); err != nil {
-		return err
+		return "", nil, err
 	}
 
-	res, err := safePost(postURL, payload)
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrSendFailed, err.Error())
-	}
-	defer res.Body.Close() // Move defer after error check
-
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %s", ErrSendFailedStatus, res.Status)
-	}
-
-	return nil
+	return postURL, payload, nil
 }
 
 // safePost performs an HTTP POST with a pre-validated URL.
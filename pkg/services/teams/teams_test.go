@@ -1,14 +1,20 @@
 package teams
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"log"
+	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
 )
 
 const (
@@ -17,6 +23,14 @@ const (
 	scopedDomainHost = "test.webhook.office.com"
 	testURLBase      = "teams://11111111-4444-4444-8444-cccccccccccc@22222222-4444-4444-8444-cccccccccccc/33333301222222222233333333333344/44444444-4444-4444-8444-cccccccccccc/" + extraIDValue
 	scopedURLBase    = testURLBase + "?host=" + scopedDomainHost
+
+	workflowID        = "0123456789abcdef0123456789abcdef"
+	workflowSignature = "AbCdEfGhIjKlMnOpQrStUvWxYz0123456789-_AbCdE"
+	workflowHost      = "prod-00.westus.logic.azure.com"
+	workflowURLBase   = "teams://workflow/" + workflowID + "/" + workflowSignature + "?host=" + workflowHost
+	workflowWebhook   = "https://" + workflowHost + "/workflows/" + workflowID +
+		"/triggers/manual/paths/invoke?api-version=2016-06-01&sig=" + workflowSignature +
+		"&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0"
 )
 
 var logger = log.New(ginkgo.GinkgoWriter, "Test", log.LstdFlags)
@@ -143,6 +157,153 @@ var _ = ginkgo.Describe("the teams service", func() {
 			err = service.Send("Message", nil)
 			gomega.Expect(err).To(gomega.HaveOccurred())
 		})
+		ginkgo.It("should render the request with the extra ID redacted when doing a dry run", func() {
+			serviceURL, _ := url.Parse(scopedURLBase)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("Message", &types.Params{"title": "Alert"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).
+				To(gomega.Equal(strings.TrimSuffix(scopedWebhookURL, extraIDValue) + "REDACTED"))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"title":"Alert"`))
+			gomega.Expect(service.Config.Title).To(gomega.BeEmpty())
+		})
+	})
+
+	ginkgo.Describe("sending to a workflow webhook", func() {
+		var service Service
+		var card adaptiveCard
+		ginkgo.BeforeEach(func() {
+			httpmock.Activate()
+			card = adaptiveCard{}
+			httpmock.RegisterResponder(
+				"POST",
+				workflowWebhook,
+				func(req *http.Request) (*http.Response, error) {
+					body, err := io.ReadAll(req.Body)
+					gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+					payload := struct {
+						Type        string `json:"type"`
+						Attachments []struct {
+							ContentType string       `json:"contentType"`
+							Content     adaptiveCard `json:"content"`
+						} `json:"attachments"`
+					}{}
+					gomega.Expect(json.Unmarshal(body, &payload)).To(gomega.Succeed())
+					gomega.Expect(payload.Type).To(gomega.Equal("message"))
+					gomega.Expect(payload.Attachments).To(gomega.HaveLen(1))
+					gomega.Expect(payload.Attachments[0].ContentType).
+						To(gomega.Equal(AdaptiveCardContentType))
+					card = payload.Attachments[0].Content
+
+					return httpmock.NewStringResponse(http.StatusAccepted, ""), nil
+				},
+			)
+		})
+		ginkgo.AfterEach(func() {
+			httpmock.DeactivateAndReset()
+		})
+		ginkgo.It("should post an adaptive card with title, facts and actions", func() {
+			serviceURL, _ := url.Parse(
+				workflowURLBase + "&title=Alert&actions=https://example.com/run%7CRunbook",
+			)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			err := service.Send("Disk is full", &types.Params{"Server": "web01"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(card.Version).To(gomega.Equal(AdaptiveCardVersion))
+			gomega.Expect(card.Body).To(gomega.HaveLen(3))
+			gomega.Expect(card.Body[0].Text).To(gomega.Equal("Alert"))
+			gomega.Expect(card.Body[1].Items[0].Text).To(gomega.Equal("Disk is full"))
+			gomega.Expect(card.Body[2].Facts).To(gomega.Equal([]cardFact{{Title: "Server", Value: "web01"}}))
+			gomega.Expect(card.Actions).To(gomega.Equal([]cardAction{{
+				Type:  "Action.OpenUrl",
+				Title: "Runbook",
+				URL:   "https://example.com/run",
+			}}))
+		})
+		ginkgo.It("should style the item containers according to their level", func() {
+			serviceURL, _ := url.Parse(workflowURLBase)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			err := service.SendItems([]types.MessageItem{
+				{Text: "all good", Level: types.Info},
+				{Text: "on fire", Level: types.Error},
+			}, nil)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(card.Body).To(gomega.HaveLen(2))
+			gomega.Expect(card.Body[0].Style).To(gomega.Equal("accent"))
+			gomega.Expect(card.Body[1].Style).To(gomega.Equal("attention"))
+			gomega.Expect(card.Body[1].Items[1].Text).To(gomega.Equal("Error"))
+		})
+		ginkgo.It("should render the request with the signature redacted when doing a dry run", func() {
+			serviceURL, _ := url.Parse(workflowURLBase)
+			gomega.Expect(service.Initialize(serviceURL, logger)).To(gomega.Succeed())
+
+			requests, err := service.DryRun("Disk is full", &types.Params{"Server": "web01"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).To(gomega.ContainSubstring("sig=REDACTED"))
+			gomega.Expect(requests[0].URL).NotTo(gomega.ContainSubstring(workflowSignature))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"value":"web01"`))
+		})
+	})
+
+	ginkgo.Describe("the workflow config", func() {
+		ginkgo.It("should be identical after de-/serialization", func() {
+			testURL := workflowURLBase + "&title=Test+title"
+			serviceURL, err := url.Parse(testURL)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			config := &Config{}
+			gomega.Expect(config.SetURL(serviceURL)).To(gomega.Succeed())
+			gomega.Expect(config.IsWorkflow()).To(gomega.BeTrue())
+			gomega.Expect(config.GetURL().String()).
+				To(gomega.Equal("teams://workflow/" + workflowID + "/" + workflowSignature +
+					"?host=" + workflowHost + "&title=Test+title"))
+		})
+		ginkgo.It("should reject workflow URLs missing the signature", func() {
+			serviceURL, _ := url.Parse("teams://workflow/" + workflowID + "?host=" + workflowHost)
+			gomega.Expect((&Config{}).SetURL(serviceURL)).
+				To(gomega.MatchError(ErrMissingWorkflowComponents))
+		})
+		ginkgo.It("should reject hosts that are not workflow hosts", func() {
+			serviceURL, _ := url.Parse(
+				"teams://workflow/" + workflowID + "/" + workflowSignature + "?host=example.com",
+			)
+			gomega.Expect((&Config{}).SetURL(serviceURL)).To(gomega.HaveOccurred())
+		})
+		ginkgo.It("should convert a custom workflow URL to a service URL", func() {
+			service := Service{}
+			customURL, err := url.Parse("teams+" + workflowWebhook + "&title=TheTitle")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			serviceURL, err := service.GetConfigURLFromCustom(customURL)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(serviceURL.String()).To(gomega.Equal(workflowURLBase + "&title=TheTitle"))
+		})
+		ginkgo.It("should build the webhook URL for power platform hosts", func() {
+			host := "default0123.45.environment.api.powerplatform.com"
+			gomega.Expect(BuildWorkflowURL(host, workflowID, "sig")).To(gomega.Equal(
+				"https://" + host + "/powerautomate/automations/direct/workflows/" + workflowID +
+					"/triggers/manual/paths/invoke?api-version=1&sig=sig&sp=%2Ftriggers%2Fmanual%2Frun&sv=1.0",
+			))
+
+			webhookURL, err := url.Parse(BuildWorkflowURL(host, workflowID, "sig"))
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			config, err := ConfigFromWorkflowURL(webhookURL)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(config.Host).To(gomega.Equal(host))
+			gomega.Expect(config.WorkflowID).To(gomega.Equal(workflowID))
+			gomega.Expect(config.Signature).To(gomega.Equal("sig"))
+		})
 	})
 
 	ginkgo.It("should return the correct service ID", func() {
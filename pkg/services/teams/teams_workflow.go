@@ -0,0 +1,140 @@
+package teams
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// Workflow constants.
+const (
+	// WorkflowMarker is the service URL host used to denote a Power Automate workflow webhook.
+	WorkflowMarker = "workflow"
+	// WorkflowComponents is the number of path components in a workflow service URL: WorkflowID and Signature.
+	WorkflowComponents = 2
+
+	logicAppsDomain     = ".logic.azure.com"
+	powerPlatformDomain = ".api.powerplatform.com"
+	workflowTriggerPath = "triggers/manual/paths/invoke"
+	workflowTriggerSP   = "/triggers/manual/run"
+	workflowVersion     = "1.0"
+	logicAppsAPIVersion = "2016-06-01"
+	powerPlatformAPIVer = "1"
+)
+
+var (
+	// WorkflowHostValidator ensures the host matches a Power Automate workflow domain.
+	WorkflowHostValidator = regexp.MustCompile(
+		`^[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)*(\.logic\.azure\.com|\.api\.powerplatform\.com)$`,
+	)
+	// workflowPathPattern extracts the workflow ID from both Logic Apps and Power Platform webhook paths.
+	workflowPathPattern = regexp.MustCompile(
+		`^/(?:powerautomate/automations/direct/)?workflows/([0-9a-zA-Z]+)/triggers/manual/paths/invoke/?$`,
+	)
+)
+
+// IsWorkflow returns whether the config targets a Power Automate workflow webhook
+// rather than a legacy Office 365 connector webhook.
+func (config *Config) IsWorkflow() bool {
+	return config.WorkflowID != ""
+}
+
+// setWorkflowURL updates the Config from a workflow service URL using the provided resolver.
+// The expected format is teams://workflow/workflowID/signature?host=workflow.host.
+func (config *Config) setWorkflowURL(resolver types.ConfigQueryResolver, serviceURL *url.URL) error {
+	pathParts := strings.Split(strings.Trim(serviceURL.Path, "/"), "/")
+	if len(pathParts) != WorkflowComponents || pathParts[0] == "" || pathParts[1] == "" {
+		return ErrMissingWorkflowComponents
+	}
+
+	config.setFromWebhookParts([5]string{})
+	config.WorkflowID = pathParts[0]
+	config.Signature = pathParts[1]
+
+	if err := config.setQueryParams(resolver, serviceURL.Query()); err != nil {
+		return err
+	}
+
+	if config.Host == "" {
+		return ErrMissingHostParameter
+	}
+
+	if !WorkflowHostValidator.MatchString(config.Host) {
+		return fmt.Errorf("%w: %q", ErrInvalidHostFormat, config.Host)
+	}
+
+	return nil
+}
+
+// ParseWorkflowURL extracts the host, workflow ID and signature from a Power Automate workflow webhook URL.
+func ParseWorkflowURL(webhookURL *url.URL) (string, string, string, error) {
+	host := webhookURL.Hostname()
+	if !WorkflowHostValidator.MatchString(host) {
+		return "", "", "", fmt.Errorf("%w: %q", ErrInvalidHostFormat, host)
+	}
+
+	groups := workflowPathPattern.FindStringSubmatch(webhookURL.Path)
+	if len(groups) != WorkflowComponents {
+		return "", "", "", fmt.Errorf("%w: %q", ErrInvalidWorkflowURL, webhookURL.Path)
+	}
+
+	signature := webhookURL.Query().Get("sig")
+	if signature == "" {
+		return "", "", "", ErrMissingWorkflowSignature
+	}
+
+	return host, groups[1], signature, nil
+}
+
+// ConfigFromWorkflowURL creates a new Config from a Power Automate workflow webhook URL.
+func ConfigFromWorkflowURL(webhookURL *url.URL) (*Config, error) {
+	host, workflowID, signature, err := ParseWorkflowURL(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Config{
+		Host:       host,
+		WorkflowID: workflowID,
+		Signature:  signature,
+	}, nil
+}
+
+// BuildWorkflowURL constructs a Power Automate workflow webhook URL from its components.
+func BuildWorkflowURL(host, workflowID, signature string) string {
+	if !WorkflowHostValidator.MatchString(host) {
+		return "" // Will trigger ErrInvalidWorkflowURL in caller
+	}
+
+	query := url.Values{}
+	query.Set("sp", workflowTriggerSP)
+	query.Set("sv", workflowVersion)
+	query.Set("sig", signature)
+
+	path := "/workflows/" + workflowID + "/" + workflowTriggerPath
+
+	if strings.HasSuffix(host, powerPlatformDomain) {
+		path = "/powerautomate/automations/direct" + path
+
+		query.Set("api-version", powerPlatformAPIVer)
+	} else {
+		query.Set("api-version", logicAppsAPIVersion)
+	}
+
+	return (&url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+// isWorkflowWebhookURL returns whether the webhook URL points to a Power Automate workflow.
+func isWorkflowWebhookURL(webhookURL *url.URL) bool {
+	host := webhookURL.Hostname()
+
+	return strings.HasSuffix(host, logicAppsDomain) || strings.HasSuffix(host, powerPlatformDomain)
+}
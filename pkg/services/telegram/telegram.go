@@ -8,17 +8,22 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
 // apiFormat defines the Telegram API endpoint template.
 const (
-	apiFormat = "https://api.telegram.org/bot%s/%s"
-	maxlength = 4096
+	apiFormat        = "https://api.telegram.org/bot%s/%s"
+	maxlength        = 4096
+	maxCaptionLength = 1024
 )
 
 // ErrMessageTooLong indicates that the message exceeds the maximum allowed length.
 var (
-	ErrMessageTooLong = errors.New("Message exceeds the max length")
+	ErrMessageTooLong     = errors.New("Message exceeds the max length")
+	ErrCaptionTooLong     = errors.New("Message exceeds the max caption length")
+	ErrUnexpectedResponse = errors.New("unexpected response from the telegram API")
 )
 
 // Service sends notifications to configured Telegram chats.
@@ -28,18 +33,134 @@ type Service struct {
 	pkr    format.PropKeyResolver
 }
 
+// MessageRef identifies a message that has been sent to a chat.
+type MessageRef struct {
+	Chat      string
+	MessageID int64
+}
+
 // Send delivers a notification message to Telegram.
 func (service *Service) Send(message string, params *types.Params) error {
+	_, err := service.PostMessage(message, params)
+
+	return err
+}
+
+// PostMessage delivers a notification message to all the configured chats,
+// returning a reference to the message sent to each of them.
+func (service *Service) PostMessage(message string, params *types.Params) ([]MessageRef, error) {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.sendMessageForChatIDs(message, config)
+}
+
+// DryRun renders the requests used for sending the message to each chat, without sending them.
+// The bot token is redacted from the request URLs. Local files are not read, the request
+// body contains their path instead of the uploaded content.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{token: config.Token}
+	requests := make([]types.Request, 0, len(config.Chats))
+
+	for _, chat := range config.Chats {
+		var (
+			endpoint = "sendMessage"
+			payload  any
+		)
+
+		if config.Photo != "" || config.Document != "" {
+			media := createSendMediaPayload(message, chat, config)
+			endpoint, _, _ = media.endpoint()
+			payload = &media
+		} else {
+			text := createSendMessagePayload(message, chat, config)
+			payload = &text
+		}
+
+		request, err := util.RedactedJSONRequest(client.apiURL(endpoint), payload, config.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// messageConfig returns a copy of the config updated with the params, after validating that
+// the message can be sent using it.
+func (service *Service) messageConfig(message string, params *types.Params) (*Config, error) {
+	if len(message) > maxlength {
+		return nil, ErrMessageTooLong
+	}
+
+	config := *service.Config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return nil, fmt.Errorf("updating config from params: %w", err)
+	}
+
+	if config.Photo != "" && config.Document != "" {
+		return nil, ErrMediaConflict
+	}
+
+	if (config.Photo != "" || config.Document != "") && len(message) > maxCaptionLength {
+		return nil, ErrCaptionTooLong
+	}
+
+	return &config, nil
+}
+
+// Check verifies the bot token using the getMe endpoint, without sending a message.
+func (service *Service) Check() (types.CheckResult, error) {
+	client := &Client{token: service.Config.Token}
+
+	bot, err := client.GetBotInfo()
+	if err != nil {
+		var apiErr *responseError
+
+		reachable := errors.As(err, &apiErr) || jsonclient.StatusCode(err) != 0
+
+		return types.CheckResult{Reachable: reachable}, fmt.Errorf("getting bot info: %w", err)
+	}
+
+	return types.CheckResult{Reachable: true, Authenticated: true, Identity: "@" + bot.Username}, nil
+}
+
+// EditMessage replaces the text of a previously sent message.
+// Only messages containing text can be edited, messages sent with a photo or document cannot.
+func (service *Service) EditMessage(ref MessageRef, message string, params *types.Params) error {
 	if len(message) > maxlength {
 		return ErrMessageTooLong
 	}
 
+	if ref.MessageID == 0 {
+		return ErrMissingMessageID
+	}
+
 	config := *service.Config
 	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return fmt.Errorf("updating config from params: %w", err)
 	}
 
-	return service.sendMessageForChatIDs(message, &config)
+	payload := createSendMessagePayload(message, ref.Chat, &config)
+	payload.MessageID = ref.MessageID
+	payload.MessageThreadID = nil
+	payload.ReplyTo = 0
+
+	client := &Client{token: config.Token}
+	if _, err := client.EditMessageText(&payload); err != nil {
+		return fmt.Errorf("editing message: %w", err)
+	}
+
+	return nil
 }
 
 // Initialize configures the service with a URL and logger.
@@ -64,14 +185,24 @@ func (service *Service) GetID() string {
 }
 
 // sendMessageForChatIDs sends the message to all configured chat IDs.
-func (service *Service) sendMessageForChatIDs(message string, config *Config) error {
-	for _, chat := range service.Config.Chats {
-		if err := sendMessageToAPI(message, chat, config); err != nil {
-			return err
+func (service *Service) sendMessageForChatIDs(message string, config *Config) ([]MessageRef, error) {
+	refs := make([]MessageRef, 0, len(config.Chats))
+
+	for _, chat := range config.Chats {
+		sent, err := sendMessageToAPI(message, chat, config)
+		if err != nil {
+			return refs, err
+		}
+
+		ref := MessageRef{Chat: chat}
+		if sent != nil {
+			ref.MessageID = sent.MessageID
 		}
+
+		refs = append(refs, ref)
 	}
 
-	return nil
+	return refs, nil
 }
 
 // GetConfig returns the current configuration for the service.
@@ -80,10 +211,17 @@ func (service *Service) GetConfig() *Config {
 }
 
 // sendMessageToAPI sends a message to the Telegram API for a specific chat.
-func sendMessageToAPI(message string, chat string, config *Config) error {
+// When a photo or document is configured, it is sent with the message as its caption.
+func sendMessageToAPI(message string, chat string, config *Config) (*Message, error) {
 	client := &Client{token: config.Token}
+
+	if config.Photo != "" || config.Document != "" {
+		payload := createSendMediaPayload(message, chat, config)
+
+		return client.SendMedia(&payload)
+	}
+
 	payload := createSendMessagePayload(message, chat, config)
-	_, err := client.SendMessage(&payload)
 
-	return err
+	return client.SendMessage(&payload)
 }
@@ -1,12 +1,22 @@
 package telegram
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
 )
 
+// localFileScheme marks media sources referring to local files, which are uploaded.
+const localFileScheme = "file://"
+
 // Client for Telegram API.
 type Client struct {
 	token string
@@ -22,7 +32,7 @@ func (c *Client) GetBotInfo() (*User, error) {
 	err := jsonclient.Get(c.apiURL("getMe"), response)
 
 	if !response.OK {
-		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
+		return nil, errorFromResponse(err)
 	}
 
 	return &response.Result, nil
@@ -63,6 +73,62 @@ func (c *Client) SendMessage(message *SendMessagePayload) (*Message, error) {
 	return response.Result, nil
 }
 
+// EditMessageText replaces the text of the message specified by the chat and message ID of the payload.
+func (c *Client) EditMessageText(message *SendMessagePayload) (*Message, error) {
+	response := &messageResponse{}
+	err := jsonclient.Post(c.apiURL("editMessageText"), message, response)
+
+	if !response.OK {
+		return nil, errorFromResponse(err)
+	}
+
+	return response.Result, nil
+}
+
+// SendMedia sends the photo or document specified in the payload.
+// Media referring to a local file using a file:// URL is uploaded, while any other value is passed on
+// as a URL or file ID.
+func (c *Client) SendMedia(media *SendMediaPayload) (*Message, error) {
+	endpoint, field, source := media.endpoint()
+	response := &messageResponse{}
+
+	path, isLocal := localFilePath(source)
+	if !isLocal {
+		err := jsonclient.Post(c.apiURL(endpoint), media, response)
+		if !response.OK {
+			return nil, errorFromResponse(err)
+		}
+
+		return response.Result, nil
+	}
+
+	body, contentType, err := createMediaUpload(media, field, path)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.Post(c.apiURL(endpoint), contentType, body) //nolint:gosec,noctx
+	if err != nil {
+		return nil, fmt.Errorf("uploading %s: %w", field, err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if err := json.Unmarshal(resBody, response); err != nil || !response.OK {
+		if apiErr := GetErrorResponse(string(resBody)); apiErr != nil {
+			return nil, apiErr
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedResponse, res.Status)
+	}
+
+	return response.Result, nil
+}
+
 // GetErrorResponse retrieves the error message from a failed request.
 func GetErrorResponse(body string) error {
 	response := &responseError{}
@@ -72,3 +138,79 @@ func GetErrorResponse(body string) error {
 
 	return nil
 }
+
+// errorFromResponse returns the API error from the body of a failed request, or the request error itself.
+func errorFromResponse(err error) error {
+	if apiErr := GetErrorResponse(jsonclient.ErrorBody(err)); apiErr != nil {
+		return apiErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ErrUnexpectedResponse
+}
+
+// localFilePath returns the path of the local file that the media source refers to. Only sources using the
+// file scheme refer to local files, so that other values, like ones supplied through params, are never read.
+func localFilePath(source string) (string, bool) {
+	return strings.CutPrefix(source, localFileScheme)
+}
+
+// createMediaUpload creates a multipart/form-data request body for the payload, with the local file
+// at path as the media field. It returns the body together with the content type, which includes the boundary.
+func createMediaUpload(media *SendMediaPayload, field string, path string) (*bytes.Buffer, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("opening %s: %w", field, err)
+	}
+	defer file.Close()
+
+	// Encode the payload as JSON first, so that the API field names and omitted values are kept
+	payloadJSON, err := json.Marshal(media)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshaling payload to JSON: %w", err)
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(payloadJSON, &fields); err != nil {
+		return nil, "", fmt.Errorf("unmarshaling payload fields: %w", err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for key, raw := range fields {
+		if key == field {
+			continue
+		}
+
+		// Strings are sent as is, while any other values are sent as their JSON representation
+		value := string(raw)
+
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			value = str
+		}
+
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, "", fmt.Errorf("writing field %q: %w", key, err)
+		}
+	}
+
+	part, err := writer.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return nil, "", fmt.Errorf("creating form file: %w", err)
+	}
+
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, "", fmt.Errorf("writing form file: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	return body, writer.FormDataContentType(), nil
+}
@@ -17,18 +17,27 @@ const (
 
 // ErrInvalidToken indicates an invalid Telegram token format or content.
 var (
-	ErrInvalidToken      = errors.New("invalid telegram token")
-	ErrNoChannelsDefined = errors.New("no channels defined in config URL")
+	ErrInvalidToken        = errors.New("invalid telegram token")
+	ErrNoChannelsDefined   = errors.New("no channels defined in config URL")
+	ErrInvalidButtonFormat = errors.New("invalid button format, expected url|text")
+	ErrMediaConflict       = errors.New("only one of photo and document can be sent")
+	ErrMissingMessageID    = errors.New("missing message ID")
 )
 
 // Config holds settings for the Telegram notification service.
 type Config struct {
-	Token        string    `url:"user"`
-	Preview      bool      `           default:"Yes"  desc:"If disabled, no web page preview will be displayed for URLs" key:"preview"`
-	Notification bool      `           default:"Yes"  desc:"If disabled, sends Message silently"                         key:"notification"`
-	ParseMode    parseMode `           default:"None" desc:"How the text Message should be parsed"                       key:"parsemode"`
-	Chats        []string  `                          desc:"Chat IDs or Channel names (using @channel-name)"             key:"chats,channels"`
-	Title        string    `           default:""     desc:"Notification title, optionally set by the sender"            key:"title"`
+	Token        string         `url:"user"`
+	Preview      bool           `           default:"Yes"  desc:"If disabled, no web page preview will be displayed for URLs" key:"preview"`
+	Notification bool           `           default:"Yes"  desc:"If disabled, sends Message silently"                         key:"notification"`
+	ParseMode    parseMode      `           default:"None" desc:"How the text Message should be parsed"                       key:"parsemode"`
+	Chats        []string       `                          desc:"Chat IDs or Channel names (using @channel-name)"             key:"chats,channels"`
+	Title        string         `           default:""     desc:"Notification title, optionally set by the sender"            key:"title"`
+	Thread       int            `           default:"0"    desc:"Forum topic thread ID for chats that do not specify one"     key:"thread,topic"`
+	Buttons      []InlineButton `                          desc:"Inline URL buttons, in the format url|text"                  key:"buttons"        optional:""`
+	ReplyTo      int64          `           default:"0"    desc:"ID of the message to reply to"                               key:"replyto"`
+	Protect      bool           `           default:"No"   desc:"If enabled, the message cannot be forwarded or saved"        key:"protect"`
+	Photo        string         `           default:""     desc:"URL, file ID or file:// URL of a local photo to send"          key:"photo"`
+	Document     string         `           default:""     desc:"URL, file ID or file:// URL of a local document to send"       key:"document"`
 }
 
 // Enums returns the fields that use an EnumFormatter for their values.
@@ -70,10 +79,8 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 	password, _ := url.User.Password()
 
 	token := url.User.Username() + ":" + password
-	if url.String() != "telegram://dummy@dummy.com" {
-		if !IsTokenValid(token) {
-			return fmt.Errorf("%w: %s", ErrInvalidToken, token)
-		}
+	if !IsTokenValid(token) {
+		return fmt.Errorf("%w: %s", ErrInvalidToken, token)
 	}
 
 	for key, vals := range url.Query() {
@@ -82,10 +89,8 @@ func (config *Config) setURL(resolver types.ConfigQueryResolver, url *url.URL) e
 		}
 	}
 
-	if url.String() != "telegram://dummy@dummy.com" {
-		if len(config.Chats) < 1 {
-			return ErrNoChannelsDefined
-		}
+	if len(config.Chats) < 1 {
+		return ErrNoChannelsDefined
 	}
 
 	config.Token = token
@@ -17,8 +17,61 @@ type SendMessagePayload struct {
 	DisableNotification bool         `json:"disable_notification"`
 	ReplyMarkup         *replyMarkup `json:"reply_markup,omitempty"`
 	Entities            []entity     `json:"entities,omitempty"`
-	ReplyTo             int64        `json:"reply_to_message_id"`
+	ReplyTo             int64        `json:"reply_to_message_id,omitempty"`
 	MessageID           int64        `json:"message_id,omitempty"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+}
+
+// SendMediaPayload is the payload used for sending a photo or document, with the message as its caption.
+type SendMediaPayload struct {
+	ID                  string       `json:"chat_id"`
+	MessageThreadID     *int         `json:"message_thread_id,omitempty"`
+	Photo               string       `json:"photo,omitempty"`
+	Document            string       `json:"document,omitempty"`
+	Caption             string       `json:"caption,omitempty"`
+	ParseMode           string       `json:"parse_mode,omitempty"`
+	DisableNotification bool         `json:"disable_notification"`
+	ProtectContent      bool         `json:"protect_content,omitempty"`
+	ReplyMarkup         *replyMarkup `json:"reply_markup,omitempty"`
+	ReplyTo             int64        `json:"reply_to_message_id,omitempty"`
+}
+
+// endpoint returns the API endpoint used for sending the media, together with the name of
+// the media field and its value.
+func (media *SendMediaPayload) endpoint() (string, string, string) {
+	if media.Document != "" {
+		return "sendDocument", "document", media.Document
+	}
+
+	return "sendPhoto", "photo", media.Photo
+}
+
+// InlineButton is an inline keyboard button that opens a URL when pressed.
+type InlineButton struct {
+	URL  string
+	Text string
+}
+
+// SetFromProp deserializes an inline button from a string in the format "url" or "url|text".
+func (b *InlineButton) SetFromProp(propValue string) error {
+	target, text, _ := strings.Cut(propValue, "|")
+	if target == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidButtonFormat, propValue)
+	}
+
+	b.URL = target
+	b.Text = text
+
+	return nil
+}
+
+// GetPropValue serializes an inline button back into a string in the format "url" or "url|text".
+func (b *InlineButton) GetPropValue() (string, error) {
+	if b.Text == "" {
+		return b.URL, nil
+	}
+
+	return b.URL + "|" + b.Text, nil
 }
 
 // Message represents one chat message.
@@ -135,11 +188,11 @@ type Chat struct {
 
 type inlineKey struct {
 	Text                     string `json:"text"`
-	URL                      string `json:"url"`
-	LoginURL                 string `json:"login_url"`
-	CallbackData             string `json:"callback_data"`
-	SwitchInlineQuery        string `json:"switch_inline_query"`
-	SwitchInlineQueryCurrent string `json:"switch_inline_query_current_chat"`
+	URL                      string `json:"url,omitempty"`
+	LoginURL                 string `json:"login_url,omitempty"`
+	CallbackData             string `json:"callback_data,omitempty"`
+	SwitchInlineQuery        string `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrent string `json:"switch_inline_query_current_chat,omitempty"`
 }
 
 type replyMarkup struct {
@@ -197,6 +250,45 @@ func (c *Chat) Name() string {
 }
 
 func createSendMessagePayload(message string, channel string, config *Config) SendMessagePayload {
+	chatID, threadID := parseChat(channel, config)
+	text, parseMode := formatText(message, config)
+
+	return SendMessagePayload{
+		Text:                text,
+		ID:                  chatID,
+		MessageThreadID:     threadID,
+		ParseMode:           parseMode,
+		DisableNotification: !config.Notification,
+		DisablePreview:      !config.Preview,
+		ReplyMarkup:         createReplyMarkup(config.Buttons),
+		ReplyTo:             config.ReplyTo,
+		ProtectContent:      config.Protect,
+	}
+}
+
+// createSendMediaPayload creates the payload for sending the configured photo or document to the channel,
+// using the message as its caption.
+func createSendMediaPayload(message string, channel string, config *Config) SendMediaPayload {
+	chatID, threadID := parseChat(channel, config)
+	caption, parseMode := formatText(message, config)
+
+	return SendMediaPayload{
+		ID:                  chatID,
+		MessageThreadID:     threadID,
+		Photo:               config.Photo,
+		Document:            config.Document,
+		Caption:             caption,
+		ParseMode:           parseMode,
+		DisableNotification: !config.Notification,
+		ProtectContent:      config.Protect,
+		ReplyMarkup:         createReplyMarkup(config.Buttons),
+		ReplyTo:             config.ReplyTo,
+	}
+}
+
+// parseChat splits the channel into the chat ID and the optional message thread ID, in the format "chat:thread".
+// When the channel does not specify a thread, the thread from the config is used, if any.
+func parseChat(channel string, config *Config) (string, *int) {
 	var threadID *int
 
 	chatID, thread, ok := strings.Cut(channel, ":")
@@ -204,15 +296,17 @@ func createSendMessagePayload(message string, channel string, config *Config) Se
 		if parsed, err := strconv.Atoi(thread); err == nil {
 			threadID = &parsed
 		}
+	} else if config.Thread != 0 {
+		thread := config.Thread
+		threadID = &thread
 	}
 
-	payload := SendMessagePayload{
-		Text:                message,
-		ID:                  chatID,
-		MessageThreadID:     threadID,
-		DisableNotification: !config.Notification,
-		DisablePreview:      !config.Preview,
-	}
+	return chatID, threadID
+}
+
+// formatText returns the text of the message, prepended by the title, together with the parse mode to use.
+func formatText(message string, config *Config) (string, string) {
+	text := message
 
 	parseMode := config.ParseMode
 	if config.ParseMode == ParseModes.None && config.Title != "" {
@@ -221,14 +315,34 @@ func createSendMessagePayload(message string, channel string, config *Config) Se
 		message = html.EscapeString(message)
 	}
 
-	if parseMode != ParseModes.None {
-		payload.ParseMode = parseMode.String()
-	}
-
 	// only HTML parse mode is supported for titles
 	if parseMode == ParseModes.HTML {
-		payload.Text = fmt.Sprintf("<b>%v</b>\n%v", html.EscapeString(config.Title), message)
+		text = fmt.Sprintf("<b>%v</b>\n%v", html.EscapeString(config.Title), message)
+	}
+
+	if parseMode == ParseModes.None {
+		return text, ""
+	}
+
+	return text, parseMode.String()
+}
+
+// createReplyMarkup creates an inline keyboard with a row for each of the buttons.
+func createReplyMarkup(buttons []InlineButton) *replyMarkup {
+	if len(buttons) < 1 {
+		return nil
+	}
+
+	keyboard := make([][]inlineKey, 0, len(buttons))
+
+	for _, button := range buttons {
+		text := button.Text
+		if text == "" {
+			text = button.URL
+		}
+
+		keyboard = append(keyboard, []inlineKey{{Text: text, URL: button.URL}})
 	}
 
-	return payload
+	return &replyMarkup{InlineKeyboard: keyboard}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // Constants for the WeCom service configuration and limits.
@@ -42,20 +43,49 @@ type Service struct {
 
 // Send delivers a notification message to WeCom.
 func (service *Service) Send(message string, params *types.Params) error {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.doSend(config, message, params)
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The webhook key is redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	body := service.getRequestBody(message, config, params)
+
+	request, err := util.RedactedJSONRequest(fmt.Sprintf(apiURL, config.Key), body, config.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return []types.Request{request}, nil
+}
+
+// messageConfig returns a copy of the config updated with the params, after validating that
+// the message can be sent using it.
+func (service *Service) messageConfig(message string, params *types.Params) (Config, error) {
 	if len(message) > maxLength {
-		return ErrLargeMessage
+		return Config{}, ErrLargeMessage
 	}
 
 	config := *service.Config
 	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
-		return fmt.Errorf("updating params: %w", err)
+		return Config{}, fmt.Errorf("updating params: %w", err)
 	}
 
 	if config.Key == "" {
-		return ErrKeyRequired
+		return Config{}, ErrKeyRequired
 	}
 
-	return service.doSend(config, message, params)
+	return config, nil
 }
 
 // Initialize configures the service with a URL and logger.
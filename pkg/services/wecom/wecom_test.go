@@ -194,6 +194,19 @@ var _ = ginkgo.Describe("WeCom Test", func() {
 				gomega.Expect(err).
 					To(gomega.MatchError(gomega.ContainSubstring("unexpected status 400")))
 			})
+
+			ginkgo.It("should render the request with the key redacted when doing a dry run", func() {
+				err := service.Initialize(testutils.URLMust(fullURL), logger)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+				requests, err := service.DryRun("message", nil)
+				gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+				gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+				gomega.Expect(requests).To(gomega.HaveLen(1))
+				gomega.Expect(requests[0].URL).
+					To(gomega.Equal("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=REDACTED"))
+				gomega.Expect(requests[0].Body).To(gomega.ContainSubstring(`"content":"message"`))
+			})
 		})
 	})
 })
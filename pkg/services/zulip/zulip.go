@@ -1,6 +1,7 @@
 package zulip
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/services/standard"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
 )
 
 // contentMaxSize defines the maximum allowed message size in bytes.
@@ -39,6 +41,42 @@ type Service struct {
 
 // Send delivers a notification message to Zulip.
 func (service *Service) Send(message string, params *types.Params) error {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return err
+	}
+
+	return service.doSend(config, message)
+}
+
+// DryRun renders the request used for sending the message, without sending it.
+// The bot credentials are redacted from the request URL.
+func (service *Service) DryRun(message string, params *types.Params) ([]types.Request, error) {
+	config, err := service.messageConfig(message, params)
+	if err != nil {
+		return nil, err
+	}
+
+	body := CreatePayload(config, message).Encode()
+
+	req, err := http.NewRequestWithContext(
+		context.Background(),
+		http.MethodPost,
+		service.getAPIURL(config),
+		strings.NewReader(body),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return []types.Request{util.RedactedRequest(req, []byte(body), config.BotKey)}, nil
+}
+
+// messageConfig returns a clone of the config updated with the stream and topic params, after validating
+// that the message can be sent using it.
+func (service *Service) messageConfig(message string, params *types.Params) (*Config, error) {
 	// Clone the config to avoid modifying the original for this send operation.
 	config := service.Config.Clone()
 
@@ -54,12 +92,12 @@ func (service *Service) Send(message string, params *types.Params) error {
 
 	topicLength := len([]rune(config.Topic))
 	if topicLength > topicMaxLength {
-		return fmt.Errorf("%w: %d characters, got %d", ErrTopicTooLong, topicMaxLength, topicLength)
+		return nil, fmt.Errorf("%w: %d characters, got %d", ErrTopicTooLong, topicMaxLength, topicLength)
 	}
 
 	messageSize := len(message)
 	if messageSize > contentMaxSize {
-		return fmt.Errorf(
+		return nil, fmt.Errorf(
 			"%w: %d bytes, got %d bytes",
 			ErrMessageTooLong,
 			contentMaxSize,
@@ -67,7 +105,7 @@ func (service *Service) Send(message string, params *types.Params) error {
 		)
 	}
 
-	return service.doSend(config, message)
+	return config, nil
 }
 
 // Initialize configures the service with a URL and logger.
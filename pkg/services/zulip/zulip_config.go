@@ -70,18 +70,16 @@ func (config *Config) setURL(_ types.ConfigQueryResolver, serviceURL *url.URL) e
 	config.BotKey, isSet = serviceURL.User.Password()
 	config.Host = serviceURL.Hostname()
 
-	if serviceURL.String() != "zulip://dummy@dummy.com" {
-		if config.BotMail == "" {
-			return ErrMissingBotMail
-		}
-
-		if !isSet {
-			return ErrMissingAPIKey
-		}
-
-		if config.Host == "" {
-			return ErrMissingHost
-		}
+	if config.BotMail == "" {
+		return ErrMissingBotMail
+	}
+
+	if !isSet {
+		return ErrMissingAPIKey
+	}
+
+	if config.Host == "" {
+		return ErrMissingHost
 	}
 
 	config.Stream = serviceURL.Query().Get("stream")
@@ -257,6 +257,30 @@ var _ = ginkgo.Describe("the zulip service", func() {
 				"failed to send zulip message: response status code unexpected: 400",
 			))
 		})
+		ginkgo.It("should render the request with the bot key redacted when doing a dry run", func() {
+			zulipURL := createZulipURL(
+				"bot-name@zulipchat.com",
+				"correcthorsebatterystable",
+				"example.zulipchat.com",
+				"foo",
+				"bar",
+			)
+			err := service.Initialize(zulipURL, testutils.TestLogger())
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			requests, err := service.DryRun("test message", &types.Params{"topic": "baz"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(httpmock.GetTotalCallCount()).To(gomega.Equal(0))
+			gomega.Expect(requests).To(gomega.HaveLen(1))
+			gomega.Expect(requests[0].URL).NotTo(gomega.ContainSubstring("correcthorsebatterystable"))
+			gomega.Expect(requests[0].URL).To(gomega.ContainSubstring("REDACTED"))
+			gomega.Expect(requests[0].URL).To(gomega.ContainSubstring("example.zulipchat.com/api/v1/messages"))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring("topic=baz"))
+			gomega.Expect(requests[0].Body).To(gomega.ContainSubstring("content=test+message"))
+		})
 	})
 	ginkgo.Describe("the zulip config", func() {
 		ginkgo.When("cloning a config object", func() {
@@ -0,0 +1,17 @@
+package types
+
+// CheckResult is the outcome of checking the connectivity and credentials of a service.
+type CheckResult struct {
+	// Reachable is whether the service responded to the check.
+	Reachable bool `json:"reachable"`
+	// Authenticated is whether the service accepted the configured credentials, if any.
+	Authenticated bool `json:"authenticated"`
+	// Identity describes the authenticated account, like a bot or user name, when available.
+	Identity string `json:"identity,omitempty"`
+}
+
+// Checker is the interface implemented by services that can check that they are able to reach
+// the remote service and authenticate with it, without sending a notification.
+type Checker interface {
+	Check() (CheckResult, error)
+}
@@ -0,0 +1,17 @@
+package types
+
+import "net/http"
+
+// Request is an HTTP request that a service would perform to send a notification.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// DryRunner is the interface implemented by services that can render the requests used for
+// sending a notification, without performing them. Secrets in the requests are redacted.
+type DryRunner interface {
+	DryRun(message string, params *Params) ([]Request, error)
+}
@@ -35,3 +35,13 @@ func ErrorBody(e error) string {
 
 	return ""
 }
+
+// StatusCode returns the HTTP status code from an error if it's a jsonclient.Error, or 0 if no response was received.
+func StatusCode(e error) int {
+	var jsonError Error
+	if errors.As(e, &jsonError) {
+		return jsonError.StatusCode
+	}
+
+	return 0
+}
@@ -271,6 +271,7 @@ var _ = ginkgo.Describe("JSONClient", func() {
 			gomega.Expect(err.Error()).
 				To(gomega.ContainSubstring("got unexpected HTTP status: 400 Bad Request"))
 			gomega.Expect(jsonclient.ErrorBody(err)).To(gomega.Equal(""))
+			gomega.Expect(jsonclient.StatusCode(err)).To(gomega.Equal(http.StatusBadRequest))
 		})
 	})
 
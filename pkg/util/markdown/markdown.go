@@ -0,0 +1,170 @@
+// Package markdown renders a basic subset of markdown as HTML for services that support formatted messages.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdQuote       = regexp.MustCompile(`^>\s?(.*)$`)
+	mdUnordered   = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdOrdered     = regexp.MustCompile(`^\s*(\d+)[.)]\s+(.*)$`)
+	mdFence       = regexp.MustCompile("^```\\s*([\\w+-]*)\\s*$")
+	mdCodeSpan    = regexp.MustCompile("`([^`]+)`")
+	mdLink        = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	mdBold        = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	mdItalic      = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*|\b_(\S(?:.*?\S)?)_\b`)
+	mdStrike      = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	mdPlaceholder = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// markdownBlock is a block level element of a markdown document.
+type markdownBlock struct {
+	tag   string
+	lines []string
+	attr  string
+}
+
+// ToHTML renders the basic markdown syntax as HTML.
+// Supported are headings, paragraphs, block quotes, lists, fenced code blocks, code spans, links,
+// bold, italic and strikethrough text. Any HTML in the text is escaped.
+func ToHTML(text string) string {
+	blocks := parseMarkdownBlocks(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n"))
+
+	// A single paragraph is not wrapped, so that short messages render as plain text lines
+	if len(blocks) == 1 && blocks[0].tag == "p" {
+		return renderInline(strings.Join(blocks[0].lines, "\n"))
+	}
+
+	builder := strings.Builder{}
+
+	for _, block := range blocks {
+		switch block.tag {
+		case "pre":
+			fmt.Fprintf(&builder, "<pre><code%s>%s</code></pre>",
+				block.attr, html.EscapeString(strings.Join(block.lines, "\n")))
+		case "ul", "ol":
+			builder.WriteString("<" + block.tag + block.attr + ">")
+
+			for _, line := range block.lines {
+				builder.WriteString("<li>" + renderInline(line) + "</li>")
+			}
+
+			builder.WriteString("</" + block.tag + ">")
+		default:
+			fmt.Fprintf(&builder, "<%s>%s</%s>", block.tag, renderInline(strings.Join(block.lines, "\n")), block.tag)
+		}
+	}
+
+	return builder.String()
+}
+
+// parseMarkdownBlocks groups the lines into block level elements.
+func parseMarkdownBlocks(lines []string) []markdownBlock {
+	var blocks []markdownBlock
+
+	var current *markdownBlock
+
+	// appendLine adds the line to the current block if it has the same tag, or starts a new block
+	appendLine := func(tag string, line string, attr string) {
+		if current == nil || current.tag != tag {
+			blocks = append(blocks, markdownBlock{tag: tag, attr: attr})
+			current = &blocks[len(blocks)-1]
+		}
+
+		current.lines = append(current.lines, line)
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if match := mdFence.FindStringSubmatch(line); match != nil {
+			attr := ""
+			if match[1] != "" {
+				attr = ` class="language-` + match[1] + `"`
+			}
+
+			blocks = append(blocks, markdownBlock{tag: "pre", attr: attr})
+			current = &blocks[len(blocks)-1]
+
+			for i++; i < len(lines) && !mdFence.MatchString(lines[i]); i++ {
+				current.lines = append(current.lines, lines[i])
+			}
+
+			current = nil
+
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			current = nil
+
+			continue
+		}
+
+		if match := mdHeading.FindStringSubmatch(line); match != nil {
+			blocks = append(blocks, markdownBlock{tag: "h" + strconv.Itoa(len(match[1])), lines: []string{match[2]}})
+			current = nil
+
+			continue
+		}
+
+		if match := mdQuote.FindStringSubmatch(line); match != nil {
+			appendLine("blockquote", match[1], "")
+
+			continue
+		}
+
+		if match := mdUnordered.FindStringSubmatch(line); match != nil {
+			appendLine("ul", match[1], "")
+
+			continue
+		}
+
+		if match := mdOrdered.FindStringSubmatch(line); match != nil {
+			attr := ""
+			if start, _ := strconv.Atoi(match[1]); start != 1 {
+				attr = ` start="` + match[1] + `"`
+			}
+
+			appendLine("ol", match[2], attr)
+
+			continue
+		}
+
+		appendLine("p", line, "")
+	}
+
+	return blocks
+}
+
+// renderInline renders the inline markdown syntax of the text as HTML.
+func renderInline(text string) string {
+	text = html.EscapeString(text)
+
+	// Code spans are replaced by placeholders, to prevent their content from being formatted
+	var codeSpans []string
+
+	text = mdCodeSpan.ReplaceAllStringFunc(text, func(match string) string {
+		codeSpans = append(codeSpans, "<code>"+mdCodeSpan.FindStringSubmatch(match)[1]+"</code>")
+
+		return "\x00" + strconv.Itoa(len(codeSpans)-1) + "\x00"
+	})
+
+	text = mdLink.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	text = mdBold.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = mdItalic.ReplaceAllString(text, "<em>$1$2</em>")
+	text = mdStrike.ReplaceAllString(text, "<del>$1</del>")
+	text = strings.ReplaceAll(text, "\n", "<br>")
+
+	return mdPlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		index, _ := strconv.Atoi(mdPlaceholder.FindStringSubmatch(match)[1])
+
+		return codeSpans[index]
+	})
+}
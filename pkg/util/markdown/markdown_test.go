@@ -0,0 +1,31 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/markdown"
+)
+
+func TestMarkdown(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Markdown Suite")
+}
+
+var _ = ginkgo.Describe("rendering markdown", func() {
+	ginkgo.It("should render block elements", func() {
+		gomega.Expect(markdown.ToHTML("# Report\n\n- one\n- `two`\n\n> quoted\n\n```go\na < b\n```")).
+			To(gomega.Equal("<h1>Report</h1><ul><li>one</li><li><code>two</code></li></ul>" +
+				"<blockquote>quoted</blockquote><pre><code class=\"language-go\">a &lt; b</code></pre>"))
+	})
+	ginkgo.It("should render inline elements", func() {
+		gomega.Expect(markdown.ToHTML("_a_ ~~b~~ [c](https://example.com)\nnext")).
+			To(gomega.Equal(`<em>a</em> <del>b</del> <a href="https://example.com">c</a><br>next`))
+	})
+	ginkgo.It("should not wrap a single paragraph", func() {
+		gomega.Expect(markdown.ToHTML("**bold** <b>")).
+			To(gomega.Equal("<strong>bold</strong> &lt;b&gt;"))
+	})
+})
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util/jsonclient"
+)
+
+// RedactedValue is the placeholder used for secrets in rendered requests.
+const RedactedValue = "REDACTED"
+
+// RedactedRequest creates a rendered request from the HTTP request and its body, replacing every
+// occurrence of the secrets with a placeholder. The credentials of Authorization headers and
+// URL user info are always redacted.
+func RedactedRequest(req *http.Request, body []byte, secrets ...string) types.Request {
+	requestURL := *req.URL
+	if requestURL.User != nil {
+		requestURL.User = url.User(RedactedValue)
+	}
+
+	redact := func(text string) string {
+		for _, secret := range secrets {
+			if secret == "" {
+				continue
+			}
+
+			text = strings.ReplaceAll(text, secret, RedactedValue)
+			text = strings.ReplaceAll(text, url.QueryEscape(secret), RedactedValue)
+			text = strings.ReplaceAll(text, url.PathEscape(secret), RedactedValue)
+		}
+
+		return text
+	}
+
+	headers := http.Header{}
+
+	for key, values := range req.Header {
+		for _, value := range values {
+			if key == "Authorization" {
+				if scheme, _, found := strings.Cut(value, " "); found {
+					value = scheme + " " + RedactedValue
+				} else {
+					value = RedactedValue
+				}
+			}
+
+			headers.Add(key, redact(value))
+		}
+	}
+
+	return types.Request{
+		Method:  req.Method,
+		URL:     redact(requestURL.String()),
+		Headers: headers,
+		Body:    redact(string(body)),
+	}
+}
+
+// RedactedJSONRequest creates a rendered POST request with the payload encoded as JSON, the way it is
+// sent using jsonclient, replacing every occurrence of the secrets with a placeholder.
+func RedactedJSONRequest(requestURL string, payload any, secrets ...string) (types.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return types.Request{}, fmt.Errorf("marshaling payload to JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, requestURL, nil)
+	if err != nil {
+		return types.Request{}, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", jsonclient.ContentType)
+
+	return RedactedRequest(req, body, secrets...), nil
+}
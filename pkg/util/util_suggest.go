@@ -0,0 +1,49 @@
+package util
+
+// maxSuggestionDistance is the maximum number of edits between a value and a suggested candidate.
+const maxSuggestionDistance = 2
+
+// ClosestMatch returns the candidate that is closest to the value, for suggesting corrections of typos.
+// Only candidates within a few edits of the value are considered, and found is false if there are none.
+func ClosestMatch(value string, candidates []string) (string, bool) {
+	best := ""
+	bestDistance := maxSuggestionDistance + 1
+
+	for _, candidate := range candidates {
+		distance := editDistance(value, candidate)
+		if distance < bestDistance && distance < len(value) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best, best != ""
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := range source {
+		current[0] = i + 1
+
+		for j := range target {
+			cost := 1
+			if source[i] == target[j] {
+				cost = 0
+			}
+
+			current[j+1] = Min(Min(current[j]+1, previous[j+1]+1), previous[j]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
@@ -37,6 +37,19 @@ var _ = ginkgo.Describe("the util package", func() {
 		})
 	})
 
+	ginkgo.When("calling function ClosestMatch", func() {
+		candidates := []string{"priority", "title", "tags"}
+		ginkgo.It("should suggest the closest candidate for a typo", func() {
+			match, found := util.ClosestMatch("prority", candidates)
+			gomega.Expect(found).To(gomega.BeTrue())
+			gomega.Expect(match).To(gomega.Equal("priority"))
+		})
+		ginkgo.It("should not suggest candidates that are too different", func() {
+			_, found := util.ClosestMatch("color", candidates)
+			gomega.Expect(found).To(gomega.BeFalse())
+		})
+	})
+
 	ginkgo.When("checking if a supplied kind is of the signed integer kind", func() {
 		ginkgo.It("should be true if the kind is Int", func() {
 			gomega.Expect(util.IsSignedInt(reflect.Int)).To(gomega.BeTrue())
@@ -102,4 +115,17 @@ var _ = ginkgo.Describe("the util package", func() {
 			gomega.Expect(util.DocsURL(`/foo`)).To(gomega.Equal(util.DocsURL(`foo`)))
 		})
 	})
+
+	ginkgo.When("calling function RedactedJSONRequest", func() {
+		ginkgo.It("should render the payload as JSON with the secrets redacted", func() {
+			payload := map[string]string{"key": "s3cr3t", "text": "message"}
+
+			request, err := util.RedactedJSONRequest("https://example.com/s3cr3t/send", payload, "s3cr3t")
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(request.Method).To(gomega.Equal("POST"))
+			gomega.Expect(request.URL).To(gomega.Equal("https://example.com/REDACTED/send"))
+			gomega.Expect(request.Headers.Get("Content-Type")).To(gomega.Equal("application/json"))
+			gomega.Expect(request.Body).To(gomega.Equal(`{"key":"REDACTED","text":"message"}`))
+		})
+	})
 })
@@ -50,6 +50,28 @@ func NewSender(logger types.StdLogger, serviceURLs ...string) (*router.ServiceRo
 	return sr, nil
 }
 
+// ParseURL parses the notification URL into the config of its service, without initializing the service.
+// The config props can be changed using format.SetConfigProp, and the URL rebuilt using the config's GetURL method.
+func ParseURL(rawURL string) (types.ServiceConfig, error) {
+	config, err := defaultRouter.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL %q: %w", rawURL, err)
+	}
+
+	return config, nil
+}
+
+// NormalizeURL returns the canonical form of the notification URL, as built from the config of its service.
+// When stripDefaults is set, query params with the default value of their prop are removed.
+func NormalizeURL(rawURL string, stripDefaults bool) (string, error) {
+	normalized, err := defaultRouter.NormalizeURL(rawURL, stripDefaults)
+	if err != nil {
+		return "", fmt.Errorf("normalizing URL %q: %w", rawURL, err)
+	}
+
+	return normalized, nil
+}
+
 // Version returns the current Shoutrrr version.
 func Version() string {
 	return meta.Version
@@ -1,9 +1,8 @@
 package docs
 
 import (
+	"encoding/json"
 	"fmt"
-	"log"
-	"net/url"
 	"os"
 	"strings"
 
@@ -29,18 +28,27 @@ var Cmd = &cobra.Command{
 			cmd.UsageTemplate() + "\nAvailable services: \n  " + serviceList + "\n",
 		)
 
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			return cobra.NoArgs(cmd, args)
+		}
+
 		return cobra.MinimumNArgs(1)(cmd, args)
 	},
 	ValidArgs: services,
 }
 
 func init() {
-	Cmd.Flags().StringP("format", "f", "console", "Output format")
+	Cmd.Flags().StringP("format", "f", "console", "Output format (console, markdown, json or jsonschema)")
+	Cmd.Flags().BoolP("all", "a", false, "Print documentation for all services")
 }
 
 func Run(cmd *cobra.Command, args []string) {
 	format, _ := cmd.Flags().GetString("format")
 
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		args = services
+	}
+
 	res := printDocs(format, args)
 	if res.ExitCode != 0 {
 		fmt.Fprintf(os.Stderr, "%s", res.Message)
@@ -52,6 +60,9 @@ func Run(cmd *cobra.Command, args []string) {
 func printDocs(docFormat string, services []string) cmd.Result {
 	var renderer format.TreeRenderer
 
+	// The JSON formats are combined into a single object, with the document for each service keyed by its scheme
+	combined := false
+
 	switch docFormat {
 	case "console":
 		renderer = format.ConsoleTreeRenderer{WithValues: false}
@@ -61,29 +72,41 @@ func printDocs(docFormat string, services []string) cmd.Result {
 			PropsDescription:  "Props can be either supplied using the params argument, or through the URL using  \n`?key=value&key=value` etc.\n",
 			PropsEmptyMessage: "*The services does not support any query/param props*",
 		}
+	case "json":
+		renderer, combined = format.JSONTreeRenderer{}, true
+	case "jsonschema":
+		renderer, combined = format.JSONSchemaTreeRenderer{}, true
 	default:
 		return cmd.InvalidUsage("invalid format")
 	}
 
-	logger := log.New(os.Stderr, "", 0) // Concrete logger implementing types.StdLogger
+	documents := make(map[string]json.RawMessage, len(services))
 
 	for _, scheme := range services {
-		service, err := serviceRouter.NewService(scheme)
+		config, err := serviceRouter.NewConfig(scheme)
 		if err != nil {
 			return cmd.InvalidUsage("failed to init service: " + err.Error())
 		}
-		// Initialize the service to populate Config
-		dummyURL, _ := url.Parse(scheme + "://dummy@dummy.com")
-		if err := service.Initialize(dummyURL, logger); err != nil {
-			return cmd.InvalidUsage(
-				fmt.Sprintf("failed to initialize service %q: %v\n", scheme, err),
-			)
-		}
 
-		config := format.GetServiceConfig(service)
 		configNode := format.GetConfigFormat(config)
+
+		if combined {
+			documents[scheme] = json.RawMessage(renderer.RenderTree(configNode, scheme))
+
+			continue
+		}
+
 		fmt.Fprint(os.Stdout, renderer.RenderTree(configNode, scheme), "\n")
 	}
 
+	if combined {
+		output, err := json.MarshalIndent(documents, "", "  ")
+		if err != nil {
+			return cmd.TaskUnavailable("failed to write documentation: " + err.Error())
+		}
+
+		fmt.Fprint(os.Stdout, string(output), "\n")
+	}
+
 	return cmd.Success
 }
@@ -7,6 +7,9 @@ const (
 	ExUsage = 64
 	// ExUnavailable is the exit code that signals that the application failed to perform the intended task.
 	ExUnavailable = 69
+	// ExPartial is the exit code that signals that the application only performed part of the intended task,
+	// like sending a notification to some of the services, but not all of them.
+	ExPartial = 75
 	// ExConfig is the exit code that signals that the task failed due to a configuration error.
 	ExConfig = 78
 )
@@ -51,3 +54,11 @@ func ConfigurationError(message string) Result {
 		message,
 	}
 }
+
+// PartialFailure returns a Result with the exit code ExPartial.
+func PartialFailure(message string) Result {
+	return Result{
+		ExPartial,
+		message,
+	}
+}
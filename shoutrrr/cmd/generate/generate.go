@@ -11,16 +11,28 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/nicholas-fedor/shoutrrr/pkg/generators"
+	"github.com/nicholas-fedor/shoutrrr/pkg/generators/guided"
 	"github.com/nicholas-fedor/shoutrrr/pkg/router"
 	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/verify"
 )
 
 // MaximumNArgs defines the maximum number of positional arguments allowed.
 const MaximumNArgs = 2
 
+// Output formats for the generated URL.
+const (
+	OutputText  = "text"  // OutputText shows the generation steps and the URL, using colors when supported.
+	OutputPlain = "plain" // OutputPlain writes only the URL to stdout.
+	OutputJSON  = "json"  // OutputJSON writes the service, generator and URL as a JSON object to stdout.
+)
+
 // ErrNoServiceSpecified indicates that no service was provided for URL generation.
 var (
 	ErrNoServiceSpecified = errors.New("no service specified")
+	ErrInvalidProperty    = errors.New("properties must be in the key=value format")
+	ErrInvalidOutput      = errors.New("unsupported output format")
 )
 
 // serviceRouter manages the creation of notification services.
@@ -58,6 +70,11 @@ func init() {
 		StringArrayP("property", "p", []string{}, "Configuration property in key=value format (e.g., token=abc123)")
 	Cmd.Flags().
 		BoolP("show-sensitive", "x", false, "Show sensitive data in the generated URL (default: masked)")
+	Cmd.Flags().
+		Bool("non-interactive", false, "Never prompt for values, failing if any required properties are missing")
+	Cmd.Flags().
+		String("from-json", "", "A JSON file containing the properties as an object, or - to read it from stdin")
+	Cmd.Flags().StringP("output", "o", OutputText, "The format of the generated URL (text, plain or json)")
 }
 
 // maskSensitiveURL masks sensitive parts of a Shoutrrr URL based on the service schema.
@@ -90,11 +107,20 @@ func maskUser(parsedURL *url.URL, placeholder string) {
 	}
 }
 
-// maskSMTPUser redacts the password in an SMTP URL, preserving the username.
+// maskSMTPUser redacts the password and OAuth2 secrets in an SMTP URL, preserving the username.
 func maskSMTPUser(parsedURL *url.URL) {
 	if parsedURL.User != nil {
 		parsedURL.User = url.UserPassword(parsedURL.User.Username(), "REDACTED")
 	}
+
+	queryParams := parsedURL.Query()
+	for _, key := range []string{"clientsecret", "refreshtoken"} {
+		if queryParams.Get(key) != "" {
+			queryParams.Set(key, "REDACTED")
+		}
+	}
+
+	parsedURL.RawQuery = queryParams.Encode()
 }
 
 // maskPushoverQuery redacts token and user query parameters in a Pushover URL.
@@ -143,26 +169,31 @@ func Run(cmd *cobra.Command, _ []string) {
 	generatorName, _ := cmd.Flags().GetString("generator")
 	propertyFlags, _ := cmd.Flags().GetStringArray("property")
 	showSensitive, _ := cmd.Flags().GetBool("show-sensitive")
+	nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+	jsonFile, _ := cmd.Flags().GetString("from-json")
+	output, _ := cmd.Flags().GetString("output")
+
+	if nonInteractive || jsonFile != "" || output != OutputText {
+		if err := runNonInteractive(cmd, os.Stdout); err != nil {
+			exitWithError(err)
+		}
+
+		return
+	}
 
-	// Parse properties into a key-value map.
+	// Parse properties into a key-value map, reporting invalid pairs without stopping.
 	props := make(map[string]string, len(propertyFlags))
 
 	for _, prop := range propertyFlags {
-		parts := strings.Split(prop, "=")
-		if len(parts) != MaximumNArgs {
+		if err := parsePropertyFlags([]string{prop}, props); err != nil {
 			fmt.Fprint(
 				color.Output,
 				"Invalid property key/value pair: ",
 				color.HiYellowString(prop),
 				"\n",
 			)
-
-			continue
 		}
-
-		props[parts[0]] = parts[1]
 	}
-
 	if len(propertyFlags) > 0 {
 		fmt.Fprint(color.Output, "\n") // Add spacing after property warnings
 	}
@@ -237,4 +268,22 @@ func Run(cmd *cobra.Command, _ []string) {
 	} else {
 		fmt.Fprint(os.Stdout, "URL: ", maskedURL, "\n")
 	}
+
+	if guidedGenerator, ok := generator.(*guided.Generator); ok && guidedGenerator.CheckRequested {
+		if exitCode := checkGeneratedURL(serviceConfig.GetURL().String()); exitCode != cli.ExSuccess {
+			os.Exit(exitCode)
+		}
+	}
+}
+
+// checkGeneratedURL creates a service from the generated URL and performs the same live check as verify.
+func checkGeneratedURL(rawURL string) int {
+	service, err := serviceRouter.Locate(rawURL)
+	if err != nil {
+		fmt.Fprint(os.Stdout, "Error: ", cli.RedactSecrets(cli.ErrorDetail(err), rawURL), "\n")
+
+		return cli.ExConfig
+	}
+
+	return verify.CheckService(color.Output, service, rawURL)
 }
@@ -0,0 +1,159 @@
+package generate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/generators/basic"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+// ErrInteractiveGenerator indicates that a generator that requires prompts was requested in the non-interactive mode.
+var ErrInteractiveGenerator = errors.New("only the basic generator can be used in the non-interactive mode")
+
+// jsonResult is the generated URL, as written in the JSON output.
+type jsonResult struct {
+	Service   string `json:"service"`
+	Generator string `json:"generator"`
+	URL       string `json:"url"`
+}
+
+// runNonInteractive generates the URL from the property flags and JSON input only, without prompting.
+// Errors are returned as CLI results, to be reported with the matching exit code.
+func runNonInteractive(cmd *cobra.Command, stdout io.Writer) error {
+	flags := cmd.Flags()
+	serviceSchema, _ := flags.GetString("service")
+	generatorName, _ := flags.GetString("generator")
+	propertyFlags, _ := flags.GetStringArray("property")
+	showSensitive, _ := flags.GetBool("show-sensitive")
+	jsonFile, _ := flags.GetString("from-json")
+	output, _ := flags.GetString("output")
+
+	if output != OutputText && output != OutputPlain && output != OutputJSON {
+		return cli.InvalidUsage(fmt.Errorf("%w: %q", ErrInvalidOutput, output).Error())
+	}
+
+	if serviceSchema == "" {
+		return cli.InvalidUsage(ErrNoServiceSpecified.Error())
+	}
+
+	if flags.Changed("generator") && !strings.EqualFold(generatorName, "basic") {
+		return cli.InvalidUsage(fmt.Errorf("%w: %q", ErrInteractiveGenerator, generatorName).Error())
+	}
+
+	props := map[string]string{}
+
+	if jsonFile != "" {
+		if err := readJSONProps(jsonFile, props); err != nil {
+			return cli.InvalidUsage(err.Error())
+		}
+	}
+
+	// Property flags are added last, to allow overriding the values from the JSON input
+	if err := parsePropertyFlags(propertyFlags, props); err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	service, err := serviceRouter.NewService(serviceSchema)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	generator := &basic.Generator{NonInteractive: true}
+
+	serviceConfig, err := generator.Generate(service, props, nil)
+	if err != nil {
+		return cli.ConfigurationError(err.Error())
+	}
+
+	serviceURL := serviceConfig.GetURL().String()
+	if !showSensitive {
+		serviceURL = maskSensitiveURL(serviceSchema, serviceURL)
+	}
+
+	switch output {
+	case OutputJSON:
+		err = json.NewEncoder(stdout).Encode(jsonResult{Service: serviceSchema, Generator: "basic", URL: serviceURL})
+	case OutputPlain:
+		_, err = fmt.Fprintln(stdout, serviceURL)
+	default:
+		_, err = fmt.Fprintln(stdout, "URL:", serviceURL)
+	}
+
+	if err != nil {
+		return cli.TaskUnavailable(fmt.Sprintf("writing URL: %v", err))
+	}
+
+	return nil
+}
+
+// parsePropertyFlags adds the properties given as key=value pairs to the props.
+// Only the first equals sign separates the key from the value, which may contain any character.
+func parsePropertyFlags(pairs []string, props map[string]string) error {
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidProperty, pair)
+		}
+
+		props[key] = value
+	}
+
+	return nil
+}
+
+// readJSONProps adds the properties from a JSON object, read from the file at path, or stdin if path is -.
+func readJSONProps(path string, props map[string]string) error {
+	var reader io.Reader = os.Stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("reading properties: %w", err)
+		}
+
+		defer file.Close()
+
+		reader = file
+	}
+
+	values := map[string]any{}
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&values); err != nil {
+		return fmt.Errorf("parsing JSON properties: %w", err)
+	}
+
+	for key, value := range values {
+		formatted, err := cli.FormatValue(value)
+		if err != nil {
+			return fmt.Errorf("property %q: %w", key, err)
+		}
+
+		props[key] = formatted
+	}
+
+	return nil
+}
+
+// exitWithError reports the error on stderr and exits with the code of the CLI result.
+func exitWithError(err error) {
+	exitCode := cli.ExUsage
+
+	var result cli.Result
+	if errors.As(err, &result) {
+		exitCode = result.ExitCode
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, "Error:", err)
+
+	os.Exit(exitCode)
+}
@@ -0,0 +1,157 @@
+package normalize
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+// ExNotNormalized is the exit code that signals that some of the checked URLs are not normalized.
+const ExNotNormalized = 1
+
+// ErrNoURLs indicates that no URLs were given to normalize.
+var ErrNoURLs = errors.New("no urls given, use --url or pass them as arguments")
+
+// Cmd prints the canonical form of service URLs.
+var Cmd = &cobra.Command{
+	Use:   "normalize [url...]",
+	Short: "Print the canonical form of notification service URLs",
+	Long: `Print the canonical form of notification service URLs, as built by their service configs.
+
+The URLs are parsed without initializing the services, and missing props are given their default values.
+With --check, nothing is printed for normalized URLs, and the command fails if any of them are not.`,
+	RunE: Run,
+	Args: cobra.ArbitraryArgs,
+}
+
+var serviceRouter router.ServiceRouter
+
+func init() {
+	Cmd.Flags().
+		StringArrayP("url", "u", []string{}, "The notification url, or - to read urls from stdin, one per line (can be repeated)")
+	Cmd.Flags().BoolP("strip-defaults", "s", false, "Remove the query params that have the default value of their prop")
+	Cmd.Flags().Bool("check", false, "Only check that the urls are normalized, exiting with an error if any of them is not")
+	Cmd.Flags().Bool("show-sensitive", false, "Show the full urls in the check results, instead of redacting secrets")
+}
+
+// Run the normalize command.
+func Run(cmd *cobra.Command, args []string) error {
+	err := run(cmd, args, os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		var result cli.Result
+		if errors.As(err, &result) && result.ExitCode != cli.ExUsage {
+			// If the error is not related to CLI usage, report error and exit to avoid cobra error output
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+	}
+
+	return err
+}
+
+func run(cmd *cobra.Command, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	flags := cmd.Flags()
+	urlFlags, _ := flags.GetStringArray("url")
+	stripDefaults, _ := flags.GetBool("strip-defaults")
+	check, _ := flags.GetBool("check")
+	showSensitive, _ := flags.GetBool("show-sensitive")
+
+	urls, err := collectURLs(append(urlFlags, args...), stdin)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	if len(urls) == 0 {
+		return cli.InvalidUsage(ErrNoURLs.Error())
+	}
+
+	invalid, changed := 0, 0
+
+	for _, rawURL := range urls {
+		normalized, err := serviceRouter.NormalizeURL(rawURL, stripDefaults)
+		if err != nil {
+			invalid++
+
+			_, _ = fmt.Fprintf(stderr, "Error: %s: %s\n", redact(rawURL, showSensitive),
+				cli.RedactSecrets(cli.ErrorDetail(err), rawURL))
+
+			continue
+		}
+
+		if !check {
+			_, _ = fmt.Fprintln(stdout, normalized)
+
+			continue
+		}
+
+		if normalized != rawURL {
+			changed++
+
+			_, _ = fmt.Fprintf(stdout, "Not normalized: %s\n  want: %s\n",
+				redact(rawURL, showSensitive), redact(normalized, showSensitive))
+		}
+	}
+
+	switch {
+	case invalid > 0:
+		return cli.ConfigurationError(fmt.Sprintf("failed to normalize %d of %d url(s)", invalid, len(urls)))
+	case changed > 0:
+		return cli.Result{
+			ExitCode: ExNotNormalized,
+			Message:  fmt.Sprintf("%d of %d url(s) are not normalized", changed, len(urls)),
+		}
+	}
+
+	return nil
+}
+
+// collectURLs returns the URLs, replacing any - with the non-empty lines read from stdin.
+func collectURLs(sources []string, stdin io.Reader) ([]string, error) {
+	urls := make([]string, 0, len(sources))
+	readStdin := false
+
+	for _, source := range sources {
+		if source != "-" {
+			urls = append(urls, source)
+
+			continue
+		}
+
+		// Stdin can only be read once, so any further - are ignored
+		if readStdin {
+			continue
+		}
+
+		readStdin = true
+
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				urls = append(urls, line)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading urls from stdin: %w", err)
+		}
+	}
+
+	return urls, nil
+}
+
+// redact returns the URL with its secrets hidden, unless they should be shown.
+func redact(rawURL string, showSensitive bool) string {
+	if showSensitive {
+		return rawURL
+	}
+
+	return cli.RedactURL(rawURL)
+}
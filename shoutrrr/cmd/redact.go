@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+)
+
+// Redacted is the placeholder used for hidden parts of service URLs.
+const Redacted = "REDACTED"
+
+// RedactURL hides the parts of a service URL that may contain secrets, so that it can be shown in output.
+// The user info, the path segments and the query values are replaced, while the scheme and host are kept.
+func RedactURL(rawURL string) string {
+	serviceURL, err := url.Parse(rawURL)
+	if err != nil {
+		return Redacted
+	}
+
+	if serviceURL.User != nil {
+		serviceURL.User = url.User(Redacted)
+	}
+
+	// The escaped path is split, so that escaped slashes do not change the number of segments
+	segments := strings.Split(serviceURL.EscapedPath(), "/")
+	for i, segment := range segments {
+		if segment != "" {
+			segments[i] = Redacted
+		}
+	}
+
+	serviceURL.Path = strings.Join(segments, "/")
+	serviceURL.RawPath = ""
+
+	query := serviceURL.Query()
+	for key := range query {
+		query.Set(key, Redacted)
+	}
+
+	serviceURL.RawQuery = query.Encode()
+	serviceURL.Fragment = ""
+
+	redacted := serviceURL.String()
+
+	// URLs without a host, like logger://, would otherwise lose their slashes
+	if strings.HasPrefix(rawURL, serviceURL.Scheme+"://") && !strings.HasPrefix(redacted, serviceURL.Scheme+"://") {
+		redacted = serviceURL.Scheme + "://" + strings.TrimPrefix(redacted, serviceURL.Scheme+":")
+	}
+
+	return redacted
+}
+
+// RedactSecrets replaces the parts of the service URL that may contain secrets in the text, like an error message.
+// Every non-empty part is replaced, both as it is and in its query and path escaped forms. The escaped forms are
+// also replaced when they have been escaped once more, like when the service URL is passed on as a query value.
+func RedactSecrets(text string, rawURL string) string {
+	serviceURL, err := url.Parse(rawURL)
+	if err != nil {
+		return text
+	}
+
+	var secrets []string
+
+	if serviceURL.User != nil {
+		password, _ := serviceURL.User.Password()
+		secrets = append(secrets, serviceURL.User.Username(), password)
+	}
+
+	for _, segment := range strings.Split(serviceURL.EscapedPath(), "/") {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			secrets = append(secrets, unescaped)
+		}
+	}
+
+	for _, values := range serviceURL.Query() {
+		secrets = append(secrets, values...)
+	}
+
+	forms := make([]string, 0, len(secrets)*7)
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		forms = append(forms, secret)
+
+		for _, escaped := range []string{url.QueryEscape(secret), url.PathEscape(secret)} {
+			forms = append(forms, escaped, url.QueryEscape(escaped), url.PathEscape(escaped))
+		}
+	}
+
+	if len(forms) == 0 {
+		return text
+	}
+
+	// The longest secrets are matched first, so that secrets containing shorter ones are replaced as a whole
+	slices.SortStableFunc(forms, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+
+	pairs := make([]string, 0, len(forms)*2)
+	for _, form := range forms {
+		pairs = append(pairs, form, Redacted)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// ErrorDetail returns the message of the error, including the error of the service when it could not be
+// initialized, which the router leaves out of its message. The detail may contain secrets from the URL,
+// so it has to be passed through RedactSecrets before it is shown.
+func ErrorDetail(err error) string {
+	var initErr *router.InitializeError
+	if !errors.As(err, &initErr) {
+		return err.Error()
+	}
+
+	detail := fmt.Sprintf("%s: %v: %v", initErr.Scheme, router.ErrInitializeFailed, initErr.Err)
+
+	return strings.Replace(err.Error(), initErr.Error(), detail, 1)
+}
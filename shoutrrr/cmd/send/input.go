@@ -0,0 +1,143 @@
+package send
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+var (
+	ErrInvalidParam = errors.New("params must be in the key=value format")
+	ErrInvalidLevel = errors.New("unknown message level")
+	ErrNoMessage    = errors.New("a message or message items are required")
+)
+
+// jsonInput is the structured input read from stdin in the JSON mode.
+type jsonInput struct {
+	Message string         `json:"message"`
+	Title   string         `json:"title"`
+	Params  map[string]any `json:"params"`
+	Items   []jsonItem     `json:"items"`
+}
+
+// jsonItem is a message item in the structured input.
+type jsonItem struct {
+	Text      string         `json:"text"`
+	Timestamp time.Time      `json:"timestamp"`
+	Level     string         `json:"level"`
+	Fields    map[string]any `json:"fields"`
+}
+
+// readJSONInput parses the structured input, adding its params to the given params.
+func readJSONInput(reader io.Reader, params types.Params) (jsonInput, []types.MessageItem, error) {
+	var input jsonInput
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&input); err != nil {
+		return input, nil, fmt.Errorf("parsing JSON input: %w", err)
+	}
+
+	if err := addParams(params, input.Params); err != nil {
+		return input, nil, err
+	}
+
+	items := make([]types.MessageItem, 0, len(input.Items))
+
+	for _, item := range input.Items {
+		level, err := parseLevel(item.Level)
+		if err != nil {
+			return input, nil, err
+		}
+
+		fields := types.Params{}
+		if err := addParams(fields, item.Fields); err != nil {
+			return input, nil, err
+		}
+
+		messageItem := types.MessageItem{Text: item.Text, Timestamp: item.Timestamp, Level: level}
+		for _, key := range slices.Sorted(maps.Keys(fields)) {
+			messageItem.WithField(key, fields[key])
+		}
+
+		items = append(items, messageItem)
+	}
+
+	if input.Message == "" && len(items) == 0 {
+		return input, nil, ErrNoMessage
+	}
+
+	return input, items, nil
+}
+
+// readParamsFile adds the params from a JSON or YAML file to the given params.
+func readParamsFile(path string, params types.Params) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading params file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so both formats can be parsed as YAML
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("parsing params file %q: %w", path, err)
+	}
+
+	return addParams(params, values)
+}
+
+// parseParamFlags adds the params given as key=value pairs to the given params.
+func parseParamFlags(pairs []string, params types.Params) error {
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		if !found || key == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidParam, pair)
+		}
+
+		params[key] = value
+	}
+
+	return nil
+}
+
+// addParams adds the values to the params, formatting them in the same way as config props in service URLs.
+// Lists are joined by commas, and objects are written as comma separated key:value pairs.
+func addParams(params types.Params, values map[string]any) error {
+	for key, value := range values {
+		formatted, err := cli.FormatValue(value)
+		if err != nil {
+			return fmt.Errorf("param %q: %w", key, err)
+		}
+
+		params[key] = formatted
+	}
+
+	return nil
+}
+
+// parseLevel returns the message level matching the name, ignoring case.
+func parseLevel(name string) (types.MessageLevel, error) {
+	if name == "" {
+		return types.Unknown, nil
+	}
+
+	for level := range types.MessageLevel(types.MessageLevelCount) {
+		if strings.EqualFold(level.String(), name) {
+			return level, nil
+		}
+	}
+
+	return types.Unknown, fmt.Errorf("%w: %q", ErrInvalidLevel, name)
+}
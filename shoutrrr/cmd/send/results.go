@@ -0,0 +1,191 @@
+package send
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+// Output formats for the send results.
+const (
+	OutputText = "text" // OutputText logs a line for every URL to stderr.
+	OutputJSON = "json" // OutputJSON writes a JSON object for every URL to stdout, one per line.
+)
+
+// Failure policies, deciding when the command exits with an error.
+const (
+	FailOnAny  = "any"  // FailOnAny fails when sending to any of the URLs failed.
+	FailOnAll  = "all"  // FailOnAll only fails when sending to all of the URLs failed.
+	FailOnNone = "none" // FailOnNone never fails because of sending errors.
+)
+
+const (
+	statusSent     = "sent"
+	statusFailed   = "failed"
+	statusRendered = "rendered"
+)
+
+var (
+	ErrInvalidOutput = errors.New("unsupported output format")
+	ErrInvalidFailOn = errors.New("unsupported failure policy")
+)
+
+// urlResult is the result of sending to a single URL, as written in the JSON output.
+type urlResult struct {
+	URL      string          `json:"url"`
+	Service  string          `json:"service"`
+	Status   string          `json:"status"`
+	Error    string          `json:"error,omitempty"`
+	Duration string          `json:"duration"`
+	Requests []types.Request `json:"requests,omitempty"`
+}
+
+// validateOutputFlags checks that the output format and failure policy are supported.
+func validateOutputFlags(output string, failOn string) error {
+	if output != OutputText && output != OutputJSON {
+		return fmt.Errorf("%w: %q", ErrInvalidOutput, output)
+	}
+
+	if failOn != FailOnAny && failOn != FailOnAll && failOn != FailOnNone {
+		return fmt.Errorf("%w: %q", ErrInvalidFailOn, failOn)
+	}
+
+	return nil
+}
+
+// reportResults outputs the result for every URL as it is received, and returns the
+// error for the command according to the failure policy.
+func reportResults(results chan router.Result, urls []string, output string, failOn string, stdout io.Writer) error {
+	collected := make([]urlResult, len(urls))
+	failed := 0
+
+	for result := range results {
+		redacted := cli.RedactURL(urls[result.Index])
+		entry := urlResult{
+			URL:      redacted,
+			Service:  result.Service,
+			Status:   statusSent,
+			Duration: result.Duration.String(),
+		}
+
+		if result.Err != nil {
+			failed++
+			entry.Status = statusFailed
+			entry.Error = cli.RedactSecrets(result.Err.Error(), urls[result.Index])
+		}
+
+		collected[result.Index] = entry
+
+		if output == OutputText {
+			if result.Err != nil {
+				logf("Failed to send to %s: %s", redacted, entry.Error)
+			} else {
+				logf("Notification sent to %s", redacted)
+			}
+		}
+	}
+
+	if output == OutputJSON {
+		// The results are written in the order of the URLs, regardless of when they were received
+		encoder := json.NewEncoder(stdout)
+		for _, entry := range collected {
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("writing results: %w", err)
+			}
+		}
+	}
+
+	return failureResult(failed, len(urls), failOn, "send to")
+}
+
+// reportDryRun outputs the rendered requests for every URL, and returns the error for the command
+// according to the failure policy. In text output, the requests are written to stdout in HTTP format.
+func reportDryRun(results []router.Result, urls []string, output string, failOn string, stdout io.Writer) error {
+	failed := 0
+	encoder := json.NewEncoder(stdout)
+
+	for _, result := range results {
+		redacted := cli.RedactURL(urls[result.Index])
+		entry := urlResult{
+			URL:      redacted,
+			Service:  result.Service,
+			Status:   statusRendered,
+			Duration: result.Duration.String(),
+			Requests: result.Requests,
+		}
+
+		if result.Err != nil {
+			failed++
+			entry.Status = statusFailed
+			entry.Error = cli.RedactSecrets(result.Err.Error(), urls[result.Index])
+		}
+
+		if output == OutputJSON {
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("writing results: %w", err)
+			}
+
+			continue
+		}
+
+		if result.Err != nil {
+			logf("Failed to render requests for %s: %s", redacted, entry.Error)
+
+			continue
+		}
+
+		for _, request := range result.Requests {
+			if err := writeRequest(stdout, redacted, request); err != nil {
+				return fmt.Errorf("writing results: %w", err)
+			}
+		}
+	}
+
+	return failureResult(failed, len(urls), failOn, "render requests for")
+}
+
+// writeRequest writes the rendered request in HTTP format, preceded by a comment with the URL it was rendered for.
+func writeRequest(writer io.Writer, serviceURL string, request types.Request) error {
+	if _, err := fmt.Fprintf(writer, "# %s\n%s %s\n", serviceURL, request.Method, request.URL); err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(request.Headers)) {
+		for _, value := range request.Headers[key] {
+			if _, err := fmt.Fprintf(writer, "%s: %s\n", key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(writer, "\n%s\n\n", request.Body)
+
+	return err
+}
+
+// failureResult returns the error for the number of failed URLs according to the failure policy.
+// When all URLs failed, the task is reported as unavailable, and otherwise as a partial failure.
+func failureResult(failed int, total int, failOn string, action string) error {
+	if failed == 0 || failOn == FailOnNone {
+		return nil
+	}
+
+	message := fmt.Sprintf("failed to %s %d of %d URLs", action, failed, total)
+
+	if failed == total {
+		return cli.TaskUnavailable(message)
+	}
+
+	if failOn == FailOnAll {
+		return nil
+	}
+
+	return cli.PartialFailure(message)
+}
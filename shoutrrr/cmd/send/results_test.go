@@ -0,0 +1,40 @@
+package send
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+func TestSend(t *testing.T) {
+	gomega.RegisterFailHandler(ginkgo.Fail)
+	ginkgo.RunSpecs(t, "Send Command Suite")
+}
+
+var _ = ginkgo.DescribeTable("the failure policy",
+	func(failed int, failOn string, exitCode int) {
+		err := failureResult(failed, 3, failOn, "send to")
+		if exitCode == cli.ExSuccess {
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			return
+		}
+
+		gomega.Expect(err).To(gomega.Equal(cli.Result{
+			ExitCode: exitCode,
+			Message:  fmt.Sprintf("failed to send to %d of 3 URLs", failed),
+		}))
+	},
+	ginkgo.Entry("any, without failures", 0, FailOnAny, cli.ExSuccess),
+	ginkgo.Entry("any, with some failures", 1, FailOnAny, cli.ExPartial),
+	ginkgo.Entry("any, with all failing", 3, FailOnAny, cli.ExUnavailable),
+	ginkgo.Entry("all, without failures", 0, FailOnAll, cli.ExSuccess),
+	ginkgo.Entry("all, with some failures", 2, FailOnAll, cli.ExSuccess),
+	ginkgo.Entry("all, with all failing", 3, FailOnAll, cli.ExUnavailable),
+	ginkgo.Entry("none, with some failures", 1, FailOnNone, cli.ExSuccess),
+	ginkgo.Entry("none, with all failing", 3, FailOnNone, cli.ExSuccess),
+)
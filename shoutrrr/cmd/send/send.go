@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -40,8 +42,19 @@ func init() {
 	Cmd.Flags().
 		StringP("message", "m", "", "The message to send to the notification url, or - to read message from stdin")
 
-	_ = Cmd.MarkFlagRequired("message")
 	Cmd.Flags().StringP("title", "t", "", "The title used for services that support it")
+	Cmd.Flags().
+		StringArrayP("param", "p", []string{}, "A param passed to the services, in the key=value format (can be repeated)")
+	Cmd.Flags().String("params-file", "", "A JSON or YAML file containing params passed to the services")
+	Cmd.Flags().
+		Bool("json", false, "Read the message, title, params and message items as a JSON object from stdin")
+	Cmd.MarkFlagsMutuallyExclusive("json", "message")
+	Cmd.Flags().StringP("output", "o", OutputText, "The format of the send results (text or json)")
+	Cmd.Flags().
+		String("fail-on", FailOnAny, "When to exit with an error: if sending to any, all or none of the urls failed")
+	Cmd.Flags().Bool("dry-run", false, "Print the requests that would be sent, with secrets redacted, instead of sending "+
+		"(supported by bark, discord, generic, googlechat, gotify, ifttt, join, lark, mattermost, ntfy, opsgenie, pagerduty, "+
+		"pushbullet, pushover, rocketchat, signal, slack, teams, telegram, wecom and zulip)")
 }
 
 func logf(format string, a ...any) {
@@ -56,6 +69,54 @@ func run(cmd *cobra.Command) error {
 	urls = dedupe.RemoveDuplicates(urls)
 	message, _ := flags.GetString("message")
 	title, _ := flags.GetString("title")
+	paramPairs, _ := flags.GetStringArray("param")
+	paramsFile, _ := flags.GetString("params-file")
+	jsonInput, _ := flags.GetBool("json")
+	output, _ := flags.GetString("output")
+	failOn, _ := flags.GetString("fail-on")
+	dryRun, _ := flags.GetBool("dry-run")
+
+	if err := validateOutputFlags(output, failOn); err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	// Params are applied from the params file, the JSON input and the flags, with later ones taking precedence
+	params := make(types.Params)
+
+	if paramsFile != "" {
+		if err := readParamsFile(paramsFile, params); err != nil {
+			return cli.InvalidUsage(err.Error())
+		}
+	}
+
+	var items []types.MessageItem
+
+	if jsonInput {
+		logf("Reading JSON from STDIN...")
+
+		input, inputItems, err := readJSONInput(os.Stdin, params)
+		if err != nil {
+			return cli.InvalidUsage(err.Error())
+		}
+
+		message = input.Message
+		items = inputItems
+
+		// When sending items, the message is sent as the first item
+		if message != "" && len(items) > 0 {
+			items = append([]types.MessageItem{{Text: message}}, items...)
+		}
+
+		if title == "" {
+			title = input.Title
+		}
+	} else if message == "" {
+		return cli.InvalidUsage(`required flag(s) "message" not set`)
+	}
+
+	if err := parseParamFlags(paramPairs, params); err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
 
 	if message == "-" {
 		logf("Reading from STDIN...")
@@ -91,6 +152,14 @@ func run(cmd *cobra.Command) error {
 			logf("Title: %v", title)
 		}
 
+		if len(items) > 0 {
+			logf("Items: %d", len(items))
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(params)) {
+			logf("Param: %s=%s", key, params[key])
+		}
+
 		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
 	} else {
 		logger = util.DiscardLogger
@@ -98,24 +167,35 @@ func run(cmd *cobra.Command) error {
 
 	serviceRouter, err := router.New(logger, urls...)
 	if err != nil {
-		return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
+		message := cli.ErrorDetail(err)
+		for _, rawURL := range urls {
+			message = cli.RedactSecrets(message, rawURL)
+		}
+
+		return cli.ConfigurationError("error invoking send: " + message)
 	}
 
-	params := make(types.Params)
 	if title != "" {
 		params["title"] = title
 	}
 
-	errs := serviceRouter.SendAsync(message, &params)
-	for err := range errs {
-		if err != nil {
-			return cli.TaskUnavailable(err.Error())
+	if dryRun {
+		// Items are rendered as plain text, since the services only render the requests for plain messages
+		if len(items) > 0 {
+			message = strings.TrimSuffix(types.ItemsToPlain(items), "\n")
 		}
 
-		logf("Notification sent")
+		return reportDryRun(serviceRouter.DryRun(message, &params), urls, output, failOn, os.Stdout)
+	}
+
+	var results chan router.Result
+	if len(items) > 0 {
+		results = serviceRouter.SendItemsAsyncResults(items, params)
+	} else {
+		results = serviceRouter.SendAsyncResults(message, &params)
 	}
 
-	return nil
+	return reportResults(results, urls, output, failOn, os.Stdout)
 }
 
 // Run executes the send command and handles its result.
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidValue is returned when a value read from structured input cannot be used as a string value.
+var ErrInvalidValue = errors.New("unsupported value")
+
+// FormatValue formats a value decoded from JSON or YAML input in the same way as config props in service URLs.
+// Lists are joined by commas, and objects are written as comma separated key:value pairs.
+func FormatValue(value any) (string, error) {
+	switch typed := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case int, int64, uint64, float64, json.Number:
+		return fmt.Sprint(typed), nil
+	case []any:
+		parts := make([]string, 0, len(typed))
+
+		for _, element := range typed {
+			part, err := FormatValue(element)
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, part)
+		}
+
+		return strings.Join(parts, ","), nil
+	case map[string]any:
+		parts := make([]string, 0, len(typed))
+
+		for _, key := range slices.Sorted(maps.Keys(typed)) {
+			part, err := FormatValue(typed[key])
+			if err != nil {
+				return "", err
+			}
+
+			parts = append(parts, key+":"+part)
+		}
+
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrInvalidValue, value)
+	}
+}
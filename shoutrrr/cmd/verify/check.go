@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+// CheckService performs the live connectivity check of the service, writing the outcome to the writer.
+// It returns the exit code for the command, which is non-zero when the check is not supported or failed.
+func CheckService(writer io.Writer, service types.Service, rawURL string) int {
+	checker, ok := service.(types.Checker)
+	if !ok {
+		fmt.Fprintf(writer, "\nChecking is not supported by the %s service\n", service.GetID())
+
+		return cli.ExUsage
+	}
+
+	result, err := checker.Check()
+
+	fmt.Fprintln(writer, "\nCheck:")
+	fmt.Fprintf(writer, "  Reachable:     %s\n", formatStatus(result.Reachable))
+	fmt.Fprintf(writer, "  Authenticated: %s\n", formatStatus(result.Authenticated))
+
+	if result.Identity != "" {
+		fmt.Fprintf(writer, "  Identity:      %s\n", cli.RedactSecrets(result.Identity, rawURL))
+	}
+
+	if err != nil {
+		fmt.Fprintf(writer, "  Error:         %s\n", cli.RedactSecrets(err.Error(), rawURL))
+	}
+
+	switch {
+	case !result.Reachable:
+		return cli.ExUnavailable
+	case !result.Authenticated || err != nil:
+		return cli.ExConfig
+	default:
+		return cli.ExSuccess
+	}
+}
+
+// formatStatus returns a colored yes or no for the status.
+func formatStatus(status bool) string {
+	if status {
+		return color.GreenString("yes")
+	}
+
+	return color.RedString("no")
+}
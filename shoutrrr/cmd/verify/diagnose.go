@@ -0,0 +1,112 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+	"github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/nicholas-fedor/shoutrrr/pkg/util"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
+)
+
+// reportError writes a description of why the URL could not be used, with secrets from the URL redacted.
+// When the service was found, the invalid prop or any missing required fields of its config are listed.
+func reportError(writer io.Writer, err error, service types.Service, rawURL string) {
+	fmt.Fprintf(writer, "error verifying URL: %s\n", describeError(err, rawURL))
+
+	if service == nil {
+		return
+	}
+
+	configNode := format.GetServiceConfigFormat(service)
+
+	var propErr *format.PropError
+	if errors.As(err, &propErr) {
+		if field := findField(configNode, propErr.Key); field != nil {
+			fmt.Fprintf(writer, "  %s (key: %s): %s\n", field.Name, propErr.Key, field.Description)
+		}
+	}
+
+	// An invalid prop stops the parsing of the URL, so the fields that follow it have not been set yet
+	if propErr != nil || errors.Is(err, format.ErrInvalidConfigKey) {
+		return
+	}
+
+	for _, field := range missingFields(configNode) {
+		fmt.Fprintf(writer, "  missing required field: %s%s\n", field.Name, formatURLParts(field.URLParts))
+	}
+}
+
+// describeError returns the error message with secrets from the URL redacted.
+// Unknown services get a suggestion for the closest known service, and URL parse errors omit the URL itself.
+func describeError(err error, rawURL string) string {
+	switch {
+	case errors.Is(err, router.ErrParseURLFailed):
+		var urlErr *url.Error
+
+		_, parseErr := url.Parse(rawURL)
+		if errors.As(parseErr, &urlErr) {
+			return "invalid URL format: " + cli.RedactSecrets(urlErr.Err.Error(), rawURL)
+		}
+
+		return "invalid URL format"
+	case errors.Is(err, router.ErrUnknownService):
+		scheme, _, _ := strings.Cut(rawURL, ":")
+		scheme, _, _ = strings.Cut(scheme, "+")
+
+		serviceRouter := router.ServiceRouter{}
+		if suggestion, found := util.ClosestMatch(scheme, serviceRouter.ListServices()); found {
+			return fmt.Sprintf("unknown service %q, did you mean %q?", scheme, suggestion)
+		}
+
+		return fmt.Sprintf("unknown service %q", scheme)
+	default:
+		return cli.RedactSecrets(cli.ErrorDetail(err), rawURL)
+	}
+}
+
+// findField returns the info of the config field with the key, or nil if there is no such field.
+func findField(configNode *format.ContainerNode, key string) *format.FieldInfo {
+	for _, item := range configNode.Items {
+		field := item.Field()
+		for _, fieldKey := range field.Keys {
+			if strings.EqualFold(fieldKey, key) {
+				return field
+			}
+		}
+	}
+
+	return nil
+}
+
+// missingFields returns the required fields of the config that have not been set.
+func missingFields(configNode *format.ContainerNode) []*format.FieldInfo {
+	var missing []*format.FieldInfo
+
+	for _, item := range configNode.Items {
+		if valueNode, ok := item.(*format.ValueNode); ok && valueNode.Required && valueNode.Value == "" {
+			missing = append(missing, valueNode.Field())
+		}
+	}
+
+	return missing
+}
+
+// formatURLParts returns a description of where the field is set in the URL, if it is set from the URL.
+func formatURLParts(parts []format.URLPart) string {
+	if len(parts) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(parts))
+	for _, part := range parts {
+		names = append(names, part.String())
+	}
+
+	return fmt.Sprintf(" (URL %s)", strings.Join(names, ", "))
+}
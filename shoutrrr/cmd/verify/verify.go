@@ -3,7 +3,6 @@ package verify
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -11,6 +10,7 @@ import (
 	internalUtil "github.com/nicholas-fedor/shoutrrr/internal/util"
 	"github.com/nicholas-fedor/shoutrrr/pkg/format"
 	"github.com/nicholas-fedor/shoutrrr/pkg/router"
+	cli "github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
 )
 
 // Cmd verifies the validity of a service url.
@@ -27,17 +27,19 @@ var serviceRouter router.ServiceRouter
 func init() {
 	Cmd.Flags().StringP("url", "u", "", "The notification url")
 	_ = Cmd.MarkFlagRequired("url")
+	Cmd.Flags().
+		Bool("check", false, "Check that the service is reachable and accepts the credentials, without sending a notification")
 }
 
 // Run the verify command.
 func Run(cmd *cobra.Command, _ []string) {
 	URL, _ := cmd.Flags().GetString("url")
+	check, _ := cmd.Flags().GetBool("check")
 	serviceRouter = router.ServiceRouter{}
 
 	service, err := serviceRouter.Locate(URL)
 	if err != nil {
-		wrappedErr := fmt.Errorf("locating service for URL: %w", err)
-		fmt.Fprint(os.Stdout, "error verifying URL: ", sanitizeError(wrappedErr), "\n")
+		reportError(os.Stdout, err, service, URL)
 		os.Exit(1)
 	}
 
@@ -45,19 +47,10 @@ func Run(cmd *cobra.Command, _ []string) {
 	configNode := format.GetConfigFormat(config)
 
 	fmt.Fprint(color.Output, format.ColorFormatTree(configNode, true))
-}
-
-// sanitizeError removes sensitive details from an error message.
-func sanitizeError(err error) string {
-	errStr := err.Error()
-	// Check for common error patterns without exposing URL details
-	if strings.Contains(errStr, "unknown service") {
-		return "service not recognized"
-	}
 
-	if strings.Contains(errStr, "parse") || strings.Contains(errStr, "invalid") {
-		return "invalid URL format"
+	if check {
+		if exitCode := CheckService(color.Output, service, URL); exitCode != cli.ExSuccess {
+			os.Exit(exitCode)
+		}
 	}
-	// Fallback for other errors
-	return "unable to process URL"
 }
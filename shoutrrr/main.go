@@ -10,6 +10,7 @@ import (
 	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd"
 	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/docs"
 	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/generate"
+	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/normalize"
 	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/send"
 	"github.com/nicholas-fedor/shoutrrr/shoutrrr/cmd/verify"
 )
@@ -25,6 +26,7 @@ func init() {
 	cobraCmd.AddCommand(generate.Cmd)
 	cobraCmd.AddCommand(send.Cmd)
 	cobraCmd.AddCommand(docs.Cmd)
+	cobraCmd.AddCommand(normalize.Cmd)
 
 	cobraCmd.Version = meta.GetMetaStr()
 }